@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/app"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/config"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+var converter = &roman.BasicRomanConverter{}
+
+// parseFlags parses the flags of a command. The flag package already
+// reports invalid flags, so the returned usageError has no message.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err == nil || err == flag.ErrHelp {
+		return err
+	}
+	return usageError{}
+}
+
+// formatFlag defines the -format flag on fs.
+func formatFlag(fs *flag.FlagSet) *string {
+	return fs.String("format", formatText, "output format: text, json or csv")
+}
+
+// readInputs calls fn with every argument or, if there are none, with
+// every non-empty line of stdin.
+func readInputs(args []string, stdin io.Reader, fn func(input string) error) error {
+	if len(args) > 0 {
+		for _, arg := range args {
+			if err := fn(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// splitInput splits a comma separated input into trimmed items.
+func splitInput(input string) []string {
+	items := strings.Split(input, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
+
+// runToRoman converts numbers to Roman numerals, in the order given.
+func runToRoman(c *cli, fs *flag.FlagSet, args []string) error {
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	w, err := newResultWriter(*format, c.stdout, conversionHeader)
+	if err != nil {
+		return err
+	}
+
+	err = readInputs(fs.Args(), c.stdin, func(input string) error {
+		numbers, invalidNumbers := roman.ParseNumberList([]string{input})
+		if len(invalidNumbers) > 0 {
+			return &inputError{err: roman.NewAppError(roman.CodeInvalidInput), input: invalidNumbers[0]}
+		}
+		for _, number := range numbers {
+			numeral, err := converter.Convert(number)
+			if err != nil {
+				return &inputError{err: err, input: strconv.Itoa(number)}
+			}
+			if err := w.write(conversion{RomanNumeral: types.RomanNumeral{Decimal: uint(number), Roman: numeral}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return closeWriter(w, err)
+}
+
+// runFromRoman converts Roman numerals to numbers, in the order given.
+func runFromRoman(c *cli, fs *flag.FlagSet, args []string) error {
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	w, err := newResultWriter(*format, c.stdout, conversionHeader)
+	if err != nil {
+		return err
+	}
+
+	err = readInputs(fs.Args(), c.stdin, func(input string) error {
+		for _, numeral := range splitInput(input) {
+			number, err := roman.ParseRoman(numeral)
+			if err != nil {
+				return &inputError{err: err, input: numeral}
+			}
+			result := conversion{RomanNumeral: types.RomanNumeral{Decimal: uint(number), Roman: strings.ToUpper(numeral)}, fromRoman: true}
+			if err := w.write(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return closeWriter(w, err)
+}
+
+// parseRange parses a range such as "1-10".
+func parseRange(input string) (types.NumberRange, error) {
+	minValue, maxValue, found := strings.Cut(input, "-")
+	min, minErr := strconv.Atoi(strings.TrimSpace(minValue))
+	max, maxErr := strconv.Atoi(strings.TrimSpace(maxValue))
+	if !found || minErr != nil || maxErr != nil {
+		return types.NumberRange{}, &inputError{err: roman.NewAppError(roman.CodeInValidRangeMissingMinMax), input: input}
+	}
+	return types.NumberRange{Min: min, Max: max}, nil
+}
+
+// runRange converts every number of the given ranges like the POST
+// endpoint, within the same limits.
+func runRange(c *cli, fs *flag.FlagSet, args []string) error {
+	cfg := config.FromEnv()
+	cfg.RegisterLimitFlags(fs)
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	w, err := newResultWriter(*format, c.stdout, conversionHeader)
+	if err != nil {
+		return err
+	}
+
+	var payload types.RangesPayload
+	err = readInputs(fs.Args(), c.stdin, func(input string) error {
+		for _, item := range splitInput(input) {
+			r, err := parseRange(item)
+			if err != nil {
+				return err
+			}
+			payload.Ranges = append(payload.Ranges, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(payload.Ranges) == 0 {
+		return usageError{"range: no ranges given"}
+	}
+
+	if err := roman.CheckRangeLimits(payload, cfg.Limits); err != nil {
+		if limitErr, ok := err.(*roman.LimitError); ok {
+			return limitError(limitErr)
+		}
+		return err
+	}
+	numbers, err := roman.ProcessRanges(payload)
+	if err != nil {
+		return err
+	}
+	for _, result := range roman.ConvertNumbersToRomanNumerals(numbers) {
+		if err := w.write(conversion{RomanNumeral: result}); err != nil {
+			return err
+		}
+	}
+	return w.close()
+}
+
+// isDecimal reports whether input looks like a number rather than a
+// Roman numeral.
+func isDecimal(input string) bool {
+	input = strings.TrimPrefix(strings.TrimSpace(input), "+")
+	return input != "" && strings.Trim(input, "0123456789") == ""
+}
+
+// validateInput checks a number or Roman numeral.
+func validateInput(input string) error {
+	if isDecimal(input) {
+		if _, invalidNumbers := roman.ParseNumberList([]string{input}); len(invalidNumbers) > 0 {
+			return roman.NewAppError(roman.CodeInvalidInput)
+		}
+		return nil
+	}
+	_, err := roman.ParseRoman(input)
+	return err
+}
+
+// runValidate reports whether every input is valid, failing with the
+// error of the first invalid one.
+func runValidate(c *cli, fs *flag.FlagSet, args []string) error {
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	w, err := newResultWriter(*format, c.stdout, validationHeader)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	err = readInputs(fs.Args(), c.stdin, func(input string) error {
+		for _, item := range splitInput(input) {
+			result := validation{Input: item, Valid: true}
+			if err := validateInput(item); err != nil {
+				result.Valid, result.Error = false, err.Error()
+				if firstErr == nil {
+					firstErr = &inputError{err: err, input: item}
+				}
+			}
+			if err := w.write(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err := closeWriter(w, err); err != nil {
+		return err
+	}
+	return firstErr
+}
+
+// runServe starts the HTTP server, configured like the server binary.
+func runServe(_ *cli, fs *flag.FlagSet, args []string) error {
+	cfg := config.FromEnv()
+	cfg.RegisterFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageError{"serve: unexpected arguments " + strings.Join(fs.Args(), " ")}
+	}
+	return app.Run(cfg)
+}
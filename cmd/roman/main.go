@@ -0,0 +1,142 @@
+// Command roman converts numbers to Roman numerals and back without a
+// running server, and can start the server itself.
+//
+// Usage:
+//
+//	roman <command> [flags] [inputs...]
+//
+// Inputs are read from the arguments or, if there are none, from stdin
+// line by line. Invalid input makes roman exit with status 1 and print
+// the AppError code, e.g. "[ERR1002]", on stderr.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+)
+
+// Exit statuses of the command.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// cli holds the streams the commands read from and write to.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// command is a subcommand of roman.
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(c *cli, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{"to-roman", "to-roman [-format text|json|csv] [numbers...]", "Convert comma separated numbers to Roman numerals.", runToRoman},
+	{"from-roman", "from-roman [-format text|json|csv] [numerals...]", "Convert comma separated Roman numerals to numbers.", runFromRoman},
+	{"range", "range [-format text|json|csv] [-max-ranges n] [ranges...]", "Convert comma separated MIN-MAX ranges to a sorted list of unique Roman numerals.", runRange},
+	{"validate", "validate [-format text|json|csv] [inputs...]", "Check that numbers and Roman numerals are valid.", runValidate},
+	{"serve", "serve [-port n] [-mode release] [flags]", "Start the HTTP server.", runServe},
+}
+
+func main() {
+	c := &cli{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	os.Exit(c.run(os.Args[1:]))
+}
+
+// run runs the command given by args and returns the exit status.
+func (c *cli) run(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		c.usage()
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		fs.SetOutput(c.stderr)
+		fs.Usage = func() {
+			fmt.Fprintf(c.stderr, "usage: roman %s\n\n%s\n\n", cmd.usage, cmd.description)
+			fs.PrintDefaults()
+		}
+		return c.exitStatus(cmd.run(c, fs, args[1:]))
+	}
+
+	fmt.Fprintf(c.stderr, "roman: unknown command %q\n\n", args[0])
+	c.usage()
+	return exitUsage
+}
+
+// exitStatus reports err on stderr and returns the matching exit status.
+func (c *cli) exitStatus(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, new(usageError)):
+		if err.Error() != "" {
+			fmt.Fprintf(c.stderr, "roman: %v\n", err)
+		}
+		return exitUsage
+	}
+	fmt.Fprintf(c.stderr, "roman: %v\n", err)
+	return exitFailure
+}
+
+// usage prints the list of commands.
+func (c *cli) usage() {
+	var b strings.Builder
+	b.WriteString("usage: roman <command> [flags] [inputs...]\n\n")
+	b.WriteString("Inputs are read from the arguments, or from stdin line by line.\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "  %-11s %s\n", cmd.name, cmd.description)
+	}
+	b.WriteString("\nRun 'roman <command> -h' for the flags of a command.\n")
+	fmt.Fprint(c.stderr, b.String())
+}
+
+// usageError is returned for invalid flags and arguments.
+type usageError struct {
+	message string
+}
+
+func (e usageError) Error() string {
+	return e.message
+}
+
+// inputError reports the input an AppError was found in.
+type inputError struct {
+	err   error
+	input string
+}
+
+func (e *inputError) Error() string {
+	return fmt.Sprintf("%v: %q", e.err, e.input)
+}
+
+func (e *inputError) Unwrap() error {
+	return e.err
+}
+
+// limitError reports the limit a LimitError exceeded.
+func limitError(err *roman.LimitError) error {
+	return fmt.Errorf("%w (limit %d, requested %d)", err, err.Limit, err.Requested)
+}
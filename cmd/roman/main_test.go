@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+)
+
+// runCLI runs roman with args and stdin, returning the exit status,
+// stdout and stderr.
+func runCLI(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	c := &cli{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr}
+	status := c.run(args)
+	return status, stdout.String(), stderr.String()
+}
+
+func TestToRoman(t *testing.T) {
+	status, stdout, stderr := runCLI("", "to-roman", "1, 4", "+1994")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "I\nIV\nMCMXCIV\n", stdout)
+	assert.Empty(t, stderr)
+
+	// Inputs are read from stdin line by line without arguments
+	status, stdout, _ = runCLI("42\n\n3999\n", "to-roman", "-format", "csv")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "number,roman\n42,XLII\n3999,MMMCMXCIX\n", stdout)
+}
+
+func TestFromRoman(t *testing.T) {
+	status, stdout, _ := runCLI("xlii\nMCMXCIV, IV\n", "from-roman")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "42\n1994\n4\n", stdout)
+
+	status, stdout, _ = runCLI("", "from-roman", "-format", "json", "XLII")
+	assert.Equal(t, exitOK, status)
+	assert.JSONEq(t, `{"results": [{"number": 42, "roman": "XLII"}]}`, stdout)
+}
+
+func TestRange(t *testing.T) {
+	status, stdout, _ := runCLI("", "range", "3-4,2-5")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "II\nIII\nIV\nV\n", stdout, "results should be unique and sorted like the API")
+
+	status, stdout, _ = runCLI("10-11\n1-1\n", "range", "-format", "json")
+	assert.Equal(t, exitOK, status)
+	assert.JSONEq(t, `{"results": [{"number": 1, "roman": "I"}, {"number": 10, "roman": "X"}, {"number": 11, "roman": "XI"}]}`, stdout)
+}
+
+func TestRangeLimits(t *testing.T) {
+	status, stdout, stderr := runCLI("", "range", "-max-ranges", "1", "1-2,3-4")
+	assert.Equal(t, exitFailure, status)
+	assert.Empty(t, stdout)
+	assert.Equal(t, "roman: "+roman.NewAppError(roman.CodeTooManyRanges).Error()+" (limit 1, requested 2)\n", stderr)
+
+	// The limits are read from the environment like the server's
+	t.Setenv("MAX_RANGE_SPAN", "5")
+	status, _, stderr = runCLI("", "range", "1-10")
+	assert.Equal(t, exitFailure, status)
+	assert.Contains(t, stderr, roman.CodeRangeSpanTooLarge)
+}
+
+func TestValidate(t *testing.T) {
+	status, stdout, stderr := runCLI("", "validate", "XLII,42")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "XLII: valid\n42: valid\n", stdout)
+	assert.Empty(t, stderr)
+
+	status, stdout, stderr = runCLI("IIII\n4000\nIV\n", "validate", "-format", "csv")
+	assert.Equal(t, exitFailure, status)
+	assert.Equal(t, "input,valid,error\n"+
+		"IIII,false,[ERR1019] invalid Roman numeral: expected a numeral between I and MMMCMXCIX in standard notation\n"+
+		"4000,false,[ERR1002] invalid input: please provide valid integers within the supported range (1-3999)\n"+
+		"IV,true,\n", stdout)
+	assert.Contains(t, stderr, "[ERR1019]", "the first invalid input should be reported")
+}
+
+func TestInvalidInput(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectedCode string
+	}{
+		{"ToRomanOutOfRange", []string{"to-roman", "1", "4000"}, roman.CodeInvalidInput},
+		{"ToRomanNotANumber", []string{"to-roman", "X"}, roman.CodeInvalidInput},
+		{"FromRomanNonCanonical", []string{"from-roman", "IIII"}, roman.CodeInvalidRomanNumeral},
+		{"RangeFormat", []string{"range", "1..10"}, roman.CodeInValidRangeMissingMinMax},
+		{"RangeReversed", []string{"range", "10-1"}, roman.CodeInvalidRangeMinMoreMax},
+		{"RangeBounds", []string{"range", "0-10"}, roman.CodeInvalidRangeBounds},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, _, stderr := runCLI("", test.args...)
+			assert.Equal(t, exitFailure, status)
+			assert.True(t, strings.HasPrefix(stderr, "roman: ["+test.expectedCode+"]"), "unexpected stderr %q", stderr)
+		})
+	}
+
+	// Results before the invalid input are still written
+	status, stdout, _ := runCLI("", "to-roman", "1", "4000")
+	assert.Equal(t, exitFailure, status)
+	assert.Equal(t, "I\n", stdout)
+
+	// but not as incomplete JSON
+	status, stdout, _ = runCLI("", "to-roman", "-format", "json", "1", "4000")
+	assert.Equal(t, exitFailure, status)
+	assert.Empty(t, stdout)
+}
+
+func TestUsage(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedStatus int
+		expectedStderr string
+	}{
+		{"NoCommand", nil, exitUsage, "usage: roman <command>"},
+		{"Help", []string{"help"}, exitOK, "usage: roman <command>"},
+		{"UnknownCommand", []string{"to-greek"}, exitUsage, `unknown command "to-greek"`},
+		{"UnknownFlag", []string{"to-roman", "-x"}, exitUsage, "flag provided but not defined: -x"},
+		{"CommandHelp", []string{"range", "-h"}, exitOK, "-max-ranges"},
+		{"InvalidFormat", []string{"to-roman", "-format", "yaml", "1"}, exitUsage, `invalid format "yaml"`},
+		{"NoRanges", []string{"range"}, exitUsage, "no ranges given"},
+		{"ServeArguments", []string{"serve", "extra"}, exitUsage, "unexpected arguments extra"},
+		{"ServeInvalidMode", []string{"serve", "-mode", "production"}, exitFailure, "invalid mode 'production'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, _, stderr := runCLI("", test.args...)
+			assert.Equal(t, test.expectedStatus, status)
+			assert.Contains(t, stderr, test.expectedStderr)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+// Output formats of the conversion commands.
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatCSV  = "csv"
+)
+
+var (
+	conversionHeader = []string{"number", "roman"}
+	validationHeader = []string{"input", "valid", "error"}
+)
+
+// result is a line of output.
+type result interface {
+	// text returns the result in the text format.
+	text() string
+	// fields returns the CSV fields of the result.
+	fields() []string
+}
+
+// conversion is the result of converting a number or a Roman numeral.
+type conversion struct {
+	types.RomanNumeral
+	// fromRoman is set for conversions of Roman numerals, whose text
+	// output is the number.
+	fromRoman bool
+}
+
+func (c conversion) text() string {
+	if c.fromRoman {
+		return strconv.Itoa(int(c.Decimal))
+	}
+	return c.Roman
+}
+
+func (c conversion) fields() []string {
+	return []string{strconv.Itoa(int(c.Decimal)), c.Roman}
+}
+
+// validation is the result of validating an input.
+type validation struct {
+	Input string `json:"input"`
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+func (v validation) text() string {
+	if v.Valid {
+		return v.Input + ": valid"
+	}
+	return v.Input + ": " + v.Error
+}
+
+func (v validation) fields() []string {
+	return []string{v.Input, strconv.FormatBool(v.Valid), v.Error}
+}
+
+// resultWriter writes results in one of the output formats.
+type resultWriter interface {
+	write(r result) error
+	// flush writes the results buffered so far, if the format allows it.
+	flush() error
+	// close completes the output.
+	close() error
+}
+
+// newResultWriter returns a writer of format to w. header names the CSV columns.
+func newResultWriter(format string, w io.Writer, header []string) (resultWriter, error) {
+	switch format {
+	case formatText:
+		return &textWriter{w: w}, nil
+	case formatJSON:
+		return &jsonWriter{w: w}, nil
+	case formatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: writer}, nil
+	}
+	return nil, usageError{fmt.Sprintf("invalid format %q: expected %s, %s or %s", format, formatText, formatJSON, formatCSV)}
+}
+
+// closeWriter completes the output of w if err is nil. Otherwise it only
+// writes the results buffered so far, and returns err.
+func closeWriter(w resultWriter, err error) error {
+	if err != nil {
+		_ = w.flush()
+		return err
+	}
+	return w.close()
+}
+
+// textWriter writes a result per line as soon as it is written.
+type textWriter struct {
+	w io.Writer
+}
+
+func (t *textWriter) write(r result) error {
+	_, err := fmt.Fprintln(t.w, r.text())
+	return err
+}
+
+func (t *textWriter) flush() error { return nil }
+func (t *textWriter) close() error { return nil }
+
+// csvWriter writes a CSV record per result after a header.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) write(r result) error {
+	if err := c.w.Write(r.fields()); err != nil {
+		return err
+	}
+	// Keep the output streaming when reading from stdin
+	return c.flush()
+}
+
+func (c *csvWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) close() error {
+	return c.flush()
+}
+
+// jsonWriter writes the results as a single {"results": [...]} object,
+// like the responses of the API. Nothing is written for failed commands.
+type jsonWriter struct {
+	w       io.Writer
+	results []result
+}
+
+func (j *jsonWriter) write(r result) error {
+	j.results = append(j.results, r)
+	return nil
+}
+
+func (j *jsonWriter) flush() error { return nil }
+
+func (j *jsonWriter) close() error {
+	results := j.results
+	if results == nil {
+		results = []result{}
+	}
+	encoder := json.NewEncoder(j.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]interface{}{"results": results})
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"net/http"
-	"os"
 	"testing"
 	"time"
 
@@ -31,31 +30,3 @@ func TestServerStarts(t *testing.T) {
 	// Check if the server responds with a success status code
 	assert.Equal(t, http.StatusOK, resp.StatusCode, "Server did not respond with expected status code")
 }
-
-func TestGetPort(t *testing.T) {
-	// Save the original PORT value and defer restoration
-	originalPort := os.Getenv("PORT")
-	defer os.Setenv("PORT", originalPort)
-
-	tests := []struct {
-		envPort      string
-		expectedPort int
-	}{
-		{"8080", 8080},    // Valid port number
-		{"invalid", 8001}, // Invalid port number
-		{"", 8001},        // No port set, should use default
-	}
-
-	for _, test := range tests {
-		// Set the PORT environment variable
-		os.Setenv("PORT", test.envPort)
-
-		// Get the port using the function
-		port := getPort()
-
-		// Check if the returned port matches the expected port
-		if port != test.expectedPort {
-			t.Errorf("Expected port %d, but got %d", test.expectedPort, port)
-		}
-	}
-}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	swaggerfiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+
+	docs "github.com/mrtyormaa/decimal-to-roman-numerals/docs"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/openapi"
+)
+
+// DocsConfig defines how the API documentation is served: Swagger UI at
+// /swagger/, ReDoc at /redoc and the OpenAPI document at /openapi.json.
+type DocsConfig struct {
+	// Enabled serves the documentation.
+	Enabled bool
+	// EnabledInRelease also serves the documentation in release mode.
+	EnabledInRelease bool
+	// ReDoc serves the ReDoc view of the OpenAPI document next to
+	// Swagger UI.
+	ReDoc bool
+	// Username and Password protect the documentation with HTTP basic
+	// authentication when both are set.
+	Username string
+	Password string
+	// Host is the host of the API in the Swagger document, e.g.
+	// "api.example.com". The host of each request is used when it is
+	// empty.
+	Host string
+	// ContentSecurityPolicy replaces the Content-Security-Policy header
+	// of the API on the documentation pages, which need inline scripts
+	// and styles and ReDoc from its CDN. The header of the API is kept
+	// when it is empty.
+	ContentSecurityPolicy string
+}
+
+// DocsContentSecurityPolicy is the default Content-Security-Policy of the
+// documentation: Swagger UI is initialized by an inline script and styled
+// inline, and ReDoc is loaded from cdn.redoc.ly and renders in workers.
+const DocsContentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' 'unsafe-inline' https://cdn.redoc.ly; " +
+	"style-src 'self' 'unsafe-inline'; " +
+	"img-src 'self' data: https://cdn.redoc.ly; " +
+	"worker-src 'self' blob:"
+
+// DefaultDocsConfig returns the configuration used when nothing has been
+// set: the documentation is served without authentication in every mode.
+func DefaultDocsConfig() DocsConfig {
+	return DocsConfig{
+		Enabled:               true,
+		EnabledInRelease:      true,
+		ReDoc:                 true,
+		ContentSecurityPolicy: DocsContentSecurityPolicy,
+	}
+}
+
+// DocsConfigFromEnv reads the configuration from DOCS_ENABLED,
+// DOCS_ENABLED_IN_RELEASE, DOCS_REDOC, DOCS_USERNAME, DOCS_PASSWORD,
+// DOCS_HOST and DOCS_CSP. Setting DOCS_CSP to an empty value keeps the
+// header of the API. Unset or invalid values fall back to
+// DefaultDocsConfig.
+func DocsConfigFromEnv() DocsConfig {
+	cfg := DefaultDocsConfig()
+	if value, err := strconv.ParseBool(os.Getenv("DOCS_ENABLED")); err == nil {
+		cfg.Enabled = value
+	}
+	if value, err := strconv.ParseBool(os.Getenv("DOCS_ENABLED_IN_RELEASE")); err == nil {
+		cfg.EnabledInRelease = value
+	}
+	if value, err := strconv.ParseBool(os.Getenv("DOCS_REDOC")); err == nil {
+		cfg.ReDoc = value
+	}
+	cfg.Username = os.Getenv("DOCS_USERNAME")
+	cfg.Password = os.Getenv("DOCS_PASSWORD")
+	cfg.Host = os.Getenv("DOCS_HOST")
+	if value, ok := os.LookupEnv("DOCS_CSP"); ok {
+		cfg.ContentSecurityPolicy = value
+	}
+	return cfg
+}
+
+// served reports whether the documentation is served in the given gin mode.
+func (cfg DocsConfig) served(mode string) bool {
+	return cfg.Enabled && (mode != gin.ReleaseMode || cfg.EnabledInRelease)
+}
+
+// protected reports whether the documentation requires authentication.
+func (cfg DocsConfig) protected() bool {
+	return cfg.Username != "" && cfg.Password != ""
+}
+
+// Report describes the configuration in the given gin mode.
+func (cfg DocsConfig) Report(mode string) string {
+	if !cfg.served(mode) {
+		return "docs: disabled"
+	}
+	if cfg.protected() {
+		return "docs: enabled, basic authentication"
+	}
+	return "docs: enabled, public"
+}
+
+func docsConfig(opts Options) DocsConfig {
+	if opts.Docs == nil {
+		return DefaultDocsConfig()
+	}
+	return *opts.Docs
+}
+
+// registerDocs serves the documentation configured by cfg. The root
+// endpoint redirects to Swagger UI when it is served.
+func registerDocs(r *gin.Engine, cfg DocsConfig) {
+	if !cfg.served(gin.Mode()) {
+		return
+	}
+
+	group := r.Group("")
+	if cfg.ContentSecurityPolicy != "" {
+		group.Use(contentSecurityPolicy(cfg.ContentSecurityPolicy))
+	}
+	if cfg.protected() {
+		group.Use(basicAuth(cfg.Username, cfg.Password, "API documentation"))
+	}
+
+	swagger := ginSwagger.WrapHandler(swaggerfiles.Handler)
+	group.GET("/swagger/*any", func(c *gin.Context) {
+		switch c.Param("any") {
+		case "", "/":
+			// The handler only serves known files, so /swagger/ used to be
+			// a 404 (issue #10)
+			c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
+		case "/doc.json":
+			serveSwaggerDoc(c, cfg.Host)
+		default:
+			swagger(c)
+		}
+	})
+	group.GET("/openapi.json", openapi.Handler())
+	if cfg.ReDoc {
+		group.GET("/redoc", redoc)
+	}
+
+	// Root endpoint to display message to use API version
+	group.GET("/", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
+	})
+}
+
+// serveSwaggerDoc serves the Swagger document for host, or for the host
+// the request was sent to. The host of the generated docs.SwaggerInfo is
+// replaced at runtime on a copy, so that regenerating the docs with swag
+// keeps working.
+func serveSwaggerDoc(c *gin.Context, host string) {
+	if host == "" {
+		host = c.Request.Host
+	}
+	spec := *docs.SwaggerInfo
+	spec.Host = host
+	c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(spec.ReadDoc()))
+}
+
+// redocPage renders /openapi.json with ReDoc.
+const redocPage = `<!DOCTYPE html>
+<html>
+  <head>
+    <title>Roman Numeral Converter API</title>
+    <meta charset="utf-8"/>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+  </head>
+  <body>
+    <redoc spec-url="/openapi.json"></redoc>
+    <script src="https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"></script>
+  </body>
+</html>
+`
+
+func redoc(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(redocPage))
+}
+
+// contentSecurityPolicy replaces the Content-Security-Policy header set
+// by the security middleware with policy.
+func contentSecurityPolicy(policy string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Security-Policy", policy)
+		c.Next()
+	}
+}
+
+// basicAuth requires the username and password with HTTP basic
+// authentication, comparing them in constant time.
+func basicAuth(username, password, realm string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, pass, ok := c.Request.BasicAuth()
+		userMatch := subtle.ConstantTimeCompare([]byte(user), []byte(username)) == 1
+		passMatch := subtle.ConstantTimeCompare([]byte(pass), []byte(password)) == 1
+		if !ok || !userMatch || !passMatch {
+			c.Header("WWW-Authenticate", `Basic realm="`+strings.ReplaceAll(realm, `"`, "")+`", charset="UTF-8"`)
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
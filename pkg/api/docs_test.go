@@ -0,0 +1,144 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api"
+)
+
+func serveDocs(router *gin.Engine, target string, configure func(*http.Request)) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if configure != nil {
+		configure(req)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestDocsRoutes(t *testing.T) {
+	router := api.InitRouter()
+
+	// Issue #10: /swagger/ redirects to Swagger UI
+	resp := serveDocs(router, "/swagger/", nil)
+	assert.Equal(t, http.StatusMovedPermanently, resp.Code)
+	assert.Equal(t, "/swagger/index.html", resp.Header().Get("Location"))
+	resp = serveDocs(router, "/swagger", nil)
+	assert.Equal(t, http.StatusMovedPermanently, resp.Code)
+	assert.Equal(t, "/swagger/", resp.Header().Get("Location"))
+
+	for _, target := range []string{"/swagger/index.html", "/swagger/doc.json", "/openapi.json", "/redoc"} {
+		assert.Equal(t, http.StatusOK, serveDocs(router, target, nil).Code, target)
+	}
+	assert.Contains(t, serveDocs(router, "/redoc", nil).Body.String(), `<redoc spec-url="/openapi.json">`)
+}
+
+func TestDocsHost(t *testing.T) {
+	host := func(router *gin.Engine) string {
+		resp := serveDocs(router, "/swagger/doc.json", func(req *http.Request) { req.Host = "api.example.com:8443" })
+		require.Equal(t, http.StatusOK, resp.Code)
+		var doc struct {
+			Host     string `json:"host"`
+			BasePath string `json:"basePath"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &doc))
+		assert.Equal(t, "/api/v1", doc.BasePath)
+		return doc.Host
+	}
+
+	// The host comes from the request, unless it is configured
+	assert.Equal(t, "api.example.com:8443", host(api.InitRouter()))
+	assert.Equal(t, "roman.example.com", host(api.NewRouter(api.Options{Docs: &api.DocsConfig{Enabled: true, Host: "roman.example.com"}})))
+}
+
+func TestDocsDisabled(t *testing.T) {
+	disabled := []string{"/", "/swagger/index.html", "/swagger/doc.json", "/openapi.json", "/redoc"}
+
+	router := api.NewRouter(api.Options{Docs: &api.DocsConfig{Enabled: false, EnabledInRelease: true}})
+	for _, target := range disabled {
+		assert.Equal(t, http.StatusNotFound, serveDocs(router, target, nil).Code, target)
+	}
+	assert.Equal(t, http.StatusOK, serveDocs(router, "/api/v1/convert?numbers=1", nil).Code)
+
+	// ReDoc can be disabled on its own
+	router = api.NewRouter(api.Options{Docs: &api.DocsConfig{Enabled: true}})
+	assert.Equal(t, http.StatusNotFound, serveDocs(router, "/redoc", nil).Code)
+	assert.Equal(t, http.StatusOK, serveDocs(router, "/swagger/index.html", nil).Code)
+}
+
+func TestDocsReleaseMode(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.TestMode)
+
+	router := api.NewRouter(api.Options{Docs: &api.DocsConfig{Enabled: true}})
+	assert.Equal(t, http.StatusNotFound, serveDocs(router, "/swagger/index.html", nil).Code)
+	assert.Equal(t, http.StatusNotFound, serveDocs(router, "/openapi.json", nil).Code)
+	assert.Contains(t, api.SecurityReport(api.Options{Docs: &api.DocsConfig{Enabled: true}}), "docs: disabled")
+
+	router = api.NewRouter(api.Options{})
+	for _, target := range []string{"/swagger/index.html", "/redoc"} {
+		resp := serveDocs(router, target, nil)
+		assert.Equal(t, http.StatusOK, resp.Code, target)
+		csp := resp.Header().Get("Content-Security-Policy")
+		assert.Equal(t, api.DocsContentSecurityPolicy, csp, target)
+		assert.Contains(t, csp, "script-src 'self' 'unsafe-inline' https://cdn.redoc.ly", "the inline Swagger UI script and ReDoc should be allowed")
+	}
+	// The API keeps the strict policy
+	resp := serveDocs(router, "/api/v1/convert?numbers=1", nil)
+	assert.Equal(t, "default-src 'self'", resp.Header().Get("Content-Security-Policy"))
+
+	cfg := api.DefaultDocsConfig()
+	cfg.ContentSecurityPolicy = ""
+	router = api.NewRouter(api.Options{Docs: &cfg})
+	resp = serveDocs(router, "/redoc", nil)
+	assert.Equal(t, "default-src 'self'", resp.Header().Get("Content-Security-Policy"), "an empty policy should keep the header of the API")
+}
+
+func TestDocsAuthentication(t *testing.T) {
+	cfg := api.DefaultDocsConfig()
+	cfg.Username, cfg.Password = "docs", "secret"
+	router := api.NewRouter(api.Options{Docs: &cfg})
+	assert.Contains(t, api.SecurityReport(api.Options{Docs: &cfg}), "docs: enabled, basic authentication")
+
+	for _, target := range []string{"/", "/swagger/", "/swagger/index.html", "/openapi.json", "/redoc"} {
+		resp := serveDocs(router, target, nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code, target)
+		assert.Equal(t, `Basic realm="API documentation", charset="UTF-8"`, resp.Header().Get("WWW-Authenticate"), target)
+	}
+
+	resp := serveDocs(router, "/swagger/index.html", func(req *http.Request) { req.SetBasicAuth("docs", "wrong") })
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	resp = serveDocs(router, "/swagger/index.html", func(req *http.Request) { req.SetBasicAuth("docs", "secret") })
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// The API itself is not protected
+	assert.Equal(t, http.StatusOK, serveDocs(router, "/api/v1/convert?numbers=1", nil).Code)
+	assert.Equal(t, http.StatusOK, serveDocs(router, "/health", nil).Code)
+}
+
+func TestDocsConfigFromEnv(t *testing.T) {
+	assert.Equal(t, api.DefaultDocsConfig(), api.DocsConfigFromEnv())
+
+	t.Setenv("DOCS_ENABLED", "true")
+	t.Setenv("DOCS_ENABLED_IN_RELEASE", "false")
+	t.Setenv("DOCS_REDOC", "invalid")
+	t.Setenv("DOCS_USERNAME", "docs")
+	t.Setenv("DOCS_PASSWORD", "secret")
+	t.Setenv("DOCS_HOST", "roman.example.com")
+	t.Setenv("DOCS_CSP", "")
+	assert.Equal(t, api.DocsConfig{
+		Enabled:          true,
+		EnabledInRelease: false,
+		ReDoc:            true,
+		Username:         "docs",
+		Password:         "secret",
+		Host:             "roman.example.com",
+	}, api.DocsConfigFromEnv())
+}
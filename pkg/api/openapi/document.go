@@ -0,0 +1,196 @@
+// Package openapi describes the HTTP API as an OpenAPI 3.1 document,
+// served at /openapi.json, and checks requests and responses against it.
+//
+// The document is built from the code it describes: the error examples
+// are produced by the AppErrors and JSON validation of package roman, so
+// they cannot drift from the responses of the handlers. Validator checks
+// the traffic of tests against the document, so that handlers cannot
+// drift from it either.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Version is the OpenAPI version of the document.
+const Version = "3.1.0"
+
+// Document is an OpenAPI document, limited to the fields the API uses.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Version     string   `json:"version"`
+	Contact     *Contact `json:"contact,omitempty"`
+	License     *License `json:"license,omitempty"`
+}
+
+// Contact is the contact information of the API.
+type Contact struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// License is the license of the API.
+type License struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// Server is a URL the API is served at.
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem lists the operations of a path.
+type PathItem struct {
+	Get  *Operation `json:"get,omitempty"`
+	Post *Operation `json:"post,omitempty"`
+}
+
+// operation returns the operation of method, or nil.
+func (p *PathItem) operation(method string) *Operation {
+	switch method {
+	case "GET", "HEAD":
+		return p.Get
+	case "POST":
+		return p.Post
+	}
+	return nil
+}
+
+// Operation is an operation on a path.
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+}
+
+// Parameter is a query or header parameter of an operation.
+type Parameter struct {
+	Name        string              `json:"name"`
+	In          string              `json:"in"`
+	Description string              `json:"description,omitempty"`
+	Required    bool                `json:"required,omitempty"`
+	Schema      *Schema             `json:"schema"`
+	Examples    map[string]*Example `json:"examples,omitempty"`
+}
+
+// RequestBody is the body of a request.
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+// Response is a response of an operation.
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// Header is a header of a response.
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType describes a body of a media type.
+type MediaType struct {
+	Schema   *Schema             `json:"schema"`
+	Examples map[string]*Example `json:"examples,omitempty"`
+}
+
+// Example is an example value, or a reference to one of the components.
+type Example struct {
+	Ref         string      `json:"$ref,omitempty"`
+	Summary     string      `json:"summary,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Value       interface{} `json:"value,omitempty"`
+}
+
+// Components holds the schemas and examples referenced by the operations.
+type Components struct {
+	Schemas  map[string]*Schema  `json:"schemas"`
+	Examples map[string]*Example `json:"examples"`
+}
+
+// Schema is a JSON Schema, limited to the keywords the API uses.
+type Schema struct {
+	Ref                  string        `json:"$ref,omitempty"`
+	Type                 string        `json:"type,omitempty"`
+	Description          string        `json:"description,omitempty"`
+	Format               string        `json:"format,omitempty"`
+	Enum                 []interface{} `json:"enum,omitempty"`
+	Pattern              string        `json:"pattern,omitempty"`
+	Minimum              *float64      `json:"minimum,omitempty"`
+	Maximum              *float64      `json:"maximum,omitempty"`
+	MinItems             *int          `json:"minItems,omitempty"`
+	MaxItems             *int          `json:"maxItems,omitempty"`
+	Items                *Schema       `json:"items,omitempty"`
+	Properties           Properties    `json:"properties,omitempty"`
+	Required             []string      `json:"required,omitempty"`
+	AdditionalProperties *bool         `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema     `json:"oneOf,omitempty"`
+	Examples             []interface{} `json:"examples,omitempty"`
+}
+
+// Property is a named property of an object schema.
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties lists the properties of an object schema. Unlike a map, it
+// keeps them in the order they are declared in, e.g. "min" before "max".
+type Properties []Property
+
+// MarshalJSON writes the properties as an object, in order.
+func (p Properties) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, property := range p {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, err := json.Marshal(property.Name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := json.Marshal(property.Schema)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(schema)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// lookup returns the schema of the property name, or nil.
+func (p Properties) lookup(name string) *Schema {
+	for _, property := range p {
+		if property.Name == name {
+			return property.Schema
+		}
+	}
+	return nil
+}
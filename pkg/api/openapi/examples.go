@@ -0,0 +1,546 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+// The paths of the conversion endpoints of every version, and of the
+// version negotiated with the Accept-Version header.
+const (
+	convertPath           = "/api/v1/convert"
+	convertPathV2         = "/api/v2/convert"
+	convertPathNegotiated = "/api/convert"
+	batchPath             = "/api/v1/batch"
+	batchPathV2           = "/api/v2/batch"
+)
+
+// exampleDurationMs is the duration_ms of the v2 examples, which differs
+// on every response.
+const exampleDurationMs = 0.042
+
+// example is a request to the conversion endpoints and its response.
+// The responses are produced by package roman, the same way the
+// handlers produce them.
+type example struct {
+	// name is the AppError code of error examples.
+	name    string
+	summary string
+	method  string
+	// path is the path of the request, convertPath if it is empty.
+	path string
+	// query is the encoded query string of the request.
+	query string
+	// header holds the headers of the request, e.g. Accept-Version.
+	header map[string]string
+	// body is the raw request body.
+	body   string
+	status int
+	// response is the body of the response.
+	response interface{}
+
+	// setup describes the server configuration the example needs.
+	setup        string
+	limits       roman.Limits
+	maxBodyBytes int64
+	timeout      time.Duration
+	// canceled examples are sent by clients that go away before the
+	// response, and unreadable ones have a body that fails to be read.
+	canceled   bool
+	unreadable bool
+}
+
+// requestPath returns the path of the request.
+func (e example) requestPath() string {
+	if e.path == "" {
+		return convertPath
+	}
+	return e.path
+}
+
+// target returns the path and query of the request.
+func (e example) target() string {
+	target := e.requestPath()
+	if e.query != "" {
+		target += "?" + e.query
+	}
+	return target
+}
+
+// description describes the request of the example.
+func (e example) description() string {
+	var b strings.Builder
+	b.WriteString(e.method + " " + e.target())
+	for _, name := range sortedKeys(e.header) {
+		b.WriteString(" with the header `" + name + ": " + e.header[name] + "`")
+	}
+	if e.body != "" {
+		b.WriteString(" with the body `" + e.body + "`")
+	}
+	if e.unreadable {
+		b.WriteString(" whose body fails to be read")
+	}
+	if e.setup != "" {
+		b.WriteString(", sent to a server started with " + e.setup)
+	}
+	if e.canceled {
+		b.WriteString(", canceled by the client before the response")
+	}
+	return b.String() + "."
+}
+
+// overlappingRanges is the body of the successful POST examples.
+const overlappingRanges = `{"ranges":[{"min":3,"max":4},{"min":2,"max":5}]}`
+
+// smallRangeSpan are the limits of the examples exceeding the span of a
+// range.
+var smallRangeSpan = func() roman.Limits {
+	limits := roman.DefaultLimits()
+	limits.MaxRangeSpan = 10
+	return limits
+}()
+
+// orderedRanges is the body of the POST example using every option.
+const orderedRanges = `{"ranges":[{"min":10,"max":30,"step":5,"exclude":[20]},{"min":24,"max":26}],"order":"desc","dedupe":false}`
+
+// successExamples describes successful conversions.
+var successExamples = []example{
+	{
+		name:     "Numbers",
+		summary:  "Convert numbers",
+		method:   http.MethodGet,
+		query:    "numbers=1994,4,+04",
+		status:   http.StatusOK,
+		response: types.RomanNumeralResponse{Results: roman.ConvertNumbersToRomanNumerals([]int{4, 1994})},
+	},
+	rangesExample("Ranges", "Convert overlapping ranges", overlappingRanges),
+	rangesExample("OrderedRanges", "Convert stepped ranges in descending order, keeping duplicates", orderedRanges),
+	{
+		name:     "DryRun",
+		summary:  "Count the results of ranges",
+		method:   http.MethodPost,
+		query:    "dry_run=true",
+		body:     overlappingRanges,
+		status:   http.StatusOK,
+		response: dryRunResponse(overlappingRanges),
+	},
+	{
+		name:     "DryRunOverLimits",
+		summary:  "Count the results of ranges exceeding a limit",
+		method:   http.MethodPost,
+		query:    "count_only=true",
+		body:     `{"ranges":[{"min":1,"max":100}]}`,
+		status:   http.StatusOK,
+		response: dryRunOverLimitsResponse(`{"ranges":[{"min":1,"max":100}]}`, smallRangeSpan),
+		setup:    "MAX_RANGE_SPAN=10",
+		limits:   smallRangeSpan,
+	},
+	{
+		name:     "LenientNumbers",
+		summary:  "Convert the valid numbers",
+		method:   http.MethodGet,
+		query:    lenientQuery,
+		status:   http.StatusOK,
+		response: lenientResponse(lenientQuery),
+	},
+	{
+		name:     "Batch",
+		summary:  "Run a batch of operations",
+		method:   http.MethodPost,
+		path:     batchPath,
+		body:     batchBody,
+		status:   http.StatusOK,
+		response: batchResponse(),
+	},
+}
+
+// errorExamples describes a request failing with every AppError code
+// the conversion endpoints return.
+var errorExamples = []example{
+	errorExample(roman.CodeInvalidParam, "Unknown query parameter", http.MethodGet, "numbers=1&format=xml", ""),
+	errorExample(roman.CodeMissingNumbersParam, "Missing numbers", http.MethodGet, "", ""),
+	errorExample(roman.CodeInvalidMode, "Unknown mode", http.MethodGet, "numbers=1&mode=partial", ""),
+	{
+		name:     roman.CodeInvalidInput,
+		summary:  "Invalid numbers",
+		method:   http.MethodGet,
+		query:    "numbers=1,4000,abc",
+		status:   http.StatusBadRequest,
+		response: invalidNumbersResponse("numbers=1,4000,abc"),
+	},
+	{
+		name:       roman.CodeFailedReadBody,
+		summary:    "Unreadable body",
+		method:     http.MethodPost,
+		status:     http.StatusBadRequest,
+		response:   errorResponse(roman.CodeFailedReadBody),
+		unreadable: true,
+	},
+	errorExample(roman.CodeInvalidRangeJSON, "Missing ranges", http.MethodPost, "", `{"numbers":[1,2]}`),
+	errorExample(roman.CodeInvalidJSONDuplicateKeys, "Duplicate keys", http.MethodPost, "", `{"ranges":[{"min":1,"min":2,"max":3}]}`),
+	errorExample(roman.CodeQueryParamInPostRequest, "Unknown query parameter", http.MethodPost, "format=xml", `{"ranges":[{"min":1,"max":2}]}`),
+	errorExample(roman.CodeInvalidRangeMinMoreMax, "Reversed range", http.MethodPost, "", `{"ranges":[{"min":10,"max":1}]}`),
+	errorExample(roman.CodeInvalidRangeBounds, "Range out of bounds", http.MethodPost, "", `{"ranges":[{"min":0,"max":10}]}`),
+	errorExample(roman.CodeInValidJSON, "Malformed JSON", http.MethodPost, "", `{"ranges":[{"min":1,"max":2}`),
+	errorExample(roman.CodeInValidRangeMissingMinMax, "Missing max", http.MethodPost, "", `{"ranges":[{"min":1}]}`),
+	errorExample(roman.CodeInvalidRangeStep, "Invalid step", http.MethodPost, "", `{"ranges":[{"min":1,"max":10,"step":0}]}`),
+	errorExample(roman.CodeInvalidRangeExclude, "Excluded number out of bounds", http.MethodPost, "", `{"ranges":[{"min":1,"max":10,"exclude":[0]}]}`),
+	errorExample(roman.CodeInvalidOrder, "Unknown order", http.MethodPost, "", `{"ranges":[{"min":1,"max":10}],"order":"random"}`),
+	errorExample(roman.CodeInvalidDedupe, "Invalid dedupe", http.MethodPost, "", `{"ranges":[{"min":1,"max":10}],"dedupe":"no"}`),
+	{
+		name:         roman.CodeRequestBodyTooLarge,
+		summary:      "Body too large",
+		method:       http.MethodPost,
+		body:         `{"ranges":[{"min":1,"max":10}]}`,
+		status:       http.StatusRequestEntityTooLarge,
+		response:     errorResponse(roman.CodeRequestBodyTooLarge),
+		setup:        "MAX_BODY_BYTES=16",
+		maxBodyBytes: 16,
+	},
+	errorExample(roman.CodeInvalidJSONTrailingData, "Trailing data", http.MethodPost, "", `{"ranges":[{"min":1,"max":2}]} {}`),
+	limitExample(roman.CodeTooManyRanges, "Too many ranges", `{"ranges":[{"min":1,"max":2},{"min":5,"max":6}]}`, "MAX_RANGES=1", func(l *roman.Limits) { l.MaxRanges = 1 }),
+	limitExample(roman.CodeRangeSpanTooLarge, "Range too large", `{"ranges":[{"min":1,"max":100}]}`, "MAX_RANGE_SPAN=10", func(l *roman.Limits) { l.MaxRangeSpan = 10 }),
+	limitExample(roman.CodeTooManyNumbers, "Too many numbers", `{"ranges":[`+strings.Repeat(`{"min":1,"max":3999},`, 5)+`{"min":1,"max":3999}]}`, "", nil),
+	{
+		name:     roman.CodeRequestTimeout,
+		summary:  "Request timed out",
+		method:   http.MethodGet,
+		query:    "numbers=1,2,3",
+		status:   http.StatusGatewayTimeout,
+		response: errorResponse(roman.CodeRequestTimeout),
+		setup:    "REQUEST_TIMEOUT=1ns",
+		timeout:  time.Nanosecond,
+	},
+	{
+		name:     roman.CodeRequestCanceled,
+		summary:  "Request canceled",
+		method:   http.MethodGet,
+		query:    "numbers=1,2,3",
+		status:   http.StatusServiceUnavailable,
+		response: errorResponse(roman.CodeRequestCanceled),
+		canceled: true,
+	},
+	{
+		name:     roman.CodeUnsupportedVersion,
+		summary:  "Unsupported version",
+		method:   http.MethodGet,
+		path:     convertPathNegotiated,
+		query:    "numbers=1,2,3",
+		header:   map[string]string{"Accept-Version": "v3"},
+		status:   http.StatusNotAcceptable,
+		response: errorResponse(roman.CodeUnsupportedVersion),
+	},
+	{
+		name:     roman.CodeInvalidBatchJSON,
+		summary:  "Missing operations",
+		method:   http.MethodPost,
+		path:     batchPath,
+		body:     `{"ranges":[{"min":1,"max":2}]}`,
+		status:   http.StatusBadRequest,
+		response: errorResponse(roman.CodeInvalidBatchJSON),
+	},
+	{
+		name:     roman.CodeTooManyOperations,
+		summary:  "Too many operations",
+		method:   http.MethodPost,
+		path:     batchPath,
+		body:     `{"operations":[{"op":"parse","numeral":"I"},{"op":"parse","numeral":"II"}]}`,
+		status:   http.StatusBadRequest,
+		response: map[string]interface{}{"error": roman.NewAppError(roman.CodeTooManyOperations).Error(), "limit": 1, "requested": 2},
+		setup:    "MAX_BATCH_OPERATIONS=1",
+		limits:   roman.Limits{MaxOperations: 1},
+	},
+}
+
+// v2Examples describes requests to v2, whose responses are wrapped in
+// an envelope.
+var v2Examples = []example{
+	{
+		name:    "NumbersV2",
+		summary: "Convert numbers",
+		method:  http.MethodGet,
+		path:    convertPathV2,
+		query:   "numbers=1994,4,+04",
+		status:  http.StatusOK,
+		response: types.ConversionEnvelope{
+			Data:   roman.ConvertNumbersToRomanNumerals([]int{4, 1994}),
+			Meta:   types.Meta{Version: roman.Version2, Count: 2, Requested: 3, DurationMs: exampleDurationMs},
+			Errors: []types.ErrorDetail{},
+		},
+	},
+	{
+		name:     "InvalidNumbersV2",
+		summary:  "Invalid numbers",
+		method:   http.MethodGet,
+		path:     convertPathV2,
+		query:    "numbers=1,4000,abc",
+		status:   http.StatusBadRequest,
+		response: invalidNumbersEnvelope("numbers=1,4000,abc"),
+	},
+	{
+		name:     "LenientNumbersV2",
+		summary:  "Convert the valid numbers",
+		method:   http.MethodGet,
+		path:     convertPathV2,
+		query:    lenientQuery,
+		status:   http.StatusOK,
+		response: lenientEnvelope(lenientQuery),
+	},
+	{
+		name:     "BatchV2",
+		summary:  "Run a batch of operations",
+		method:   http.MethodPost,
+		path:     batchPathV2,
+		body:     batchBody,
+		status:   http.StatusOK,
+		response: batchEnvelope(batchResponse()),
+	},
+}
+
+// examples returns every example of the document.
+func examples() []example {
+	all := append(append([]example{}, successExamples...), errorExamples...)
+	return append(all, v2Examples...)
+}
+
+// itemCodes lists the AppError codes only returned for the items of a
+// successful response, with where to find them.
+var itemCodes = map[string]string{
+	roman.CodeOutOfBounds:         lenientCodes,
+	roman.CodeNotANumber:          lenientCodes,
+	roman.CodeEmptyNumber:         lenientCodes,
+	roman.CodeInvalidRomanNumeral: "Only returned for the failed operations of a batch, in the error of their result. See the Batch example.",
+	roman.CodeInvalidOperation:    "Only returned for the failed operations of a batch, in the error of their result. See the Batch example.",
+}
+
+const lenientCodes = "Only returned for the invalid numbers of lenient requests, in their code. See the LenientNumbers example."
+
+// lenientQuery is the query of the lenient examples, with a number
+// failing with every code of lenient requests.
+const lenientQuery = "numbers=1994,abc,,4000,4&mode=lenient"
+
+// errorExample returns an example failing with code and no details,
+// besides the problems found in invalid JSON bodies.
+func errorExample(code, summary, method, query, body string) example {
+	response := errorResponse(code)
+	if err := roman.ValidateJSON([]byte(body)); body != "" && err != nil {
+		response["problems"] = err.Problems
+	}
+	return example{name: code, summary: summary, method: method, query: query, body: body, status: http.StatusBadRequest, response: response}
+}
+
+// limitExample returns an example exceeding a limit of the defaults
+// changed by setLimits.
+func limitExample(code, summary, body, setup string, setLimits func(*roman.Limits)) example {
+	limits := roman.DefaultLimits()
+	if setLimits != nil {
+		setLimits(&limits)
+	}
+	return example{
+		name:     code,
+		summary:  summary,
+		method:   http.MethodPost,
+		body:     body,
+		status:   http.StatusBadRequest,
+		response: limitResponse(body, limits),
+		setup:    setup,
+		limits:   limits,
+	}
+}
+
+// rangesExample returns an example converting the ranges of body, in
+// the order of the numbers of the payload.
+func rangesExample(name, summary, body string) example {
+	payload := rangesPayload(body)
+	numbers, err := roman.ProcessRanges(payload)
+	if err != nil {
+		panic(err)
+	}
+	romans := map[uint]string{}
+	for _, result := range roman.ConvertNumbersToRomanNumerals(numbers) {
+		romans[result.Decimal] = result.Roman
+	}
+	results := make([]types.RomanNumeral, len(numbers))
+	for i, number := range numbers {
+		results[i] = types.RomanNumeral{Decimal: uint(number), Roman: romans[uint(number)]}
+	}
+	return example{
+		name:     name,
+		summary:  summary,
+		method:   http.MethodPost,
+		body:     body,
+		status:   http.StatusOK,
+		response: types.RomanNumeralResponse{Results: results},
+	}
+}
+
+// batchBody is the body of the batch examples, with operations failing
+// with every error only returned for the operations of a batch.
+const batchBody = `{"operations":[{"op":"convert","numbers":[1994,4]},{"op":"range","min":3,"max":5},{"op":"parse","numeral":"XIV"},` +
+	`{"op":"validate","numeral":"IIII"},{"op":"convert","numbers":[1,4000]},{"op":"parse","numeral":"IC"},{"op":"square","numbers":[2]}]}`
+
+// batchResponse returns the body of the batch of batchBody.
+func batchResponse() types.BatchResponse {
+	invalid := false
+	parsed, err := roman.ParseRoman("XIV")
+	if err != nil {
+		panic(err)
+	}
+	return types.BatchResponse{Results: []types.BatchResult{
+		{Index: 0, Op: roman.OpConvert, Results: roman.ConvertNumbersToRomanNumerals([]int{1994, 4})},
+		{Index: 1, Op: roman.OpRange, Results: roman.ConvertNumbersToRomanNumerals([]int{3, 4, 5})},
+		{Index: 2, Op: roman.OpParse, Results: roman.ConvertNumbersToRomanNumerals([]int{parsed})},
+		{Index: 3, Op: roman.OpValidate, Valid: &invalid},
+		{Index: 4, Op: roman.OpConvert, Error: roman.NewAppError(roman.CodeInvalidInput).Error(), InvalidNumbers: []string{"4000"}},
+		{Index: 5, Op: roman.OpParse, Error: roman.NewAppError(roman.CodeInvalidRomanNumeral).Error()},
+		{Index: 6, Op: "square", Error: roman.NewAppError(roman.CodeInvalidOperation).Error()},
+	}}
+}
+
+// batchEnvelope returns the v2 body of a batch whose v1 body is
+// response. The numbers of the example operations are unique, so that
+// every number requested has a result.
+func batchEnvelope(response types.BatchResponse) types.BatchEnvelope {
+	envelope := types.BatchEnvelope{
+		Meta:   types.Meta{Version: roman.Version2, Count: len(response.Results), DurationMs: exampleDurationMs},
+		Errors: []types.ErrorDetail{},
+	}
+	for _, result := range response.Results {
+		item := types.BatchItem{Index: result.Index, Op: result.Op, Results: result.Results, Valid: result.Valid, Errors: []types.ErrorDetail{}}
+		if result.Error != "" {
+			code := strings.Trim(strings.Fields(result.Error)[0], "[]")
+			detail := types.ErrorDetail{Code: code, Message: roman.ErrorMap[code]}
+			for i := range result.InvalidNumbers {
+				invalid := detail
+				invalid.Value = &result.InvalidNumbers[i]
+				item.Errors = append(item.Errors, invalid)
+			}
+			if len(item.Errors) == 0 {
+				item.Errors = append(item.Errors, detail)
+			}
+		} else if result.Op == roman.OpConvert || result.Op == roman.OpRange {
+			envelope.Meta.Requested += len(result.Results)
+			if result.Op == roman.OpRange {
+				envelope.Meta.Ranges++
+			}
+		}
+		envelope.Data = append(envelope.Data, item)
+	}
+	return envelope
+}
+
+// errorResponse returns the body of a response failing with code.
+func errorResponse(code string) map[string]interface{} {
+	return map[string]interface{}{"error": roman.NewAppError(code).Error()}
+}
+
+// invalidNumbersResponse returns the body of a GET request with invalid
+// numbers in its query.
+func invalidNumbersResponse(query string) map[string]interface{} {
+	values, _ := url.ParseQuery(query)
+	_, invalidNumbers := roman.ParseNumberList(values["numbers"])
+	response := errorResponse(roman.CodeInvalidInput)
+	response["invalid_numbers"] = invalidNumbers
+	return response
+}
+
+// lenientResponse returns the body of a lenient GET request.
+func lenientResponse(query string) types.LenientResponse {
+	values, _ := url.ParseQuery(query)
+	numbers, invalidNumbers := roman.ParseNumberListDetailed(values["numbers"])
+	return types.LenientResponse{Results: roman.ConvertNumbersToRomanNumerals(numbers), InvalidNumbers: invalidNumbers}
+}
+
+// lenientEnvelope returns the v2 body of a lenient GET request.
+func lenientEnvelope(query string) types.ConversionEnvelope {
+	response := lenientResponse(query)
+	envelope := types.ConversionEnvelope{
+		Data:   response.Results,
+		Meta:   types.Meta{Version: roman.Version2, Count: len(response.Results), DurationMs: exampleDurationMs},
+		Errors: []types.ErrorDetail{},
+	}
+	values, _ := url.ParseQuery(query)
+	numbers, _ := roman.ParseNumberList(values["numbers"])
+	envelope.Meta.Requested = len(numbers) + len(response.InvalidNumbers)
+	for i, invalid := range response.InvalidNumbers {
+		envelope.Errors = append(envelope.Errors, types.ErrorDetail{Code: invalid.Code, Message: invalid.Message, Value: &response.InvalidNumbers[i].Value})
+	}
+	return envelope
+}
+
+// invalidNumbersEnvelope returns the v2 body of a GET request with
+// invalid numbers in its query.
+func invalidNumbersEnvelope(query string) types.ConversionEnvelope {
+	values, _ := url.ParseQuery(query)
+	_, invalidNumbers := roman.ParseNumberList(values["numbers"])
+	envelope := types.ConversionEnvelope{Meta: types.Meta{Version: roman.Version2, DurationMs: exampleDurationMs}}
+	for i := range invalidNumbers {
+		envelope.Errors = append(envelope.Errors, types.ErrorDetail{
+			Code:    roman.CodeInvalidInput,
+			Message: roman.ErrorMap[roman.CodeInvalidInput],
+			Value:   &invalidNumbers[i],
+		})
+	}
+	return envelope
+}
+
+// limitResponse returns the body of a POST request exceeding the limits.
+func limitResponse(body string, limits roman.Limits) map[string]interface{} {
+	limitErr := limitError(body, limits)
+	response := errorResponse(limitErr.Err.Code)
+	response["limit"] = limitErr.Limit
+	response["requested"] = limitErr.Requested
+	return response
+}
+
+// limitError returns the limit of limits the ranges of body exceed.
+func limitError(body string, limits roman.Limits) *roman.LimitError {
+	var limitErr *roman.LimitError
+	if !errors.As(roman.CheckRangeLimits(rangesPayload(body), limits), &limitErr) {
+		panic("openapi: the example does not exceed the limits: " + body)
+	}
+	return limitErr
+}
+
+// dryRunResponse returns the body of a dry run of body.
+func dryRunResponse(body string) types.RangesCount {
+	count := roman.CountRanges(rangesPayload(body))
+	count.DryRun = true
+	return count
+}
+
+// dryRunOverLimitsResponse returns the body of the dry run of body, with
+// the limit of limits it exceeds.
+func dryRunOverLimitsResponse(body string, limits roman.Limits) types.RangesCount {
+	count := dryRunResponse(body)
+	limitErr := limitError(body, limits)
+	count.Exceeded = &types.LimitExceeded{Error: limitErr.Error(), Limit: limitErr.Limit, Requested: limitErr.Requested}
+	return count
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// rangesPayload decodes a valid example body.
+func rangesPayload(body string) types.RangesPayload {
+	var payload types.RangesPayload
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		panic(err)
+	}
+	return payload
+}
@@ -0,0 +1,47 @@
+package openapi
+
+import (
+	"time"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+)
+
+// Replay is an example request and its response, exported for the tests
+// sending the examples to a router. The duration_ms of v2 responses
+// differs from the example.
+type Replay struct {
+	Name         string
+	Method       string
+	Target       string
+	Header       map[string]string
+	Body         string
+	Status       int
+	Response     interface{}
+	Limits       roman.Limits
+	MaxBodyBytes int64
+	Timeout      time.Duration
+	Canceled     bool
+	Unreadable   bool
+}
+
+// Replays returns the examples of the document.
+func Replays() []Replay {
+	var replays []Replay
+	for _, e := range examples() {
+		replays = append(replays, Replay{
+			Name:         e.name,
+			Method:       e.method,
+			Target:       e.target(),
+			Header:       e.header,
+			Body:         e.body,
+			Status:       e.status,
+			Response:     e.response,
+			Limits:       e.limits,
+			MaxBodyBytes: e.maxBodyBytes,
+			Timeout:      e.timeout,
+			Canceled:     e.canceled,
+			Unreadable:   e.unreadable,
+		})
+	}
+	return replays
+}
@@ -0,0 +1,26 @@
+package openapi
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the document as JSON. It is built once, on the first
+// request.
+func Handler() gin.HandlerFunc {
+	var (
+		once sync.Once
+		data []byte
+		err  error
+	)
+	return func(c *gin.Context) {
+		once.Do(func() { data, err = MarshalJSON() })
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+	}
+}
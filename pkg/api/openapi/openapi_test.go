@@ -0,0 +1,193 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/openapi"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+)
+
+func TestSpec(t *testing.T) {
+	data, err := openapi.MarshalJSON()
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "3.1.0", doc["openapi"])
+
+	// Issue #9: the properties keep their declared order
+	text := string(data)
+	assert.Less(t, strings.Index(text, `"min": {`), strings.Index(text, `"max": {`), "min should be documented before max")
+}
+
+func TestSpecErrorExamples(t *testing.T) {
+	doc := openapi.Spec()
+	for code, message := range roman.ErrorMap {
+		example := doc.Components.Examples[code]
+		if !assert.NotNil(t, example, "missing example of %s", code) {
+			continue
+		}
+		value, err := json.Marshal(example.Value)
+		require.NoError(t, err)
+		assert.Contains(t, string(value), message, code)
+		assert.Empty(t, doc.ValidateJSON(&openapi.Schema{Ref: "#/components/schemas/ErrorResponse"}, value), code)
+	}
+
+	// Every example referenced by an operation exists
+	for path, item := range doc.Paths {
+		for _, operation := range []*openapi.Operation{item.Get, item.Post} {
+			if operation == nil {
+				continue
+			}
+			for status, response := range operation.Responses {
+				for _, media := range response.Content {
+					for name, example := range media.Examples {
+						if example.Ref != "" {
+							assert.Contains(t, doc.Components.Examples, strings.TrimPrefix(example.Ref, "#/components/examples/"), "%s %s %s", path, status, name)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// unreadableBody fails to be read.
+type unreadableBody struct{}
+
+func (unreadableBody) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+// withoutDuration removes the duration_ms of a v2 response, which
+// differs on every response.
+func withoutDuration(t *testing.T, body []byte) string {
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &response))
+	if meta, ok := response["meta"].(map[string]interface{}); ok {
+		assert.Contains(t, meta, "duration_ms")
+		delete(meta, "duration_ms")
+	}
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// TestExamples sends the request of every example to a router and
+// compares the response with the example.
+func TestExamples(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	doc := openapi.Spec()
+
+	for _, replay := range openapi.Replays() {
+		t.Run(replay.Name, func(t *testing.T) {
+			validator := openapi.NewValidator(doc)
+			router := api.NewRouter(api.Options{
+				RangeLimits:    replay.Limits,
+				MaxBodyBytes:   replay.MaxBodyBytes,
+				RequestTimeout: replay.Timeout,
+				Middleware:     []gin.HandlerFunc{validator.Handler},
+			})
+
+			req := httptest.NewRequest(replay.Method, replay.Target, strings.NewReader(replay.Body))
+			if replay.Unreadable {
+				req = httptest.NewRequest(replay.Method, replay.Target, unreadableBody{})
+			}
+			for name, value := range replay.Header {
+				req.Header.Set(name, value)
+			}
+			if replay.Canceled {
+				ctx, cancel := context.WithCancel(req.Context())
+				cancel()
+				req = req.WithContext(ctx)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			expected, err := json.Marshal(replay.Response)
+			require.NoError(t, err)
+			assert.Equal(t, replay.Status, w.Code)
+			assert.JSONEq(t, withoutDuration(t, expected), withoutDuration(t, w.Body.Bytes()))
+			assert.Empty(t, validator.Violations())
+
+			// The example in the document is the same
+			documented, err := json.Marshal(doc.Components.Examples[replay.Name].Value)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(documented))
+		})
+	}
+}
+
+func TestServeSpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := api.InitRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	expected, err := openapi.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), w.Body.String())
+}
+
+func TestValidator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator := openapi.NewValidator(openapi.Spec(), "/metrics")
+	router := api.NewRouter(api.Options{Middleware: []gin.HandlerFunc{validator.Handler}})
+
+	// The responses of the API match the document
+	for _, target := range []string{"/health", "/api/v1/health", "/livez?verbose", "/readyz", "/openapi.json", "/metrics", "/api/v1/convert?numbers=1,2", "/unknown"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	assert.Empty(t, validator.Violations())
+
+	// Responses that drift from the document are reported
+	router = gin.New()
+	router.Use(validator.Handler)
+	router.GET("/api/v1/convert", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"results": []gin.H{{"number": 4, "roman": "iv", "notation": "additive"}}})
+	})
+	router.POST("/api/v1/convert", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"results": []gin.H{}})
+	})
+	router.GET("/api/v1/undocumented", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusTeapot, gin.H{"status": "success", "message": "ok"})
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/convert?numbers=4", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/convert", strings.NewReader(`{"ranges":[{"min":0,"max":1}]}`)))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/undocumented", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	violations := validator.Violations()
+	require.Len(t, violations, 4)
+	assert.Equal(t, []string{
+		`response body $.results[0]: unexpected property "notation"`,
+		`response body $.results[0].roman: "iv" does not match the pattern "^[MDCLXVI]+$"`,
+	}, violations[0].Problems)
+	assert.Equal(t, []string{
+		"the request does not match the document but was answered with 200: body $.ranges[0].min: 0 is less than the minimum 1",
+	}, violations[1].Problems)
+	assert.Equal(t, []string{"the operation GET /api/v1/undocumented is not documented"}, violations[2].Problems)
+	assert.Equal(t, []string{"the status 418 is not documented"}, violations[3].Problems)
+	assert.Equal(t, "GET /health (418): the status 418 is not documented", violations[3].Error())
+
+	validator.Reset()
+	assert.Empty(t, validator.Violations())
+}
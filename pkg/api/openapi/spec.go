@@ -0,0 +1,667 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+)
+
+// Spec returns the OpenAPI document of the API. Every call builds a new
+// document, which the caller may modify.
+func Spec() *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title: "Roman Numeral Converter API",
+			Description: "Converts numbers and ranges of numbers to Roman numerals, and Roman numerals to numbers in batches. " +
+				"Every version of the API is served under its path, e.g. /api/v2/convert, and the version of the Accept-Version header under /api, e.g. /api/convert. " +
+				"v1 is deprecated: its responses carry the Deprecation and Sunset headers, and v2 wraps the responses in an envelope with data, meta and errors.",
+			Version: "2.0",
+			Contact: &Contact{Name: "Asutosh", URL: "https://github.com/mrtyormaa/decimal-to-roman-numerals"},
+		},
+		Servers: []Server{{URL: "/", Description: "The server serving this document"}},
+		Paths: map[string]*PathItem{
+			"/health":       {Get: healthOperation("healthCheck", unversioned)},
+			"/livez":        {Get: probeOperation("livez", "Liveness probe")},
+			"/readyz":       {Get: probeOperation("readyz", "Readiness probe")},
+			"/startupz":     {Get: probeOperation("startupz", "Startup probe")},
+			"/openapi.json": {Get: specOperation()},
+		},
+		Components: Components{
+			Schemas:  schemas(),
+			Examples: map[string]*Example{},
+		},
+	}
+
+	for _, v := range apiVersions {
+		// /health is healthCheck, and v1 keeps its operation IDs
+		healthID := "healthCheck" + v.idSuffix
+		if v.name == roman.Version1 {
+			healthID = "healthCheckV1"
+		}
+		doc.Paths[v.prefix+"/health"] = &PathItem{Get: healthOperation(healthID, v)}
+		doc.Paths[v.prefix+"/convert"] = &PathItem{Get: convertNumbersOperation(v), Post: convertRangesOperation(v)}
+		doc.Paths[v.prefix+"/batch"] = &PathItem{Post: batchOperation(v)}
+	}
+
+	for _, e := range examples() {
+		doc.Components.Examples[e.name] = &Example{Summary: e.summary, Description: e.description(), Value: e.response}
+	}
+	for code, description := range itemCodes {
+		doc.Components.Examples[code] = &Example{
+			Summary:     roman.ErrorMap[code],
+			Description: description,
+			Value:       errorResponse(code),
+		}
+	}
+	return doc
+}
+
+// apiVersion describes the operations of a version of the API.
+type apiVersion struct {
+	// name is the version, or empty for the version negotiated with the
+	// Accept-Version header.
+	name string
+	// prefix is the path the version is served under.
+	prefix string
+	// idSuffix tells the operations of the versions apart.
+	idSuffix   string
+	deprecated bool
+}
+
+var (
+	// unversioned describes the v1 routes kept outside of /api.
+	unversioned = apiVersion{name: roman.Version1}
+	apiVersions = []apiVersion{
+		{name: roman.Version1, prefix: "/api/v1", deprecated: true},
+		{name: roman.Version2, prefix: "/api/v2", idSuffix: "V2"},
+		{prefix: "/api", idSuffix: "ByHeader"},
+	}
+)
+
+// schema returns the schema of a body of the version, given the schemas
+// of the v1 and v2 bodies.
+func (v apiVersion) schema(v1Schema, v2Schema *Schema) *Schema {
+	switch v.name {
+	case roman.Version1:
+		return v1Schema
+	case roman.Version2:
+		return v2Schema
+	}
+	return &Schema{OneOf: []*Schema{v1Schema, v2Schema}}
+}
+
+// examples returns references to the examples of the version.
+func (v apiVersion) examples(v1Names, v2Names []string) map[string]*Example {
+	switch v.name {
+	case roman.Version1:
+		if v.prefix != "" {
+			return exampleRefs(v1Names...)
+		}
+	case roman.Version2:
+		return exampleRefs(v2Names...)
+	}
+	return nil
+}
+
+// operation completes op for the version: deprecated operations send
+// the deprecation headers and negotiated ones take the Accept-Version
+// header.
+func (v apiVersion) operation(op *Operation) *Operation {
+	if v.deprecated || v.name == "" {
+		op.Deprecated = v.deprecated
+		for _, response := range op.Responses {
+			headers := map[string]*Header{}
+			for name, header := range response.Headers {
+				headers[name] = header
+			}
+			for name, header := range deprecationHeaders {
+				headers[name] = header
+			}
+			response.Headers = headers
+		}
+	}
+	if v.name == "" {
+		op.Parameters = append(op.Parameters, acceptVersion)
+	}
+	return op
+}
+
+var deprecationHeaders = map[string]*Header{
+	"Deprecation": {Description: "When v1 was deprecated, as an RFC 9745 timestamp, e.g. @1792368000. Only sent with v1 responses.", Schema: &Schema{Type: "string"}},
+	"Sunset":      {Description: "When v1 stops being served, as an HTTP date. Only sent with v1 responses.", Schema: &Schema{Type: "string"}},
+	"Link":        {Description: "The v2 path replacing the v1 path, with the successor-version relation. Only sent with v1 responses.", Schema: &Schema{Type: "string"}},
+}
+
+var acceptVersion = &Parameter{
+	Name:        "Accept-Version",
+	In:          "header",
+	Description: "The version of the API, e.g. v2. Without it, the default version of the server is used, v1 unless configured otherwise. Other versions fail with ERR1020.",
+	Schema:      &Schema{Type: "string", Examples: []interface{}{roman.Version2}},
+}
+
+// MarshalJSON returns the JSON of the document.
+func MarshalJSON() ([]byte, error) {
+	return json.MarshalIndent(Spec(), "", "  ")
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func number(value float64) *float64 {
+	return &value
+}
+
+func count(value int) *int {
+	return &value
+}
+
+// noAdditionalProperties closes object schemas.
+var noAdditionalProperties = new(bool)
+
+// schemas returns the schemas of the request and response bodies.
+func schemas() map[string]*Schema {
+	numberSchema := func(description string) *Schema {
+		return &Schema{Type: "integer", Description: description, Minimum: number(roman.LowerLimit), Maximum: number(roman.UpperLimit)}
+	}
+	codes := make([]string, 0, len(roman.ErrorMap))
+	for code := range roman.ErrorMap {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	return map[string]*Schema{
+		"RomanNumeral": {
+			Type: "object",
+			Properties: Properties{
+				{"number", numberSchema("The converted number.")},
+				{"roman", &Schema{Type: "string", Description: "The Roman numeral in standard notation.", Pattern: "^[MDCLXVI]+$", Examples: []interface{}{"MCMXCIV"}}},
+			},
+			Required:             []string{"number", "roman"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"RomanNumeralResponse": {
+			Type:                 "object",
+			Description:          "The converted numbers. Unless the options of a request for ranges say otherwise, every number is listed once, in ascending order.",
+			Properties:           Properties{{"results", &Schema{Type: "array", Items: ref("RomanNumeral")}}},
+			Required:             []string{"results"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"NumberRange": {
+			Type:        "object",
+			Description: "A range of numbers. Both bounds are inclusive and min must not be greater than max.",
+			Properties: Properties{
+				{"min", numberSchema("The minimum value of the range.")},
+				{"max", numberSchema("The maximum value of the range.")},
+				{"step", &Schema{Type: "integer", Description: "The gap between the numbers of the range, from min. Defaults to 1.", Minimum: number(1), Examples: []interface{}{5}}},
+				{"exclude", &Schema{Type: "array", Description: "Numbers left out of the range.", Items: numberSchema("")}},
+			},
+			Required:             []string{"min", "max"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"InvalidNumber": {
+			Type:        "object",
+			Description: "An invalid number of a lenient request.",
+			Properties: Properties{
+				{"value", &Schema{Type: "string", Description: "The invalid input. It may be empty, e.g. for 1,,2."}},
+				{"code", &Schema{Type: "string", Description: "The AppError code: ERR1003 out of bounds, ERR1024 not a number or ERR1025 empty.", Enum: []interface{}{roman.CodeOutOfBounds, roman.CodeNotANumber, roman.CodeEmptyNumber}}},
+				{"message", &Schema{Type: "string"}},
+			},
+			Required:             []string{"value", "code", "message"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"LenientResponse": {
+			Type:        "object",
+			Description: "The unique converted numbers, sorted in ascending order, and the invalid numbers of lenient requests.",
+			Properties: Properties{
+				{"results", &Schema{Type: "array", Items: ref("RomanNumeral")}},
+				{"invalid_numbers", &Schema{Type: "array", Description: "The invalid numbers, in the order of the request. Only sent by lenient requests with invalid numbers.", Items: ref("InvalidNumber"), MinItems: count(1)}},
+			},
+			Required:             []string{"results"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"RangesPayload": {
+			Type: "object",
+			Properties: Properties{
+				{"ranges", &Schema{Type: "array", Items: ref("NumberRange"), MinItems: count(1)}},
+				{"order", &Schema{Type: "string", Description: "The order of the results: ascending (the default), descending, or the order of the ranges and of the numbers within them.", Enum: []interface{}{roman.OrderAsc, roman.OrderDesc, roman.OrderInput}}},
+				{"dedupe", &Schema{Type: "boolean", Description: "Whether numbers covered by several ranges are listed once. Defaults to true; false lists them once per range."}},
+			},
+			Required:             []string{"ranges"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"RangesCount": {
+			Type:        "object",
+			Description: "The amount of results a request for ranges would produce.",
+			Properties: Properties{
+				{"dry_run", &Schema{Type: "boolean", Enum: []interface{}{true}}},
+				{"ranges", &Schema{Type: "integer", Description: "The amount of ranges in the request.", Minimum: number(1)}},
+				{"expanded", &Schema{Type: "integer", Description: "The amount of numbers covered by the ranges, counting overlaps once per range.", Minimum: number(0)}},
+				{"count", &Schema{Type: "integer", Description: "The amount of results, unique unless dedupe is false.", Minimum: number(0)}},
+				{"exceeded", ref("LimitExceeded")},
+			},
+			Required:             []string{"dry_run", "ranges", "expanded", "count"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"LimitExceeded": {
+			Type:        "object",
+			Description: "The limit converting the ranges of a dry run would exceed, with the error the conversion would fail with.",
+			Properties: Properties{
+				{"error", &Schema{Type: "string"}},
+				{"limit", &Schema{Type: "integer"}},
+				{"requested", &Schema{Type: "integer"}},
+			},
+			Required:             []string{"error", "limit", "requested"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"BatchOperation": {
+			Type: "object",
+			Description: "An operation of a batch: convert converts numbers, range converts the numbers from min to max, parse converts numeral to its number and validate checks numeral. " +
+				"Each operation takes its own properties only. Invalid operations fail on their own, with ERR1023 or the error of the endpoint doing the same work.",
+			Properties: Properties{
+				{"op", &Schema{Type: "string", Description: "The operation, one of convert, range, parse or validate.", Examples: []interface{}{roman.OpConvert}}},
+				{"numbers", &Schema{Type: "array", Description: "The numbers to convert, for convert.", Items: &Schema{Type: "integer"}, MinItems: count(1)}},
+				{"min", &Schema{Type: "integer", Description: "The minimum value of the range, for range."}},
+				{"max", &Schema{Type: "integer", Description: "The maximum value of the range, for range."}},
+				{"numeral", &Schema{Type: "string", Description: "The Roman numeral, for parse and validate.", Examples: []interface{}{"MCMXCIV"}}},
+			},
+			Required: []string{"op"},
+		},
+		"BatchPayload": {
+			Type:                 "object",
+			Properties:           Properties{{"operations", &Schema{Type: "array", Items: ref("BatchOperation"), MinItems: count(1)}}},
+			Required:             []string{"operations"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"BatchResult": {
+			Type:        "object",
+			Description: "The result of an operation of a batch.",
+			Properties: Properties{
+				{"index", &Schema{Type: "integer", Description: "The position of the operation in the request.", Minimum: number(0)}},
+				{"op", &Schema{Type: "string", Description: "The operation, as sent."}},
+				{"results", &Schema{Type: "array", Description: "The unique converted numbers, sorted in ascending order, the parsed numeral, or the valid numeral.", Items: ref("RomanNumeral")}},
+				{"valid", &Schema{Type: "boolean", Description: "Whether the numeral is valid, for validate."}},
+				{"error", &Schema{Type: "string", Description: "The AppError code and message of a failed operation.", Pattern: `^\[ERR[0-9]{4}\] `}},
+				{"invalid_numbers", &Schema{Type: "array", Description: "The invalid numbers, for ERR1002.", Items: &Schema{Type: "string"}}},
+			},
+			Required:             []string{"index", "op"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"BatchResponse": {
+			Type:                 "object",
+			Properties:           Properties{{"results", &Schema{Type: "array", Items: ref("BatchResult"), MinItems: count(1)}}},
+			Required:             []string{"results"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"BatchItem": {
+			Type:        "object",
+			Description: "The v2 result of an operation of a batch.",
+			Properties: Properties{
+				{"index", &Schema{Type: "integer", Description: "The position of the operation in the request.", Minimum: number(0)}},
+				{"op", &Schema{Type: "string", Description: "The operation, as sent."}},
+				{"results", &Schema{Type: "array", Description: "The unique converted numbers, sorted in ascending order, the parsed numeral, or the valid numeral.", Items: ref("RomanNumeral")}},
+				{"valid", &Schema{Type: "boolean", Description: "Whether the numeral is valid, for validate."}},
+				{"errors", &Schema{Type: "array", Description: "The errors of a failed operation.", Items: ref("ErrorDetail")}},
+			},
+			Required:             []string{"index", "op", "errors"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"BatchEnvelope": {
+			Type:        "object",
+			Description: "The v2 response of a batch.",
+			Properties: Properties{
+				{"data", &Schema{Type: "array", Items: ref("BatchItem"), MinItems: count(1)}},
+				{"meta", ref("Meta")},
+				{"errors", &Schema{Type: "array", Items: ref("ErrorDetail"), MaxItems: count(0)}},
+			},
+			Required:             []string{"data", "meta", "errors"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"JSONProblem": {
+			Type: "object",
+			Properties: Properties{
+				{"path", &Schema{Type: "string", Description: "The JSONPath of the problem.", Examples: []interface{}{"$.ranges[0].min"}}},
+				{"message", &Schema{Type: "string"}},
+			},
+			Required:             []string{"path", "message"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"ErrorResponse": {
+			Type:        "object",
+			Description: "An error, identified by the AppError code at the start of its message.",
+			Properties: Properties{
+				{"error", &Schema{Type: "string", Description: "The AppError code and message, one of " + strings.Join(codes, ", ") + ".", Pattern: `^\[ERR[0-9]{4}\] `}},
+				{"invalid_numbers", &Schema{Type: "array", Description: "The invalid inputs, for ERR1002.", Items: &Schema{Type: "string"}}},
+				{"problems", &Schema{Type: "array", Description: "The problems found in the JSON body, for ERR1006, ERR1010 and ERR1013.", Items: ref("JSONProblem")}},
+				{"limit", &Schema{Type: "integer", Description: "The exceeded limit, for ERR1014, ERR1015, ERR1016 and ERR1022."}},
+				{"requested", &Schema{Type: "integer", Description: "The amount the request asked for, for ERR1014, ERR1015, ERR1016 and ERR1022."}},
+			},
+			Required:             []string{"error"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"Meta": {
+			Type:        "object",
+			Description: "Describes a v2 response.",
+			Properties: Properties{
+				{"version", &Schema{Type: "string", Enum: []interface{}{roman.Version2}}},
+				{"count", &Schema{Type: "integer", Description: "The amount of results, the amount a dry run would produce, or the amount of operations of a batch.", Minimum: number(0)}},
+				{"requested", &Schema{Type: "integer", Description: "The amount of numbers in the request, counting duplicates and overlaps.", Minimum: number(1)}},
+				{"ranges", &Schema{Type: "integer", Description: "The amount of ranges in the request.", Minimum: number(1)}},
+				{"dry_run", &Schema{Type: "boolean", Enum: []interface{}{true}}},
+				{"duration_ms", &Schema{Type: "number", Description: "The time spent serving the request, in milliseconds.", Minimum: number(0)}},
+			},
+			Required:             []string{"version", "count", "duration_ms"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"ErrorDetail": {
+			Type:        "object",
+			Description: "An error of a v2 response.",
+			Properties: Properties{
+				{"code", &Schema{Type: "string", Description: "The AppError code, one of " + strings.Join(codes, ", ") + ".", Pattern: `^ERR[0-9]{4}$`}},
+				{"message", &Schema{Type: "string"}},
+				{"value", &Schema{Type: "string", Description: "The invalid input, for ERR1002, ERR1003, ERR1024 and ERR1025."}},
+				{"path", &Schema{Type: "string", Description: "The JSONPath of the problem, for ERR1006, ERR1010 and ERR1013.", Examples: []interface{}{"$.ranges[0].min"}}},
+				{"detail", &Schema{Type: "string", Description: "The problem found in the JSON body, for ERR1006, ERR1010 and ERR1013, or the allowed query parameters, for ERR1000 and ERR1007."}},
+				{"limit", &Schema{Type: "integer", Description: "The exceeded limit, for ERR1014, ERR1015, ERR1016 and ERR1022."}},
+				{"requested", &Schema{Type: "integer", Description: "The amount the request asked for, for ERR1014, ERR1015, ERR1016 and ERR1022."}},
+			},
+			Required:             []string{"code", "message"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"ConversionEnvelope": {
+			Type:        "object",
+			Description: "The v2 response of the conversion endpoints: the unique converted numbers, sorted in ascending order, or none for dry runs.",
+			Properties: Properties{
+				{"data", &Schema{Type: "array", Items: ref("RomanNumeral")}},
+				{"meta", ref("Meta")},
+				{"errors", &Schema{Type: "array", Description: "The invalid numbers of lenient requests, empty otherwise.", Items: ref("ErrorDetail")}},
+			},
+			Required:             []string{"data", "meta", "errors"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"ErrorEnvelope": {
+			Type:        "object",
+			Description: "The v2 response of a failed request. Invalid numbers, JSON problems and exceeded limits are reported as one error each.",
+			Properties: Properties{
+				{"data", &Schema{Type: "null"}},
+				{"meta", ref("Meta")},
+				{"errors", &Schema{Type: "array", Items: ref("ErrorDetail"), MinItems: count(1)}},
+			},
+			Required:             []string{"data", "meta", "errors"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"HealthEnvelope": {
+			Type: "object",
+			Properties: Properties{
+				{"data", ref("HealthResponse")},
+				{"meta", ref("Meta")},
+				{"errors", &Schema{Type: "array", Items: ref("ErrorDetail"), MaxItems: count(0)}},
+			},
+			Required:             []string{"data", "meta", "errors"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"HealthResponse": {
+			Type: "object",
+			Properties: Properties{
+				{"status", &Schema{Type: "string", Enum: []interface{}{"success", "failure"}}},
+				{"message", &Schema{Type: "string"}},
+			},
+			Required:             []string{"status", "message"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"ProbeResult": {
+			Type: "object",
+			Properties: Properties{
+				{"status", &Schema{Type: "string", Enum: []interface{}{"ok", "failed"}}},
+				{"checks", &Schema{Type: "array", Description: "The result of every check, with the verbose query parameter.", Items: ref("CheckResult")}},
+			},
+			Required:             []string{"status"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+		"CheckResult": {
+			Type: "object",
+			Properties: Properties{
+				{"name", &Schema{Type: "string"}},
+				{"status", &Schema{Type: "string", Enum: []interface{}{"ok", "failed"}}},
+				{"error", &Schema{Type: "string"}},
+				{"duration", &Schema{Type: "string"}},
+			},
+			Required:             []string{"name", "status", "duration"},
+			AdditionalProperties: noAdditionalProperties,
+		},
+	}
+}
+
+func jsonContent(schema *Schema, examples map[string]*Example) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema, Examples: examples}}
+}
+
+// exampleRefs returns references to the component examples, by name.
+func exampleRefs(names ...string) map[string]*Example {
+	refs := make(map[string]*Example, len(names))
+	for _, name := range names {
+		refs[name] = &Example{Ref: "#/components/examples/" + name}
+	}
+	return refs
+}
+
+// sharedError reports whether every endpoint of the version may fail
+// with code for method, whatever the example request: the errors of
+// reading a JSON body and of the shared limits for POST, and the errors
+// of the middlewares.
+func sharedError(code, method string, v apiVersion) bool {
+	switch code {
+	case roman.CodeFailedReadBody, roman.CodeInvalidJSONDuplicateKeys, roman.CodeInValidJSON, roman.CodeRequestBodyTooLarge, roman.CodeInvalidJSONTrailingData,
+		roman.CodeTooManyRanges, roman.CodeRangeSpanTooLarge, roman.CodeTooManyNumbers:
+		return method == http.MethodPost
+	case roman.CodeRequestTimeout, roman.CodeRequestCanceled:
+		return true
+	case roman.CodeUnsupportedVersion:
+		return v.name == ""
+	}
+	return false
+}
+
+// errorResponses returns the error responses of the endpoint of the
+// version for method, grouped by status. The examples sent to the path
+// of the endpoint are referenced.
+func errorResponses(method, endpoint string, v apiVersion) map[string]*Response {
+	path := v.prefix + endpoint
+	codes := map[int][]string{}
+	names := map[int][]string{}
+	for _, e := range errorExamples {
+		examplePath := e.requestPath()
+		own := e.method == method && (examplePath == "/api/v1"+endpoint || examplePath == path)
+		if !own && !sharedError(e.name, method, v) {
+			continue
+		}
+		codes[e.status] = append(codes[e.status], e.name)
+		if examplePath == path {
+			names[e.status] = append(names[e.status], e.name)
+		}
+	}
+
+	responses := map[string]*Response{}
+	for status := range codes {
+		responses[strconv.Itoa(status)] = &Response{
+			Description: fmt.Sprintf("%s: %s", http.StatusText(status), strings.Join(codes[status], ", ")),
+			Content:     jsonContent(v.schema(ref("ErrorResponse"), ref("ErrorEnvelope")), exampleRefs(names[status]...)),
+		}
+	}
+	return responses
+}
+
+var etagHeaders = map[string]*Header{
+	"ETag": {Description: "The entity tag of the results, for If-None-Match.", Schema: &Schema{Type: "string"}},
+}
+
+var ifNoneMatch = &Parameter{
+	Name:        "If-None-Match",
+	In:          "header",
+	Description: "The ETag of a previous response. The response is 304 Not Modified if the results have not changed.",
+	Schema:      &Schema{Type: "string"},
+}
+
+var notModified = &Response{Description: "The results match the If-None-Match ETag."}
+
+func convertNumbersOperation(v apiVersion) *Operation {
+	responses := errorResponses(http.MethodGet, "/convert", v)
+	responses["200"] = &Response{
+		Description: "The Roman numerals of the unique numbers, sorted in ascending order, and the invalid numbers of lenient requests. Responses listing invalid numbers have no ETag.",
+		Headers:     etagHeaders,
+		Content:     jsonContent(v.schema(ref("LenientResponse"), ref("ConversionEnvelope")), v.examples([]string{"Numbers", "LenientNumbers"}, []string{"NumbersV2", "LenientNumbersV2"})),
+	}
+	responses["304"] = notModified
+	if v.name == roman.Version2 {
+		responses["400"].Content["application/json"].Examples = exampleRefs("InvalidNumbersV2")
+	}
+
+	return v.operation(&Operation{
+		OperationID: "convertNumbersToRoman" + v.idSuffix,
+		Summary:     "Convert integers to Roman numerals",
+		Description: "Converts a comma-separated list of integers within the range of 1 to 3999 into their Roman numerals. " +
+			"Leading zeroes, leading '+' signs and extra spaces are supported, and the numbers parameter may be repeated. " +
+			"The response lists every number once, in ascending order. " +
+			"Invalid numbers fail the request, unless mode is lenient: the valid numbers are then converted, and every invalid number is listed with the code of its error.",
+		Tags: []string{"convert"},
+		Parameters: []*Parameter{
+			{
+				Name:        "numbers",
+				In:          "query",
+				Description: "A single integer or a comma-separated list of integers. With mode, it is the only query parameter allowed.",
+				Required:    true,
+				Schema:      &Schema{Type: "string"},
+				Examples: map[string]*Example{
+					"Numbers":              {Summary: "Numbers", Value: "1994,4,+04"},
+					roman.CodeInvalidInput: {Summary: "Invalid numbers", Value: "1,4000,abc"},
+				},
+			},
+			{
+				Name:        "mode",
+				In:          "query",
+				Description: "strict, the default, fails on any invalid number. lenient converts the valid numbers and lists the invalid ones. Other modes fail with ERR1026.",
+				Schema:      &Schema{Type: "string", Enum: []interface{}{"strict", "lenient"}},
+			},
+			ifNoneMatch,
+		},
+		Responses: responses,
+	})
+}
+
+func convertRangesOperation(v apiVersion) *Operation {
+	responses := errorResponses(http.MethodPost, "/convert", v)
+	responses["200"] = &Response{
+		Description: "The Roman numerals of the unique numbers of the ranges, sorted in ascending order, or their count for dry runs.",
+		Headers:     etagHeaders,
+		Content: jsonContent(
+			v.schema(&Schema{OneOf: []*Schema{ref("RomanNumeralResponse"), ref("RangesCount")}}, ref("ConversionEnvelope")),
+			v.examples([]string{"Ranges", "OrderedRanges", "DryRun", "DryRunOverLimits"}, nil),
+		),
+	}
+	responses["304"] = notModified
+
+	requests := requestExamples(convertPath)
+
+	return v.operation(&Operation{
+		OperationID: "convertRangesToRoman" + v.idSuffix,
+		Summary:     "Convert ranges of numbers to Roman numerals",
+		Description: "Converts every number of the ranges, within the range of 1 to 3999, to its Roman numeral. " +
+			"Both bounds of a range are inclusive, and a range may only cover every step numbers from min and exclude some numbers. " +
+			"By default, the response lists every number once, in ascending order; order and dedupe change the order and keep the numbers covered by several ranges. " +
+			"The amount of ranges, the span of each range and the total amount of numbers covered are limited.",
+		Tags: []string{"convert"},
+		Parameters: []*Parameter{
+			{
+				Name:        "dry_run",
+				In:          "query",
+				Description: "Only report how many results the request would produce, and the limit producing them would exceed if any. count_only is an alias. They are the only query parameters allowed.",
+				Schema:      &Schema{Type: "string", Description: "A boolean such as true or 1, or empty for true."},
+			},
+			ifNoneMatch,
+		},
+		RequestBody: &RequestBody{
+			Required: true,
+			Content:  jsonContent(ref("RangesPayload"), requests),
+		},
+		Responses: responses,
+	})
+}
+
+// requestExamples returns the bodies of the POST examples sent to path,
+// or its v2 path, that are valid JSON.
+func requestExamples(path string) map[string]*Example {
+	requests := map[string]*Example{}
+	for _, e := range examples() {
+		examplePath := strings.Replace(e.requestPath(), "/api/v2/", "/api/v1/", 1)
+		if e.method == http.MethodPost && examplePath == path && e.body != "" && json.Valid([]byte(e.body)) {
+			requests[e.name] = &Example{Summary: e.summary, Value: json.RawMessage(e.body)}
+		}
+	}
+	return requests
+}
+
+func batchOperation(v apiVersion) *Operation {
+	responses := errorResponses(http.MethodPost, "/batch", v)
+	responses["200"] = &Response{
+		Description: "The result of every operation, in the order of the request. Failed operations report their error in their result.",
+		Content:     jsonContent(v.schema(ref("BatchResponse"), ref("BatchEnvelope")), v.examples([]string{"Batch"}, []string{"BatchV2"})),
+	}
+
+	return v.operation(&Operation{
+		OperationID: "convertBatch" + v.idSuffix,
+		Summary:     "Run a batch of operations",
+		Description: "Runs every operation of the request: convert converts numbers, range converts the numbers of a range, parse converts a Roman numeral to its number and validate checks a Roman numeral. " +
+			"The results are returned in the order of the operations. An operation failing, e.g. with invalid numbers, does not fail the others: its result reports the error instead. " +
+			"The amount of operations is limited, and the limits of ranges and numbers apply to all the operations together. Requests exceeding them fail as a whole.",
+		Tags: []string{"convert"},
+		RequestBody: &RequestBody{
+			Required: true,
+			Content:  jsonContent(ref("BatchPayload"), requestExamples(batchPath)),
+		},
+		Responses: responses,
+	})
+}
+
+func healthOperation(id string, v apiVersion) *Operation {
+	return v.operation(&Operation{
+		OperationID: id,
+		Summary:     "Check service health",
+		Tags:        []string{"health"},
+		Responses: map[string]*Response{
+			"200": {Description: "The service is up.", Content: jsonContent(v.schema(ref("HealthResponse"), ref("HealthEnvelope")), nil)},
+			"503": {Description: "The service is not ready, as reported by /readyz.", Content: jsonContent(v.schema(ref("HealthResponse"), ref("HealthEnvelope")), nil)},
+		},
+	})
+}
+
+func probeOperation(id, summary string) *Operation {
+	return &Operation{
+		OperationID: id,
+		Summary:     summary,
+		Tags:        []string{"health"},
+		Parameters: []*Parameter{
+			{Name: "verbose", In: "query", Description: "List the result of every check.", Schema: &Schema{Type: "string"}},
+		},
+		Responses: map[string]*Response{
+			"200": {Description: "All the checks passed.", Content: jsonContent(ref("ProbeResult"), nil)},
+			"503": {Description: "A check failed.", Content: jsonContent(ref("ProbeResult"), nil)},
+		},
+	}
+}
+
+func specOperation() *Operation {
+	return &Operation{
+		OperationID: "openAPI",
+		Summary:     "Get this OpenAPI document",
+		Tags:        []string{"docs"},
+		Responses: map[string]*Response{
+			"200": {Description: "The OpenAPI 3.1 document of the API.", Content: jsonContent(&Schema{Type: "object"}, nil)},
+		},
+	}
+}
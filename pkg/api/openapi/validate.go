@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// decodeJSON decodes a JSON document, keeping numbers as json.Number.
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+// ValidateJSON checks a JSON document against schema, resolving the
+// references to the schemas of the document. It returns the problems
+// found, prefixed by their JSONPath, or nil.
+func (d *Document) ValidateJSON(schema *Schema, data []byte) []string {
+	value, err := decodeJSON(data)
+	if err != nil {
+		return []string{"$: invalid JSON: " + err.Error()}
+	}
+	return d.validate(schema, value, "$")
+}
+
+// validate checks a decoded value against schema.
+func (d *Document) validate(schema *Schema, value interface{}, path string) []string {
+	if schema.Ref != "" {
+		resolved := d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if resolved == nil {
+			return []string{fmt.Sprintf("%s: unknown schema %s", path, schema.Ref)}
+		}
+		return d.validate(resolved, value, path)
+	}
+
+	if len(schema.OneOf) > 0 {
+		matches := 0
+		var problems []string
+		for _, option := range schema.OneOf {
+			optionProblems := d.validate(option, value, path)
+			if len(optionProblems) == 0 {
+				matches++
+			}
+			problems = append(problems, optionProblems...)
+		}
+		switch matches {
+		case 1:
+			return nil
+		case 0:
+			return append([]string{path + ": matches none of the oneOf schemas"}, problems...)
+		}
+		return []string{path + ": matches more than one of the oneOf schemas"}
+	}
+
+	if problem := checkType(schema.Type, value); problem != "" {
+		return []string{path + ": " + problem}
+	}
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		return []string{fmt.Sprintf("%s: %v is not one of %v", path, value, schema.Enum)}
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return d.validateObject(schema, value, path)
+	case []interface{}:
+		var problems []string
+		if schema.MinItems != nil && len(value) < *schema.MinItems {
+			problems = append(problems, fmt.Sprintf("%s: expected at least %d items, got %d", path, *schema.MinItems, len(value)))
+		}
+		if schema.MaxItems != nil && len(value) > *schema.MaxItems {
+			problems = append(problems, fmt.Sprintf("%s: expected at most %d items, got %d", path, *schema.MaxItems, len(value)))
+		}
+		if schema.Items != nil {
+			for i, item := range value {
+				problems = append(problems, d.validate(schema.Items, item, path+"["+strconv.Itoa(i)+"]")...)
+			}
+		}
+		return problems
+	case json.Number:
+		number, _ := value.Float64()
+		if schema.Minimum != nil && number < *schema.Minimum {
+			return []string{fmt.Sprintf("%s: %s is less than the minimum %v", path, value, *schema.Minimum)}
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			return []string{fmt.Sprintf("%s: %s is greater than the maximum %v", path, value, *schema.Maximum)}
+		}
+	case string:
+		if schema.Pattern != "" {
+			pattern, err := regexp.Compile(schema.Pattern)
+			if err != nil {
+				return []string{fmt.Sprintf("%s: invalid pattern %q: %v", path, schema.Pattern, err)}
+			}
+			if !pattern.MatchString(value) {
+				return []string{fmt.Sprintf("%s: %q does not match the pattern %q", path, value, schema.Pattern)}
+			}
+		}
+	}
+	return nil
+}
+
+// validateObject checks the properties of an object.
+func (d *Document) validateObject(schema *Schema, object map[string]interface{}, path string) []string {
+	var problems []string
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: missing required property %q", path, name))
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propertySchema := schema.Properties.lookup(name)
+		if propertySchema == nil {
+			if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+				problems = append(problems, fmt.Sprintf("%s: unexpected property %q", path, name))
+			}
+			continue
+		}
+		problems = append(problems, d.validate(propertySchema, object[name], path+"."+name)...)
+	}
+	return problems
+}
+
+// checkType returns a problem if value is not of the JSON Schema type.
+func checkType(schemaType string, value interface{}) string {
+	ok := true
+	switch schemaType {
+	case "":
+		return ""
+	case "object":
+		_, ok = value.(map[string]interface{})
+	case "array":
+		_, ok = value.([]interface{})
+	case "string":
+		_, ok = value.(string)
+	case "boolean":
+		_, ok = value.(bool)
+	case "number":
+		_, ok = value.(json.Number)
+	case "integer":
+		number, isNumber := value.(json.Number)
+		_, err := number.Int64()
+		ok = isNumber && err == nil
+	case "null":
+		ok = value == nil
+	}
+	if !ok {
+		return fmt.Sprintf("expected %s, got %s", schemaType, jsonType(value))
+	}
+	return ""
+}
+
+// jsonType returns the JSON type of a decoded value.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// inEnum reports whether value is one of the enum values.
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) && jsonType(value) == jsonType(normalize(allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize converts Go numbers to json.Number, like decoded values.
+func normalize(value interface{}) interface{} {
+	switch value := value.(type) {
+	case int:
+		return json.Number(strconv.Itoa(value))
+	case float64:
+		return json.Number(strconv.FormatFloat(value, 'f', -1, 64))
+	}
+	return value
+}
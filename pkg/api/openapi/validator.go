@@ -0,0 +1,231 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Violation is a request or response that does not match the document.
+type Violation struct {
+	Method   string
+	URI      string
+	Status   int
+	Problems []string
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("%s %s (%d): %s", v.Method, v.URI, v.Status, strings.Join(v.Problems, "; "))
+}
+
+// Validator is a middleware checking requests and responses against a
+// document, meant for tests. It checks that:
+//
+//   - the route of the request is documented, unless it is skipped,
+//   - the response has a documented status and content type, and its
+//     body matches the schema of the response,
+//   - requests that do not match the document, e.g. whose body does not
+//     match the schema of the request, are answered with an error.
+//
+// It must run after the middlewares that transform responses, such as
+// compression, so that it sees the bodies written by the handlers.
+// Responses written by earlier middlewares, such as timeouts, are not
+// checked.
+type Validator struct {
+	doc  *Document
+	skip map[string]bool
+
+	mu         sync.Mutex
+	violations []Violation
+}
+
+// NewValidator returns a validator of doc. The skipped routes, e.g.
+// "/metrics", are not checked.
+func NewValidator(doc *Document, skip ...string) *Validator {
+	v := &Validator{doc: doc, skip: make(map[string]bool, len(skip))}
+	for _, route := range skip {
+		v.skip[route] = true
+	}
+	return v
+}
+
+// Violations returns the violations found so far.
+func (v *Validator) Violations() []Violation {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]Violation(nil), v.violations...)
+}
+
+// Reset forgets the violations found so far.
+func (v *Validator) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.violations = nil
+}
+
+func (v *Validator) report(c *gin.Context, status int, problems []string) {
+	if len(problems) == 0 {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.violations = append(v.violations, Violation{
+		Method:   c.Request.Method,
+		URI:      c.Request.URL.RequestURI(),
+		Status:   status,
+		Problems: problems,
+	})
+}
+
+// Handler checks the request and its response.
+func (v *Validator) Handler(c *gin.Context) {
+	route := c.FullPath()
+	if route == "" || v.skip[route] || c.Request.Method == http.MethodOptions {
+		c.Next()
+		return
+	}
+
+	var operation *Operation
+	if item := v.doc.Paths[route]; item != nil {
+		operation = item.operation(c.Request.Method)
+	}
+	if operation == nil {
+		c.Next()
+		v.report(c, c.Writer.Status(), []string{fmt.Sprintf("the operation %s %s is not documented", c.Request.Method, route)})
+		return
+	}
+
+	requestProblems := v.checkRequest(c, operation)
+
+	recorder := &bodyRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	c.Next()
+	c.Writer = recorder.ResponseWriter
+
+	// The response is written by an earlier middleware
+	if !recorder.Written() {
+		return
+	}
+
+	status := recorder.Status()
+	problems := v.checkResponse(c, operation, status, recorder.body.Bytes())
+	if len(requestProblems) > 0 && status < http.StatusBadRequest {
+		problems = append(problems, fmt.Sprintf("the request does not match the document but was answered with %d: %s", status, strings.Join(requestProblems, "; ")))
+	}
+	v.report(c, status, problems)
+}
+
+// checkRequest returns the problems of the request. The body is read
+// and restored for the handlers.
+func (v *Validator) checkRequest(c *gin.Context, operation *Operation) []string {
+	var problems []string
+	query := c.Request.URL.Query()
+	for _, parameter := range operation.Parameters {
+		if parameter.In != "query" {
+			continue
+		}
+		values, ok := query[parameter.Name]
+		if !ok {
+			if parameter.Required {
+				problems = append(problems, fmt.Sprintf("missing required query parameter %q", parameter.Name))
+			}
+			continue
+		}
+		for _, value := range values {
+			for _, problem := range v.doc.validate(parameter.Schema, value, parameter.Name) {
+				problems = append(problems, "query parameter "+problem)
+			}
+		}
+	}
+
+	if operation.RequestBody == nil || c.Request.Body == nil {
+		return problems
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{err}))
+	if err != nil {
+		return append(problems, "failed to read the body: "+err.Error())
+	}
+	if len(body) == 0 {
+		if operation.RequestBody.Required {
+			problems = append(problems, "missing required body")
+		}
+		return problems
+	}
+	media := operation.RequestBody.Content["application/json"]
+	if media == nil {
+		return append(problems, "the operation does not accept JSON bodies")
+	}
+	for _, problem := range v.doc.ValidateJSON(media.Schema, body) {
+		problems = append(problems, "body "+problem)
+	}
+	return problems
+}
+
+// checkResponse returns the problems of the response.
+func (v *Validator) checkResponse(c *gin.Context, operation *Operation, status int, body []byte) []string {
+	response := operation.Responses[strconv.Itoa(status)]
+	if response == nil {
+		response = operation.Responses["default"]
+	}
+	if response == nil {
+		return []string{fmt.Sprintf("the status %d is not documented", status)}
+	}
+
+	if len(response.Content) == 0 || c.Request.Method == http.MethodHead {
+		if len(body) > 0 && c.Request.Method != http.MethodHead {
+			return []string{fmt.Sprintf("the response %d should have no body", status)}
+		}
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(c.Writer.Header().Get("Content-Type"))
+	if err != nil {
+		return []string{fmt.Sprintf("invalid content type %q", c.Writer.Header().Get("Content-Type"))}
+	}
+	media := response.Content[mediaType]
+	if media == nil {
+		return []string{fmt.Sprintf("the content type %s of the response %d is not documented", mediaType, status)}
+	}
+
+	var problems []string
+	for _, problem := range v.doc.ValidateJSON(media.Schema, body) {
+		problems = append(problems, "response body "+problem)
+	}
+	return problems
+}
+
+// bodyRecorder keeps a copy of the response body.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *bodyRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *bodyRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// errReader returns the error that stopped reading a body, after the
+// part that was read.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
@@ -1,30 +1,44 @@
 package roman
 
+import (
+	"context"
+
+	numerals "github.com/mrtyormaa/decimal-to-roman-numerals/pkg/roman"
+)
+
 // Converts an integer to its corresponding Roman numeral string.
+// It is backed by the pkg/roman library, mapping its errors to AppErrors.
 type BasicRomanConverter struct{}
 
-// Convert converts an integer to its corresponding Roman numeral string.
-// It first checks if the input number is within the acceptable range
-// (LowerLimit to UpperLimit). If the number is out of bounds, it returns
-// an error. The function uses predefined arrays of decimal values and
-// their corresponding Roman numeral symbols to iteratively build the
-// Roman numeral string. It subtracts values from the input number
-// while appending the corresponding symbols to the result string until
-// the number is reduced to zero, ensuring an accurate conversion.
+// Convert converts an integer to its corresponding Roman numeral string
+// in the standard notation. If the number is not within LowerLimit and
+// UpperLimit, it returns an AppError with CodeOutOfBounds.
 func (c *BasicRomanConverter) Convert(num int) (string, error) {
 	if num < LowerLimit || num > UpperLimit {
 		return "", NewAppError(CodeOutOfBounds)
 	}
+	roman, err := numerals.Convert(num)
+	if err != nil {
+		return "", NewAppError(CodeOutOfBounds)
+	}
+	return roman, nil
+}
 
-	val := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
-	syb := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+// ConvertContext converts like Convert, after checking that ctx is not done.
+func (c *BasicRomanConverter) ConvertContext(ctx context.Context, num int) (string, error) {
+	if err := checkContext(ctx); err != nil {
+		return "", err
+	}
+	return c.Convert(num)
+}
 
-	roman := ""
-	for i := 0; i < len(val); i++ {
-		for num >= val[i] {
-			roman += syb[i]
-			num -= val[i]
-		}
+// Parse parses a Roman numeral in the standard notation, ignoring case
+// and surrounding spaces. Invalid numerals, including numerals in any
+// other notation, fail with an AppError with CodeInvalidRomanNumeral.
+func (c *BasicRomanConverter) Parse(numeral string) (int, error) {
+	number, err := numerals.Parse(numeral)
+	if err != nil {
+		return 0, NewAppError(CodeInvalidRomanNumeral)
 	}
-	return roman, nil
+	return number, nil
 }
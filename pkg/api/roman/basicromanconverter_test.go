@@ -1,8 +1,11 @@
 package roman_test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
 )
 
@@ -50,3 +53,24 @@ func TestBasicRomanConverter_Convert(t *testing.T) {
 		})
 	}
 }
+
+// Test the self-test used by the health probes
+func TestSelfTest(t *testing.T) {
+	if err := roman.SelfTest(); err != nil {
+		t.Errorf("SelfTest() returned %v", err)
+	}
+}
+
+func TestBasicRomanConverterConvertContext(t *testing.T) {
+	converter := &roman.BasicRomanConverter{}
+
+	result, err := converter.ConvertContext(context.Background(), 1994)
+	assert.NoError(t, err)
+	assert.Equal(t, "MCMXCIV", result)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = converter.ConvertContext(ctx, 1994)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, roman.CodeRequestCanceled)
+}
@@ -0,0 +1,328 @@
+package roman
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+// The operations of a batch request.
+const (
+	OpConvert  = "convert"
+	OpRange    = "range"
+	OpParse    = "parse"
+	OpValidate = "validate"
+)
+
+// operationKeys lists the keys every operation accepts besides "op".
+var operationKeys = map[string][]string{
+	OpConvert:  {"numbers"},
+	OpRange:    {"min", "max"},
+	OpParse:    {"numeral"},
+	OpValidate: {"numeral"},
+}
+
+// batchItem is an operation of a batch request and its outcome. Operations
+// that fail, to be parsed or to run, have an err and the extra fields of
+// their error.
+type batchItem struct {
+	op      types.BatchOperation
+	results []types.RomanNumeral
+	valid   *bool
+	err     error
+	extra   gin.H
+}
+
+// fail records err as the outcome of the operation.
+func (item *batchItem) fail(err error, extra gin.H) {
+	item.err, item.extra = err, extra
+}
+
+// ConvertBatch handles the API request to run a batch of operations.
+// @Summary Run a batch of operations
+// @Description Runs every operation of the JSON request body and returns their results in the order of the request.
+// @Description An operation converts numbers ('convert'), converts the numbers of a range ('range'), parses a Roman numeral ('parse') or checks whether a Roman numeral is valid ('validate').
+// @Description Failed operations report their error next to the results of the others, while requests that cannot be read, or exceed the limits shared by all the operations, fail as a whole.
+// @ID convertBatch
+// @Accept json
+// @Produce json
+// @Param operations body types.BatchPayload true "List of operations" example({"operations": [{"op": "convert", "numbers": [4, 1994]}, {"op": "parse", "numeral": "XIV"}]})
+// @Success 200 {object} types.BatchResponse
+// @Failure 400 {object} types.JsonErrorResponse "Invalid JSON Payload or operations exceeding the limits"
+// @Router /batch [post]
+func ConvertBatch(c *gin.Context) {
+	items, err := getBatchPayload(c)
+	if err != nil {
+		respondWithError(c, errorStatus(err), err, errorDetails(err))
+		return
+	}
+
+	// Reject batches asking for too much work before running any operation
+	if err := checkBatchLimits(items, limitsFrom(c)); err != nil {
+		respondWithError(c, http.StatusBadRequest, err, errorDetails(err))
+		return
+	}
+
+	recorder := recorderFrom(c)
+	converted := 0
+	for i := range items {
+		if err := runOperation(c, &items[i]); err != nil {
+			respondWithError(c, errorStatus(err), err, nil)
+			return
+		}
+		item := items[i]
+		if item.err != nil {
+			recorder.IncOperationError(operationLabel(item.op.Op), errorCode(item.err))
+		} else if item.op.Op == OpRange {
+			recorder.ObserveRangeSpan(item.op.Max - item.op.Min + 1)
+		}
+		converted += len(item.results)
+	}
+
+	// Record the domain metrics, unless no operation converted a number
+	if converted > 0 {
+		recorder.ObserveNumbersConverted("batch", converted)
+		recorder.IncConversion(NotationStandard, FormatJSON)
+	}
+
+	respondWithBatch(c, items)
+}
+
+// operationLabel returns op as a metric label, or "invalid" for the
+// unknown operations clients may send.
+func operationLabel(op string) string {
+	switch op {
+	case OpConvert, OpRange, OpParse, OpValidate:
+		return op
+	}
+	return "invalid"
+}
+
+// getBatchPayload reads the operations of a batch request. Operations
+// that cannot be parsed are returned with their error, while the errors
+// of the request itself are returned.
+func getBatchPayload(c *gin.Context) (items []batchItem, err error) {
+	_, span := startSpan(c.Request.Context(), "getBatchPayload")
+	defer func() {
+		span.SetAttributes(attrOperationCount.Int(len(items)))
+		endSpan(span, err)
+	}()
+
+	// Read the raw request body, up to the body limit
+	rawBody, err := readBody(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check for duplicate keys at any depth and trailing data
+	if validationErr := ValidateJSON(rawBody); validationErr != nil {
+		return nil, validationErr
+	}
+
+	// Keep the numbers as written, to report the invalid ones as sent
+	var payload map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(rawBody))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, NewAppError(CodeInValidJSON)
+	}
+
+	// Check if the payload contains exactly one key "operations" and the value is an array
+	operations, ok := payload["operations"].([]interface{})
+	if !ok || len(payload) != 1 || len(operations) == 0 {
+		return nil, NewAppError(CodeInvalidBatchJSON)
+	}
+
+	items = make([]batchItem, len(operations))
+	for i, operation := range operations {
+		items[i] = parseOperation(operation)
+	}
+	return items, nil
+}
+
+// parseOperation parses an operation of a batch request.
+func parseOperation(value interface{}) batchItem {
+	var item batchItem
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		item.fail(NewAppError(CodeInvalidOperation), nil)
+		return item
+	}
+	item.op.Op, _ = fields["op"].(string)
+
+	// Every key must belong to the operation
+	keys, ok := operationKeys[item.op.Op]
+	if !ok {
+		item.fail(NewAppError(CodeInvalidOperation), nil)
+		return item
+	}
+	for key := range fields {
+		if key != "op" && !slices.Contains(keys, key) {
+			item.fail(NewAppError(CodeInvalidOperation), nil)
+			return item
+		}
+	}
+
+	switch item.op.Op {
+	case OpConvert:
+		values, ok := fields["numbers"].([]interface{})
+		if !ok || len(values) == 0 {
+			item.fail(NewAppError(CodeInvalidOperation), nil)
+			return item
+		}
+		var invalidNumbers []string
+		for _, value := range values {
+			number, ok := jsonInt(value)
+			if !ok || number < LowerLimit || number > UpperLimit {
+				invalidNumbers = append(invalidNumbers, jsonText(value))
+				continue
+			}
+			item.op.Numbers = append(item.op.Numbers, number)
+		}
+		if len(invalidNumbers) > 0 {
+			item.fail(NewAppError(CodeInvalidInput), gin.H{"invalid_numbers": invalidNumbers})
+		}
+	case OpRange:
+		min, minOk := jsonInt(fields["min"])
+		max, maxOk := jsonInt(fields["max"])
+		if !minOk || !maxOk {
+			item.fail(NewAppError(CodeInValidRangeMissingMinMax), nil)
+			return item
+		}
+		item.op.Min, item.op.Max = min, max
+		if err := validateRange(types.NumberRange{Min: min, Max: max}); err != nil {
+			item.fail(err, nil)
+		}
+	case OpParse, OpValidate:
+		numeral, ok := fields["numeral"].(string)
+		if !ok {
+			item.fail(NewAppError(CodeInvalidOperation), nil)
+			return item
+		}
+		item.op.Numeral = numeral
+	}
+	return item
+}
+
+// jsonInt returns the integer of a JSON number decoded with UseNumber.
+func jsonInt(value interface{}) (int, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	integer, err := strconv.Atoi(number.String())
+	return integer, err == nil
+}
+
+// jsonText returns an invalid number the way it was sent: strings
+// without quotes and other values as JSON.
+func jsonText(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// checkBatchLimits checks that the valid operations of a batch stay
+// within limits together, without expanding their ranges.
+func checkBatchLimits(items []batchItem, limits Limits) error {
+	if len(items) > limits.MaxOperations {
+		return &LimitError{Err: NewAppError(CodeTooManyOperations), Limit: limits.MaxOperations, Requested: len(items)}
+	}
+
+	var ranges []types.NumberRange
+	numbers := 0
+	for _, item := range items {
+		if item.err != nil {
+			continue
+		}
+		switch item.op.Op {
+		case OpConvert:
+			numbers += len(item.op.Numbers)
+		case OpRange:
+			ranges = append(ranges, types.NumberRange{Min: item.op.Min, Max: item.op.Max})
+		}
+	}
+	return checkLimits(ranges, numbers, limits)
+}
+
+// runOperation runs a valid operation, recording its outcome in item. It
+// only returns the errors failing the whole batch, i.e. a *CanceledError
+// once the request is done.
+func runOperation(c *gin.Context, item *batchItem) error {
+	if err := checkContext(c.Request.Context()); err != nil {
+		return err
+	}
+	if item.err != nil {
+		return nil
+	}
+
+	var err error
+	switch item.op.Op {
+	case OpConvert:
+		item.results, err = cachedConvertNumbers(c, item.op.Numbers)
+	case OpRange:
+		var numbers []int
+		numbers, err = cachedProcessRanges(c, types.RangesPayload{Ranges: []types.NumberRange{{Min: item.op.Min, Max: item.op.Max}}})
+		if err == nil {
+			item.results, err = cachedConvertNumbers(c, numbers)
+		}
+	case OpParse:
+		number, parseErr := ParseRoman(item.op.Numeral)
+		if parseErr != nil {
+			item.fail(parseErr, nil)
+			return nil
+		}
+		item.results, err = tracedConvertNumbers(c.Request.Context(), []int{number})
+	case OpValidate:
+		number, parseErr := ParseRoman(item.op.Numeral)
+		valid := parseErr == nil
+		item.valid = &valid
+		if valid {
+			item.results, err = tracedConvertNumbers(c.Request.Context(), []int{number})
+		}
+	}
+	return err
+}
+
+// respondWithBatch writes the outcome of every operation of a batch, in
+// the order of the request.
+func respondWithBatch(c *gin.Context, items []batchItem) {
+	if VersionFrom(c) != Version2 {
+		results := make([]types.BatchResult, len(items))
+		for i, item := range items {
+			results[i] = types.BatchResult{Index: i, Op: item.op.Op, Results: item.results, Valid: item.valid}
+			if item.err != nil {
+				results[i].Error = item.err.Error()
+				results[i].InvalidNumbers, _ = item.extra["invalid_numbers"].([]string)
+			}
+		}
+		c.JSON(http.StatusOK, types.BatchResponse{Results: results})
+		return
+	}
+
+	meta := newMeta(c, len(items))
+	data := make([]types.BatchItem, len(items))
+	for i, item := range items {
+		data[i] = types.BatchItem{Index: i, Op: item.op.Op, Results: item.results, Valid: item.valid, Errors: []types.ErrorDetail{}}
+		if item.err != nil {
+			data[i].Errors = errorDetailList(item.err, item.extra)
+			continue
+		}
+		switch item.op.Op {
+		case OpConvert:
+			meta.Requested += len(item.op.Numbers)
+		case OpRange:
+			meta.Requested += item.op.Max - item.op.Min + 1
+			meta.Ranges++
+		}
+	}
+	c.JSON(http.StatusOK, types.BatchEnvelope{Data: data, Meta: meta, Errors: []types.ErrorDetail{}})
+}
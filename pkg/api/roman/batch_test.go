@@ -0,0 +1,177 @@
+package roman_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+func batchRouter(limits *roman.Limits) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if limits != nil {
+		router.Use(roman.RangeLimits(*limits))
+	}
+	router.POST("/batch", roman.ConvertBatch)
+	router.POST("/v2/batch", roman.APIVersion(roman.Version2), roman.ConvertBatch)
+	return router
+}
+
+func postBatch(router http.Handler, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestConvertBatch(t *testing.T) {
+	router := batchRouter(nil)
+
+	resp := postBatch(router, "/batch", `{"operations":[
+		{"op":"convert","numbers":[4,1994,4]},
+		{"op":"range","min":3,"max":5},
+		{"op":"parse","numeral":"xiv"},
+		{"op":"validate","numeral":"IIII"},
+		{"op":"validate","numeral":"IX"}
+	]}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var response types.BatchResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+	valid, invalid := true, false
+	assert.Equal(t, []types.BatchResult{
+		{Index: 0, Op: "convert", Results: []types.RomanNumeral{{Decimal: 4, Roman: "IV"}, {Decimal: 1994, Roman: "MCMXCIV"}}},
+		{Index: 1, Op: "range", Results: []types.RomanNumeral{{Decimal: 3, Roman: "III"}, {Decimal: 4, Roman: "IV"}, {Decimal: 5, Roman: "V"}}},
+		{Index: 2, Op: "parse", Results: []types.RomanNumeral{{Decimal: 14, Roman: "XIV"}}},
+		{Index: 3, Op: "validate", Valid: &invalid},
+		{Index: 4, Op: "validate", Results: []types.RomanNumeral{{Decimal: 9, Roman: "IX"}}, Valid: &valid},
+	}, response.Results)
+}
+
+func TestConvertBatchOperationErrors(t *testing.T) {
+	router := batchRouter(nil)
+
+	// Failed operations do not fail the others
+	resp := postBatch(router, "/batch", `{"operations":[
+		{"op":"convert","numbers":[1,4000,"abc",2.5]},
+		{"op":"range","min":10,"max":1},
+		{"op":"range","min":0,"max":1},
+		{"op":"range","min":1},
+		{"op":"parse","numeral":"IC"},
+		{"op":"square","numbers":[1]},
+		{"op":"convert","numbers":[1],"format":"xml"},
+		{"op":"convert","numbers":[]},
+		{"op":"parse","numeral":4},
+		"convert",
+		{"op":"convert","numbers":[2]}
+	]}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var response types.BatchResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+	require.Len(t, response.Results, 11)
+	expectedCodes := []string{
+		roman.CodeInvalidInput,
+		roman.CodeInvalidRangeMinMoreMax,
+		roman.CodeInvalidRangeBounds,
+		roman.CodeInValidRangeMissingMinMax,
+		roman.CodeInvalidRomanNumeral,
+		roman.CodeInvalidOperation,
+		roman.CodeInvalidOperation,
+		roman.CodeInvalidOperation,
+		roman.CodeInvalidOperation,
+		roman.CodeInvalidOperation,
+	}
+	for i, code := range expectedCodes {
+		assert.Equal(t, i, response.Results[i].Index)
+		assert.Equal(t, roman.NewAppError(code).Error(), response.Results[i].Error, "operation %d", i)
+		assert.Empty(t, response.Results[i].Results, "operation %d", i)
+	}
+	assert.Equal(t, []string{"4000", "abc", "2.5"}, response.Results[0].InvalidNumbers)
+	assert.Equal(t, "square", response.Results[5].Op)
+	assert.Empty(t, response.Results[9].Op)
+	assert.Empty(t, response.Results[10].Error)
+	assert.Equal(t, []types.RomanNumeral{{Decimal: 2, Roman: "II"}}, response.Results[10].Results)
+}
+
+func TestConvertBatchRequestErrors(t *testing.T) {
+	router := batchRouter(nil)
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode string
+	}{
+		{"MissingOperations", `{"ranges":[{"min":1,"max":2}]}`, roman.CodeInvalidBatchJSON},
+		{"EmptyOperations", `{"operations":[]}`, roman.CodeInvalidBatchJSON},
+		{"ExtraKey", `{"operations":[{"op":"parse","numeral":"I"}],"dry_run":true}`, roman.CodeInvalidBatchJSON},
+		{"NotAnArray", `{"operations":{"op":"parse","numeral":"I"}}`, roman.CodeInvalidBatchJSON},
+		{"Malformed", `{"operations":[`, roman.CodeInValidJSON},
+		{"DuplicateKeys", `{"operations":[{"op":"parse","op":"validate","numeral":"I"}]}`, roman.CodeInvalidJSONDuplicateKeys},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := postBatch(router, "/batch", test.body)
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+			assert.Contains(t, resp.Body.String(), test.expectedCode)
+		})
+	}
+}
+
+func TestConvertBatchLimits(t *testing.T) {
+	router := batchRouter(&roman.Limits{MaxOperations: 2, MaxRanges: 1, MaxExpanded: 10})
+
+	resp := postBatch(router, "/batch", `{"operations":[{"op":"parse","numeral":"I"},{"op":"parse","numeral":"II"},{"op":"parse","numeral":"III"}]}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error": "`+roman.NewAppError(roman.CodeTooManyOperations).Error()+`", "limit": 2, "requested": 3}`, resp.Body.String())
+
+	// The ranges of every operation count together
+	resp = postBatch(router, "/batch", `{"operations":[{"op":"range","min":1,"max":2},{"op":"range","min":3,"max":4}]}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error": "`+roman.NewAppError(roman.CodeTooManyRanges).Error()+`", "limit": 1, "requested": 2}`, resp.Body.String())
+
+	// So do the numbers, converted or covered by a range
+	resp = postBatch(router, "/batch", `{"operations":[{"op":"convert","numbers":[1,2,3,4,5,6]},{"op":"range","min":1,"max":5}]}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error": "`+roman.NewAppError(roman.CodeTooManyNumbers).Error()+`", "limit": 10, "requested": 11}`, resp.Body.String())
+
+	// Failed operations do not count
+	resp = postBatch(router, "/batch", `{"operations":[{"op":"convert","numbers":[1,2,3,4,5,6,0]},{"op":"range","min":1,"max":5}]}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestConvertBatchV2(t *testing.T) {
+	router := batchRouter(nil)
+
+	resp := postBatch(router, "/v2/batch", `{"operations":[{"op":"convert","numbers":[1,2,2,0]},{"op":"range","min":1,"max":3},{"op":"validate","numeral":"IIII"}]}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var envelope types.BatchEnvelope
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &envelope))
+	zero, invalid := "0", false
+	assert.Equal(t, []types.BatchItem{
+		{Index: 0, Op: "convert", Errors: []types.ErrorDetail{{Code: roman.CodeInvalidInput, Message: roman.ErrorMap[roman.CodeInvalidInput], Value: &zero}}},
+		{Index: 1, Op: "range", Results: []types.RomanNumeral{{Decimal: 1, Roman: "I"}, {Decimal: 2, Roman: "II"}, {Decimal: 3, Roman: "III"}}, Errors: []types.ErrorDetail{}},
+		{Index: 2, Op: "validate", Valid: &invalid, Errors: []types.ErrorDetail{}},
+	}, envelope.Data)
+	envelope.Meta.DurationMs = 0
+	assert.Equal(t, types.Meta{Version: roman.Version2, Count: 3, Requested: 3, Ranges: 1}, envelope.Meta)
+	assert.Empty(t, envelope.Errors)
+
+	// Batches failing as a whole have no data
+	resp = postBatch(router, "/v2/batch", `{"operations":[]}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &envelope))
+	assert.Nil(t, envelope.Data)
+	assert.Equal(t, []types.ErrorDetail{{Code: roman.CodeInvalidBatchJSON, Message: roman.ErrorMap[roman.CodeInvalidBatchJSON]}}, envelope.Errors)
+}
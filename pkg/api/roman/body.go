@@ -0,0 +1,86 @@
+package roman
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultMaxBodyBytes is the request body limit used when BodyLimit
+	// has not been installed.
+	DefaultMaxBodyBytes int64 = 1 << 20
+
+	bodyLimitKey = "roman.body_limit"
+)
+
+// BodyLimit limits the request bodies read by the handlers of the routes
+// it is used on to limit bytes.
+func BodyLimit(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(bodyLimitKey, limit)
+		c.Next()
+	}
+}
+
+// bodyLimitFrom returns the limit installed by BodyLimit, or DefaultMaxBodyBytes.
+func bodyLimitFrom(c *gin.Context) int64 {
+	if value, ok := c.Get(bodyLimitKey); ok {
+		if limit, ok := value.(int64); ok && limit > 0 {
+			return limit
+		}
+	}
+	return DefaultMaxBodyBytes
+}
+
+// readBody reads the request body, failing with CodeRequestBodyTooLarge
+// as soon as it exceeds the body limit.
+func readBody(c *gin.Context) ([]byte, error) {
+	limit := bodyLimitFrom(c)
+	if c.Request.ContentLength > limit {
+		return nil, NewAppError(CodeRequestBodyTooLarge)
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, NewAppError(CodeRequestBodyTooLarge)
+		}
+		return nil, NewAppError(CodeFailedReadBody)
+	}
+	return body, nil
+}
+
+// errorStatus returns the HTTP status of an error returned while
+// reading and validating a request.
+func errorStatus(err error) int {
+	switch errorCode(err) {
+	case CodeRequestBodyTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case CodeRequestTimeout:
+		return http.StatusGatewayTimeout
+	case CodeRequestCanceled:
+		return http.StatusServiceUnavailable
+	case CodeUnsupportedVersion:
+		return http.StatusNotAcceptable
+	}
+	return http.StatusBadRequest
+}
+
+// errorDetails returns the fields added to the error response of err,
+// such as the JSON problems of a JSONValidationError or the exceeded
+// limit of a LimitError.
+func errorDetails(err error) gin.H {
+	var validationErr *JSONValidationError
+	if errors.As(err, &validationErr) {
+		return gin.H{"problems": validationErr.Problems}
+	}
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) {
+		return gin.H{"limit": limitErr.Limit, "requested": limitErr.Requested}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package roman_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+)
+
+func bodyLimitRouter(limit int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if limit > 0 {
+		router.Use(roman.BodyLimit(limit))
+	}
+	router.POST("/convert", roman.ConvertRangesToRoman)
+	return router
+}
+
+func TestBodyLimit(t *testing.T) {
+	payload := `{"ranges": [{"min": 1, "max": 3}]}`
+	router := bodyLimitRouter(int64(len(payload)))
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		contentLength  int64
+		expectedStatus int
+	}{
+		{"AtLimit", strings.NewReader(payload), int64(len(payload)), http.StatusOK},
+		{"ContentLengthAboveLimit", strings.NewReader(payload + " "), int64(len(payload) + 1), http.StatusRequestEntityTooLarge},
+		// Without a Content-Length, the body is cut off while reading
+		{"StreamAboveLimit", io.MultiReader(strings.NewReader(payload), strings.NewReader(" ")), -1, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/convert", test.body)
+			req.ContentLength = test.contentLength
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, test.expectedStatus, resp.Code)
+			if test.expectedStatus == http.StatusRequestEntityTooLarge {
+				assert.Equal(t, `{"error":"`+roman.NewAppError(roman.CodeRequestBodyTooLarge).Error()+`"}`, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestDefaultBodyLimit(t *testing.T) {
+	router := bodyLimitRouter(0)
+
+	body := `{"ranges": [{"min": 1, "max": 3}]}` + strings.Repeat(" ", int(roman.DefaultMaxBodyBytes))
+	req, _ := http.NewRequest("POST", "/convert", strings.NewReader(body))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+}
+
+func TestConvertRangesToRomanJSONProblems(t *testing.T) {
+	router := bodyLimitRouter(0)
+
+	req, _ := http.NewRequest("POST", "/convert", strings.NewReader(`{"ranges": [{"min": 1, "max": 2, "min": 3}]}`))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	var body struct {
+		Error    string              `json:"error"`
+		Problems []roman.JSONProblem `json:"problems"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, roman.NewAppError(roman.CodeInvalidJSONDuplicateKeys).Error(), body.Error)
+	assert.Equal(t, []roman.JSONProblem{{Path: "$.ranges[0].min", Message: "duplicate key 'min'"}}, body.Problems)
+}
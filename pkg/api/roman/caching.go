@@ -0,0 +1,95 @@
+package roman
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheControl is sent with every successful conversion. Results only
+// depend on the request, so they can be cached for a year.
+const CacheControl = "public, max-age=31536000, immutable"
+
+// ConversionETag returns a strong ETag for the conversion of numbers with
+// notation. It is computed from the sorted unique numbers, so requests
+// that normalise to the same set of numbers share the ETag.
+func ConversionETag(notation string, numbers []int) string {
+	unique := make(map[int]struct{}, len(numbers))
+	for _, number := range numbers {
+		unique[number] = struct{}{}
+	}
+	sorted := make([]int, 0, len(unique))
+	for number := range unique {
+		sorted = append(sorted, number)
+	}
+	sort.Ints(sorted)
+	return numbersETag(notation+"|"+FormatJSON+"|", sorted)
+}
+
+// SequenceETag returns a strong ETag for the conversion of numbers with
+// notation, in their order and with their duplicates. It is used for
+// results that are not sorted and unique, which ConversionETag describes.
+func SequenceETag(notation string, numbers []int) string {
+	return numbersETag(notation+"|"+FormatJSON+"|sequence|", numbers)
+}
+
+// numbersETag hashes prefix and numbers into a strong ETag.
+func numbersETag(prefix string, numbers []int) string {
+	hash := sha256.New()
+	hash.Write([]byte(prefix))
+	for _, number := range numbers {
+		hash.Write([]byte(strconv.Itoa(number) + ","))
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// etagMatches reports whether the If-None-Match header matches etag,
+// using the weak comparison of RFC 9110.
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// versionETag returns the ETag of the conversion of numbers, computed by
+// etagOf, e.g. ConversionETag or SequenceETag. v2 responses have a weak
+// ETag of their own.
+func versionETag(c *gin.Context, etagOf func(notation string, numbers []int) string, numbers []int) string {
+	if VersionFrom(c) == Version2 {
+		// The envelope differs from the v1 body, and its timing changes
+		// on every response
+		return "W/" + etagOf(NotationStandard+"|"+Version2, numbers)
+	}
+	return etagOf(NotationStandard, numbers)
+}
+
+// notModified reports whether the client already has the result with
+// etag, in which case it has responded with 304 Not Modified and the
+// caching headers. Other responses get the caching headers from
+// setCacheHeaders once the conversion succeeded, so that errors are not
+// cached.
+func notModified(c *gin.Context, etag string) bool {
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag) {
+		setCacheHeaders(c, etag)
+		c.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// setCacheHeaders sets the ETag and Cache-Control headers of a successful
+// conversion.
+func setCacheHeaders(c *gin.Context, etag string) {
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", CacheControl)
+}
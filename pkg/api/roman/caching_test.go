@@ -0,0 +1,158 @@
+package roman_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+)
+
+func TestConversionETag(t *testing.T) {
+	etag := roman.ConversionETag(roman.NotationStandard, []int{3, 1, 2, 2})
+
+	assert.Equal(t, etag, roman.ConversionETag(roman.NotationStandard, []int{1, 2, 3}), "ETags should only depend on the unique numbers")
+	assert.NotEqual(t, etag, roman.ConversionETag(roman.NotationStandard, []int{1, 2}))
+	assert.NotEqual(t, etag, roman.ConversionETag("other", []int{1, 2, 3}), "ETags should depend on the notation")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+}
+
+func TestSequenceETag(t *testing.T) {
+	etag := roman.SequenceETag(roman.NotationStandard, []int{3, 1, 2, 2})
+
+	assert.NotEqual(t, etag, roman.SequenceETag(roman.NotationStandard, []int{3, 1, 2}), "ETags should depend on the duplicates")
+	assert.NotEqual(t, etag, roman.SequenceETag(roman.NotationStandard, []int{1, 2, 2, 3}), "ETags should depend on the order")
+	assert.NotEqual(t, roman.ConversionETag(roman.NotationStandard, []int{1, 2}), roman.SequenceETag(roman.NotationStandard, []int{1, 2}))
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+}
+
+func cachingRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/convert", roman.ConvertNumbersToRoman)
+	router.POST("/convert", roman.ConvertRangesToRoman)
+	return router
+}
+
+func serveConditional(router http.Handler, method, url, body, ifNoneMatch string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestConditionalGet(t *testing.T) {
+	router := cachingRouter()
+
+	resp := serveConditional(router, "GET", "/convert?numbers=3,1,2", "", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, roman.CacheControl, resp.Header().Get("Cache-Control"))
+	etag := resp.Header().Get("ETag")
+	assert.Equal(t, roman.ConversionETag(roman.NotationStandard, []int{1, 2, 3}), etag)
+
+	tests := []struct {
+		name           string
+		url            string
+		ifNoneMatch    string
+		expectedStatus int
+	}{
+		{"SameRequest", "/convert?numbers=3,1,2", etag, http.StatusNotModified},
+		{"NormalisedRequest", "/convert?numbers=1&numbers=02,3,3", etag, http.StatusNotModified},
+		{"WeakETag", "/convert?numbers=1,2,3", "W/" + etag, http.StatusNotModified},
+		{"ETagList", "/convert?numbers=1,2,3", `"other", ` + etag, http.StatusNotModified},
+		{"Wildcard", "/convert?numbers=1,2,3", "*", http.StatusNotModified},
+		{"DifferentNumbers", "/convert?numbers=1,2", etag, http.StatusOK},
+		{"StaleETag", "/convert?numbers=1,2,3", `"other"`, http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := serveConditional(router, "GET", test.url, "", test.ifNoneMatch)
+			assert.Equal(t, test.expectedStatus, resp.Code)
+			assert.NotEmpty(t, resp.Header().Get("ETag"))
+			assert.Equal(t, roman.CacheControl, resp.Header().Get("Cache-Control"))
+			if test.expectedStatus == http.StatusNotModified {
+				assert.Empty(t, resp.Body.String(), "304 responses should not have a body")
+			}
+		})
+	}
+}
+
+func TestConditionalPost(t *testing.T) {
+	router := cachingRouter()
+
+	resp := serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 1, "max": 3}]}`, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, roman.CacheControl, resp.Header().Get("Cache-Control"))
+	etag := resp.Header().Get("ETag")
+
+	// Ranges covering the same numbers share the ETag, and so does the GET request
+	resp = serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 2, "max": 3}, {"min": 1, "max": 2}]}`, etag)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+	assert.Empty(t, resp.Body.String())
+	assert.Equal(t, etag, serveConditional(router, "GET", "/convert?numbers=1,2,3", "", "").Header().Get("ETag"))
+
+	resp = serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 1, "max": 4}]}`, etag)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEqual(t, etag, resp.Header().Get("ETag"))
+}
+
+func TestConditionalPostOptions(t *testing.T) {
+	router := cachingRouter()
+	etag := serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 1, "max": 3}]}`, "").Header().Get("ETag")
+
+	// Results in another order have an ETag of their own
+	resp := serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 1, "max": 3}], "order": "desc"}`, etag)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	descETag := resp.Header().Get("ETag")
+	assert.Equal(t, roman.SequenceETag(roman.NotationStandard, []int{3, 2, 1}), descETag)
+
+	resp = serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 3, "max": 3}, {"min": 1, "max": 2}], "order": "desc", "dedupe": false}`, descETag)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+
+	// Explicit default options share the ETag of the default results
+	resp = serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 1, "max": 3}], "order": "asc", "dedupe": true}`, etag)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+}
+
+func TestConditionalErrorsNotCached(t *testing.T) {
+	router := cachingRouter()
+
+	resp := serveConditional(router, "GET", "/convert?numbers=0", "", "*")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Empty(t, resp.Header().Get("ETag"))
+	assert.Empty(t, resp.Header().Get("Cache-Control"))
+
+	resp = serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 3, "max": 1}]}`, "*")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Empty(t, resp.Header().Get("ETag"))
+}
+
+func TestConditionalCanceledNotCached(t *testing.T) {
+	router := cachingRouter()
+
+	for code, ctx := range canceledContexts() {
+		t.Run(code, func(t *testing.T) {
+			for _, target := range []string{"/convert?numbers=7", "/convert"} {
+				method, body := "GET", ""
+				if target == "/convert" {
+					method, body = "POST", `{"ranges": [{"min": 1, "max": 3}]}`
+				}
+				req, _ := http.NewRequestWithContext(ctx, method, target, strings.NewReader(body))
+				resp := httptest.NewRecorder()
+				router.ServeHTTP(resp, req)
+
+				assert.Contains(t, resp.Body.String(), code, method)
+				assert.Empty(t, resp.Header().Get("ETag"), method)
+				assert.Empty(t, resp.Header().Get("Cache-Control"), "%s: errors should not be cached for a year", method)
+			}
+		})
+	}
+}
@@ -16,4 +16,23 @@ const (
 	CodeInvalidRangeBounds        = "ERR1009"
 	CodeInValidJSON               = "ERR1010"
 	CodeInValidRangeMissingMinMax = "ERR1011"
+	CodeRequestBodyTooLarge       = "ERR1012"
+	CodeInvalidJSONTrailingData   = "ERR1013"
+	CodeTooManyRanges             = "ERR1014"
+	CodeRangeSpanTooLarge         = "ERR1015"
+	CodeTooManyNumbers            = "ERR1016"
+	CodeRequestTimeout            = "ERR1017"
+	CodeRequestCanceled           = "ERR1018"
+	CodeInvalidRomanNumeral       = "ERR1019"
+	CodeUnsupportedVersion        = "ERR1020"
+	CodeInvalidBatchJSON          = "ERR1021"
+	CodeTooManyOperations         = "ERR1022"
+	CodeInvalidOperation          = "ERR1023"
+	CodeNotANumber                = "ERR1024"
+	CodeEmptyNumber               = "ERR1025"
+	CodeInvalidMode               = "ERR1026"
+	CodeInvalidRangeStep          = "ERR1027"
+	CodeInvalidRangeExclude       = "ERR1028"
+	CodeInvalidOrder              = "ERR1029"
+	CodeInvalidDedupe             = "ERR1030"
 )
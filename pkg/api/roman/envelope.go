@@ -0,0 +1,120 @@
+package roman
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+// newMeta returns the meta of a v2 response with count results.
+func newMeta(c *gin.Context, count int) types.Meta {
+	return types.Meta{Version: Version2, Count: count, DurationMs: elapsed(c)}
+}
+
+// respondWithResults writes the results of a conversion. The meta of v2
+// responses is completed with the count and timing of the request.
+func respondWithResults(c *gin.Context, results []types.RomanNumeral, meta types.Meta) {
+	if VersionFrom(c) != Version2 {
+		c.JSON(http.StatusOK, gin.H{"results": results})
+		return
+	}
+	if results == nil {
+		results = []types.RomanNumeral{}
+	}
+	meta.Version, meta.Count, meta.DurationMs = Version2, len(results), elapsed(c)
+	c.JSON(http.StatusOK, types.ConversionEnvelope{Data: results, Meta: meta, Errors: []types.ErrorDetail{}})
+}
+
+// respondWithPartialResults writes the results of a lenient conversion,
+// with the invalid numbers. v2 responses report them in their errors.
+func respondWithPartialResults(c *gin.Context, results []types.RomanNumeral, invalidNumbers []types.InvalidNumber, meta types.Meta) {
+	if results == nil {
+		results = []types.RomanNumeral{}
+	}
+	if VersionFrom(c) != Version2 {
+		c.JSON(http.StatusOK, types.LenientResponse{Results: results, InvalidNumbers: invalidNumbers})
+		return
+	}
+	details := []types.ErrorDetail{}
+	for i := range invalidNumbers {
+		details = append(details, types.ErrorDetail{Code: invalidNumbers[i].Code, Message: invalidNumbers[i].Message, Value: &invalidNumbers[i].Value})
+	}
+	meta.Version, meta.Count, meta.DurationMs = Version2, len(results), elapsed(c)
+	c.JSON(http.StatusOK, types.ConversionEnvelope{Data: results, Meta: meta, Errors: details})
+}
+
+// respondWithCount writes the result of a dry run, with the limit the
+// conversion would exceed if err is a *LimitError. v2 responses report
+// it in their errors.
+func respondWithCount(c *gin.Context, count types.RangesCount, err error) {
+	var limitErr *LimitError
+	exceeded := errors.As(err, &limitErr)
+	if VersionFrom(c) != Version2 {
+		if exceeded {
+			count.Exceeded = &types.LimitExceeded{Error: limitErr.Error(), Limit: limitErr.Limit, Requested: limitErr.Requested}
+		}
+		c.JSON(http.StatusOK, count)
+		return
+	}
+	meta := newMeta(c, count.Results)
+	meta.Requested, meta.Ranges, meta.DryRun = count.Expanded, count.Ranges, true
+	details := []types.ErrorDetail{}
+	if exceeded {
+		details = errorDetailList(limitErr, errorDetails(limitErr))
+	}
+	c.JSON(http.StatusOK, types.ConversionEnvelope{Data: []types.RomanNumeral{}, Meta: meta, Errors: details})
+}
+
+// respondWithHealth writes the health of the service with status.
+func respondWithHealth(c *gin.Context, status int, health types.HealthResponse) {
+	if VersionFrom(c) != Version2 {
+		c.JSON(status, gin.H{"status": health.Status, "message": health.Message})
+		return
+	}
+	c.JSON(status, types.HealthEnvelope{Data: &health, Meta: newMeta(c, 1), Errors: []types.ErrorDetail{}})
+}
+
+// errorEnvelope returns the v2 response of err, with the errors of
+// errorDetailList.
+func errorEnvelope(c *gin.Context, err error, extra gin.H) types.ConversionEnvelope {
+	return types.ConversionEnvelope{Meta: newMeta(c, 0), Errors: errorDetailList(err, extra)}
+}
+
+// errorDetailList returns the v2 errors of err. The invalid numbers, JSON
+// problems and exceeded limits of extra are reported as one ErrorDetail
+// each.
+func errorDetailList(err error, extra gin.H) []types.ErrorDetail {
+	detail := types.ErrorDetail{Code: errorCode(err), Message: err.Error()}
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		detail.Message = appErr.Message
+		detail.Detail = detailMap[appErr.Code]
+	}
+
+	var details []types.ErrorDetail
+	if invalidNumbers, ok := extra["invalid_numbers"].([]string); ok {
+		for i := range invalidNumbers {
+			invalid := detail
+			invalid.Value = &invalidNumbers[i]
+			details = append(details, invalid)
+		}
+	}
+	if problems, ok := extra["problems"].([]JSONProblem); ok {
+		for _, problem := range problems {
+			invalid := detail
+			invalid.Path, invalid.Detail = problem.Path, problem.Message
+			details = append(details, invalid)
+		}
+	}
+	if limit, ok := extra["limit"].(int); ok {
+		detail.Limit = limit
+		detail.Requested, _ = extra["requested"].(int)
+	}
+	if len(details) == 0 {
+		details = append(details, detail)
+	}
+	return details
+}
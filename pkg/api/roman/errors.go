@@ -10,12 +10,39 @@ var ErrorMap = map[string]string{
 	CodeOutOfBounds:               fmt.Sprintf("input out of bounds, must be between %d and %d", LowerLimit, UpperLimit),
 	CodeFailedReadBody:            "failed to read request body",
 	CodeInvalidRangeJSON:          "invalid JSON: expected 'ranges' key with an array value. Array of 'min' and 'max'. ex. {'ranges': [{'min': 1, 'max': 2}]}",
-	CodeInvalidJSONDuplicateKeys:  "invalid JSON payload: duplicate `ranges` keys",
-	CodeQueryParamInPostRequest:   "invalid request: query parameters not allowed in POST requests",
+	CodeInvalidJSONDuplicateKeys:  "invalid JSON payload: duplicate keys",
+	CodeQueryParamInPostRequest:   "invalid request: only the 'dry_run' query parameter is allowed in POST requests",
 	CodeInvalidRangeMinMoreMax:    "invalid ranges: 'min' should be less than 'max'",
 	CodeInvalidRangeBounds:        fmt.Sprintf("invalid ranges: 'min' and 'max' values must be within %d to %d", LowerLimit, UpperLimit),
 	CodeInValidJSON:               "failed to parse JSON",
 	CodeInValidRangeMissingMinMax: "invalid format: each range must have 'min' and 'max' integers",
+	CodeRequestBodyTooLarge:       "request body too large",
+	CodeInvalidJSONTrailingData:   "invalid JSON payload: unexpected data after the JSON value",
+	CodeTooManyRanges:             "invalid ranges: too many ranges in the request",
+	CodeRangeSpanTooLarge:         "invalid ranges: a range covers too many numbers",
+	CodeTooManyNumbers:            "invalid ranges: the ranges cover too many numbers in total",
+	CodeRequestTimeout:            "request timed out",
+	CodeRequestCanceled:           "request canceled",
+	CodeInvalidRomanNumeral:       "invalid Roman numeral: expected a numeral between I and MMMCMXCIX in standard notation",
+	CodeUnsupportedVersion:        "unsupported API version: the Accept-Version header must be v1 or v2",
+	CodeInvalidBatchJSON:          "invalid JSON: expected 'operations' key with an array value. ex. {'operations': [{'op': 'convert', 'numbers': [1, 2]}]}",
+	CodeTooManyOperations:         "invalid batch: too many operations in the request",
+	CodeInvalidOperation:          "invalid operation: expected 'op' convert with 'numbers', range with 'min' and 'max', or parse or validate with 'numeral'",
+	CodeNotANumber:                "invalid number: expected an integer",
+	CodeEmptyNumber:               "invalid number: the value is empty",
+	CodeInvalidMode:               "invalid mode: the 'mode' query parameter must be 'strict' or 'lenient'",
+	CodeInvalidRangeStep:          "invalid ranges: 'step' must be a positive integer",
+	CodeInvalidRangeExclude:       fmt.Sprintf("invalid ranges: 'exclude' must be an array of integers within %d to %d", LowerLimit, UpperLimit),
+	CodeInvalidOrder:              "invalid order: 'order' must be 'asc', 'desc' or 'input'",
+	CodeInvalidDedupe:             "invalid dedupe: 'dedupe' must be a boolean",
+}
+
+// detailMap holds the details of the errors of v2 responses. The messages
+// of ErrorMap are frozen by the v1 responses, so the query parameters
+// added since are only named in v2.
+var detailMap = map[string]string{
+	CodeInvalidParam:            "the allowed query parameters are 'numbers' and 'mode'",
+	CodeQueryParamInPostRequest: "the allowed query parameters are 'dry_run' and 'count_only'",
 }
 
 // AppError represents a structured error with a code and message
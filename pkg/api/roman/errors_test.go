@@ -51,13 +51,13 @@ func TestNewAppError(t *testing.T) {
 			name:         "InvalidJSONDuplicateKeys",
 			code:         CodeInvalidJSONDuplicateKeys,
 			expectedCode: CodeInvalidJSONDuplicateKeys,
-			expectedMsg:  "invalid JSON payload: duplicate `ranges` keys",
+			expectedMsg:  "invalid JSON payload: duplicate keys",
 		},
 		{
 			name:         "QueryParamInPostRequest",
 			code:         CodeQueryParamInPostRequest,
 			expectedCode: CodeQueryParamInPostRequest,
-			expectedMsg:  "invalid request: query parameters not allowed in POST requests",
+			expectedMsg:  "invalid request: only the 'dry_run' query parameter is allowed in POST requests",
 		},
 		{
 			name:         "CodeInvalidRangeMinMoreMax",
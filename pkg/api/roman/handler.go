@@ -1,8 +1,9 @@
 package roman
 
 import (
+	"context"
 	"encoding/json"
-	"io"
+	"errors"
 	"net/http"
 	"sort"
 	"strconv"
@@ -23,13 +24,29 @@ var converter RomanConverter = &BasicRomanConverter{}
 // @Accept json
 // @Produce json
 // @Success 200 {object} types.HealthResponse "Service is healthy"
+// @Failure 503 {object} types.HealthResponse "Service is not ready"
 // @Router /health [get]
 func Healthcheck(g *gin.Context) {
-	response := gin.H{
-		"status":  "success",
-		"message": "Decimal to Roman Numerals Converter",
+	respondWithHealth(g, http.StatusOK, types.HealthResponse{
+		Status:  "success",
+		Message: "Decimal to Roman Numerals Converter",
+	})
+}
+
+// ReadinessHealthcheck returns a Healthcheck backed by ready. While ready
+// reports false, it responds with 503 Service Unavailable in the same
+// format, e.g. when the server starts or drains.
+func ReadinessHealthcheck(ready func(ctx context.Context) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ready(c.Request.Context()) {
+			respondWithHealth(c, http.StatusServiceUnavailable, types.HealthResponse{
+				Status:  "failure",
+				Message: "Decimal to Roman Numerals Converter is not ready",
+			})
+			return
+		}
+		Healthcheck(c)
 	}
-	g.JSON(http.StatusOK, response)
 }
 
 // ConvertNumbersToRoman handles the API request to convert numbers to Roman numerals.
@@ -38,57 +55,118 @@ func Healthcheck(g *gin.Context) {
 // @Description The response provides a unique, ascending list of Roman numerals. Leading zeroes, leading '+' signs, and extra spaces are supported.
 // @Description For example, /convert?numbers=1,1,2,2,2,3,3 will return results for 1, 2, 3.
 // @Description This endpoint also supports pluralized query formats, such as /convert?numbers=1,2 or /convert?numbers=1&numbers=2,3.
+// @Description With mode=lenient, invalid numbers do not fail the request: they are listed with the code of their error next to the results of the valid ones.
 // @ID convertNumbersToRoman
 // @Accept json
 // @Produce json
 // @Param numbers query string true "Single integer or Comma-separated list of integers to be converted" example("52"; "1,4,9"; "01,02"; "1,52,098,+437")
-// @Success 200 {object} types.RomanNumeralResponse "Successful response"
+// @Param mode query string false "strict (default) fails on any invalid number, lenient converts the valid ones" Enums(strict, lenient)
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Success 200 {object} types.LenientResponse "Successful response"
+// @Success 304 "The result matches the If-None-Match ETag"
 // @Failure 400 {object} types.ErrorResponse "Invalid input"
 // @Router /convert [get]
 func ConvertNumbersToRoman(c *gin.Context) {
 	// Get all query parameters
 	queryParams := c.Request.URL.Query()
 
-	// Check if there are any query parameters other than 'numbers'
+	// Check if there are any query parameters other than 'numbers' and 'mode'
 	for param := range queryParams {
-		if param != "numbers" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": NewAppError(CodeInvalidParam).Error()})
+		if param != "numbers" && param != "mode" {
+			respondWithError(c, http.StatusBadRequest, NewAppError(CodeInvalidParam), nil)
 			return
 		}
 	}
 
+	lenient, err := isLenient(c)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
 	// Get the numbers parameters from the query string
 	numbersParams := c.QueryArray("numbers")
 
 	// Check if the numbers parameter is missing
 	if len(numbersParams) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": NewAppError(CodeMissingNumbersParam).Error()})
+		respondWithError(c, http.StatusBadRequest, NewAppError(CodeMissingNumbersParam), nil)
 		return
 	}
 
 	// Parse and validate the number list
-	numbers, invalidNumbers := ParseNumberList(numbersParams)
+	numbers, invalidNumbers := ParseNumberListDetailed(numbersParams)
 
-	// If there are any invalid numbers, return an error response
-	if len(invalidNumbers) > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":           NewAppError(CodeInvalidInput).Error(),
-			"invalid_numbers": invalidNumbers,
+	// If there are any invalid numbers, return an error response, unless
+	// the client asked for the valid ones only
+	if len(invalidNumbers) > 0 && !lenient {
+		respondWithError(c, http.StatusBadRequest, NewAppError(CodeInvalidInput), gin.H{
+			"invalid_numbers": invalidValues(invalidNumbers),
 		})
 		return
 	}
 
+	// Skip the conversion if the client already has the result. Responses
+	// listing invalid numbers are not cached.
+	etag := ""
+	if len(invalidNumbers) == 0 {
+		etag = versionETag(c, ConversionETag, numbers)
+		if notModified(c, etag) {
+			return
+		}
+	}
+
 	// Convert the numbers to Roman numerals
-	results := ConvertNumbersToRomanNumerals(numbers)
+	results, err := cachedConvertNumbers(c, numbers)
+	if err != nil {
+		respondWithError(c, errorStatus(err), err, nil)
+		return
+	}
+	if etag != "" {
+		setCacheHeaders(c, etag)
+	}
+
+	// Record the domain metrics
+	recorder := recorderFrom(c)
+	recorder.ObserveNumbersConverted("get", len(results))
+	recorder.IncConversion(NotationStandard, FormatJSON)
+	for _, invalid := range invalidNumbers {
+		recorder.IncLenientInvalid(invalid.Code)
+	}
 
 	// Return the results as a JSON response
-	c.JSON(http.StatusOK, gin.H{"results": results})
+	if lenient {
+		respondWithPartialResults(c, results, invalidNumbers, types.Meta{Requested: len(numbers) + len(invalidNumbers)})
+		return
+	}
+	respondWithResults(c, results, types.Meta{Requested: len(numbers)})
+}
+
+// isLenient reports whether the mode query parameter asks for lenient
+// parsing. It fails with CodeInvalidMode for modes other than strict,
+// the default, and lenient.
+func isLenient(c *gin.Context) (bool, error) {
+	mode, ok := c.GetQuery("mode")
+	switch {
+	case !ok || mode == "strict":
+		return false, nil
+	case mode == "lenient":
+		return true, nil
+	}
+	return false, NewAppError(CodeInvalidMode)
 }
 
 // ParseNumberList parses and validates an array of comma-separated list of numbers
 func ParseNumberList(numbersParams []string) ([]int, []string) {
+	numbers, invalidNumbers := ParseNumberListDetailed(numbersParams)
+	return numbers, invalidValues(invalidNumbers)
+}
+
+// ParseNumberListDetailed parses like ParseNumberList, reporting the
+// AppError code of every invalid number: CodeEmptyNumber, CodeNotANumber
+// or CodeOutOfBounds.
+func ParseNumberListDetailed(numbersParams []string) ([]int, []types.InvalidNumber) {
 	var numbers []int
-	var invalidNumbers []string
+	var invalidNumbers []types.InvalidNumber
 
 	// Iterate over each numbers parameter
 	for _, numbersParam := range numbersParams {
@@ -96,13 +174,9 @@ func ParseNumberList(numbersParams []string) ([]int, []string) {
 		for _, numberString := range numberStrings {
 			// Trim spaces
 			numberString = strings.TrimSpace(numberString)
-			if numberString == "" {
-				invalidNumbers = append(invalidNumbers, "")
-				continue // Skip empty strings
-			}
-			number, err := strconv.Atoi(numberString)
-			if err != nil || number < LowerLimit || number > UpperLimit {
-				invalidNumbers = append(invalidNumbers, numberString)
+			number, err := parseNumber(numberString)
+			if err != nil {
+				invalidNumbers = append(invalidNumbers, types.InvalidNumber{Value: numberString, Code: err.Code, Message: err.Message})
 			} else {
 				numbers = append(numbers, number)
 			}
@@ -112,10 +186,49 @@ func ParseNumberList(numbersParams []string) ([]int, []string) {
 	return numbers, invalidNumbers
 }
 
+// parseNumber parses a trimmed number of a number list.
+func parseNumber(numberString string) (int, *AppError) {
+	if numberString == "" {
+		return 0, NewAppError(CodeEmptyNumber)
+	}
+	number, err := strconv.Atoi(numberString)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, NewAppError(CodeOutOfBounds)
+	}
+	if err != nil {
+		return 0, NewAppError(CodeNotANumber)
+	}
+	if number < LowerLimit || number > UpperLimit {
+		return 0, NewAppError(CodeOutOfBounds)
+	}
+	return number, nil
+}
+
+// invalidValues returns the values of invalidNumbers.
+func invalidValues(invalidNumbers []types.InvalidNumber) []string {
+	var values []string
+	for _, invalid := range invalidNumbers {
+		values = append(values, invalid.Value)
+	}
+	return values
+}
+
 // ConvertNumbersToRomanNumerals converts a list of unique numbers to their Roman numeral equivalents
 func ConvertNumbersToRomanNumerals(numbers []int) []types.RomanNumeral {
+	results, _ := ConvertNumbersToRomanNumeralsContext(context.Background(), numbers)
+	return results
+}
+
+// ConvertNumbersToRomanNumeralsContext converts like ConvertNumbersToRomanNumerals,
+// stopping with a *CanceledError once ctx is done.
+func ConvertNumbersToRomanNumeralsContext(ctx context.Context, numbers []int) ([]types.RomanNumeral, error) {
 	uniqueNumbers := make(map[int]struct{})
-	for _, number := range numbers {
+	for i, number := range numbers {
+		if i%cancellationCheckInterval == 0 {
+			if err := checkContext(ctx); err != nil {
+				return nil, err
+			}
+		}
 		uniqueNumbers[number] = struct{}{}
 	}
 
@@ -123,7 +236,11 @@ func ConvertNumbersToRomanNumerals(numbers []int) []types.RomanNumeral {
 	for number := range uniqueNumbers {
 		// todo: need to handle the error thrown by Convert
 		// we never reach to error state as number is always valid
-		roman, _ := converter.Convert(number)
+		roman, err := converter.ConvertContext(ctx, number)
+		var canceledErr *CanceledError
+		if errors.As(err, &canceledErr) {
+			return nil, err
+		}
 		results = append(results, types.RomanNumeral{
 			Decimal: uint(number),
 			Roman:   roman,
@@ -135,66 +252,132 @@ func ConvertNumbersToRomanNumerals(numbers []int) []types.RomanNumeral {
 		return results[i].Decimal < results[j].Decimal
 	})
 
-	return results
-}
-
-// Function to check for duplicate `ranges` keys
-func hasDuplicateRangesKey(data string) error {
-	if strings.Count(data, "\"ranges\"") > 1 {
-		return NewAppError(CodeInvalidJSONDuplicateKeys)
-	}
-	return nil
+	return results, nil
 }
 
 // ConvertRangesToRoman handles the API request to convert ranges of numbers to Roman numerals.
 // @Summary Convert Ranges of Numbers to Roman Numerals
 // @Description This endpoint accepts a JSON request body with multiple ranges of numbers(within the range of 1 to 3999), converting each to its Roman numeral equivalent.
 // @Description Both 'min' and 'max' values in the range are inclusive. For example, the range 1-3 will generate results for 1, 2, and 3.
-// @Description The response provides a unique list of numbers in ascending order from all specified ranges, sorted in ascending order. For example, ranges 3-4 and 2-5 will return results for 2, 3, 4, and 5 only once.
-// @Description Note that leading zeroes and leading '+' signs are not supported due to JSON limitations. Query parameters are not accepted; the request must be sent as a JSON object.
+// @Description A range may only cover every 'step' numbers from 'min', e.g. 1, 6 and 11 for the range 1-12 with a step of 5, and leave out the numbers of its 'exclude' list.
+// @Description By default, the response provides a unique list of numbers in ascending order from all specified ranges. For example, ranges 3-4 and 2-5 will return results for 2, 3, 4, and 5 only once.
+// @Description The 'order' key sorts the results in ascending ('asc') or descending ('desc') order, or keeps the order of the ranges ('input'), while 'dedupe' set to false keeps the numbers covered by several ranges once per range.
+// @Description Note that leading zeroes and leading '+' signs are not supported due to JSON limitations. The request must be sent as a JSON object; the only accepted query parameter is 'dry_run'.
+// @Description The amount of ranges, the span of each range and the total amount of numbers covered are limited. With 'dry_run=true' or 'count_only=true', the response only reports how many results the request would produce, and the limit it would exceed if any.
 // @Description
 // @ID convertRangesToRoman
 // @Accept json
 // @Produce json
-// @Param ranges body types.RangesPayload true "List of number ranges to be converted" example({"ranges": [{"min": 50, "max": 52}, {"min": 10, "max": 12}]})
+// @Param ranges body types.RangesPayload true "List of number ranges to be converted" example({"ranges": [{"min": 50, "max": 52}, {"min": 10, "max": 20, "step": 5, "exclude": [15]}], "order": "desc"})
+// @Param dry_run query bool false "Only report how many results the request would produce"
+// @Param count_only query bool false "Alias of dry_run"
+// @Param If-None-Match header string false "ETag of a previous response"
 // @Success 200 {object} []types.RomanNumeralResponse
-// @Failure 400 {object} types.JsonErrorResponse "Invalid JSON Payload"
+// @Success 304 "The result matches the If-None-Match ETag"
+// @Failure 400 {object} types.JsonErrorResponse "Invalid JSON Payload or ranges exceeding the limits"
 // @Router /convert [post]
 func ConvertRangesToRoman(c *gin.Context) {
 
 	rangesPayload, err := getRangesPayload(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(c, errorStatus(err), err, errorDetails(err))
+		return
+	}
+
+	// Reject requests with too many ranges before looking at them
+	limits := limitsFrom(c)
+	if err := checkRangeCount(rangesPayload.Ranges, limits); err != nil {
+		respondWithError(c, http.StatusBadRequest, err, errorDetails(err))
+		return
+	}
+
+	// Report the amount of results without producing them, and whether
+	// producing them would exceed the other limits
+	if dryRun, _ := isDryRun(c); dryRun {
+		for _, r := range rangesPayload.Ranges {
+			if err := validateRange(r); err != nil {
+				respondWithError(c, http.StatusBadRequest, err, nil)
+				return
+			}
+		}
+		count := CountRanges(rangesPayload)
+		count.DryRun = true
+		respondWithCount(c, count, tracedCheckRangeLimits(c.Request.Context(), rangesPayload, limits))
+		return
+	}
+
+	// Reject requests asking for too much work before expanding the ranges
+	if err := tracedCheckRangeLimits(c.Request.Context(), rangesPayload, limits); err != nil {
+		respondWithError(c, http.StatusBadRequest, err, errorDetails(err))
 		return
 	}
 
 	// Process the ranges to generate a list of numbers
-	numbers, err := ProcessRanges(rangesPayload)
+	numbers, err := cachedProcessRanges(c, rangesPayload)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(c, errorStatus(err), err, nil)
+		return
+	}
+
+	// Skip the conversion if the client already has the result. Results
+	// in another order, or with duplicates, have an ETag of their own
+	etagOf := ConversionETag
+	if !sortedResults(rangesPayload) {
+		etagOf = SequenceETag
+	}
+	etag := versionETag(c, etagOf, numbers)
+	if notModified(c, etag) {
 		return
 	}
 
 	// Convert the numbers to Roman numerals
-	results := ConvertNumbersToRomanNumerals(numbers)
+	results, err := cachedConvertNumbers(c, numbers)
+	if err != nil {
+		respondWithError(c, errorStatus(err), err, nil)
+		return
+	}
+	setCacheHeaders(c, etag)
+	if !sortedResults(rangesPayload) {
+		results = arrangeResults(results, numbers)
+	}
+	if results == nil {
+		// Every number of the ranges was excluded
+		results = []types.RomanNumeral{}
+	}
+
+	// Record the domain metrics
+	recorder := recorderFrom(c)
+	recorder.ObserveRanges(len(rangesPayload.Ranges))
+	requested := 0
+	for _, r := range rangesPayload.Ranges {
+		recorder.ObserveRangeSpan(r.Max - r.Min + 1)
+		requested += rangeCount(r)
+	}
+	recorder.ObserveNumbersConverted("post", len(results))
+	recorder.IncConversion(NotationStandard, FormatJSON)
 
 	// Return the results as a JSON response
-	c.JSON(http.StatusOK, gin.H{"results": results})
+	respondWithResults(c, results, types.Meta{Requested: requested, Ranges: len(rangesPayload.Ranges)})
 }
 
-func getRangesPayload(c *gin.Context) (types.RangesPayload, error) {
+func getRangesPayload(c *gin.Context) (rangesPayload types.RangesPayload, err error) {
 	var payload map[string]interface{}
-	var rangesPayload types.RangesPayload
 
-	// Read the raw request body
-	rawBody, err := io.ReadAll(c.Request.Body)
+	_, span := startSpan(c.Request.Context(), "getRangesPayload")
+	defer func() {
+		span.SetAttributes(attrRangeCount.Int(len(rangesPayload.Ranges)))
+		endSpan(span, err)
+	}()
+
+	// Read the raw request body, up to the body limit
+	rawBody, err := readBody(c)
 	if err != nil {
-		return rangesPayload, NewAppError(CodeFailedReadBody)
+		return rangesPayload, err
 	}
 
-	// Check for duplicate keys
-	if err := hasDuplicateRangesKey(string(rawBody)); err != nil {
-		return rangesPayload, err
+	// Check for duplicate keys at any depth and trailing data
+	if validationErr := ValidateJSON(rawBody); validationErr != nil {
+		return rangesPayload, validationErr
 	}
 
 	// Unmarshal the raw body into a map
@@ -202,56 +385,153 @@ func getRangesPayload(c *gin.Context) (types.RangesPayload, error) {
 		return rangesPayload, NewAppError(CodeInValidJSON)
 	}
 
-	// Return error if we detect query parameters
-	if len(c.Request.URL.Query()) > 0 {
-		return rangesPayload, NewAppError(CodeQueryParamInPostRequest)
+	// Return error if we detect query parameters other than dry_run
+	if _, err := isDryRun(c); err != nil {
+		return rangesPayload, err
 	}
 
-	// Check if the payload contains exactly one key "ranges" and the value is an array
+	// Check if the payload contains the key "ranges" and the value is an array
 	rangesData, ok := payload["ranges"].([]interface{})
-	if !ok || len(payload) != 1 || len(rangesData) == 0 {
+	if !ok || len(rangesData) == 0 {
 		return rangesPayload, NewAppError(CodeInvalidRangeJSON)
 	}
-
-	// Validate each range object
-	for _, item := range rangesData {
-		rangeMap, ok := item.(map[string]interface{})
-		if !ok {
+	for key := range payload {
+		if key != "ranges" && key != "order" && key != "dedupe" {
 			return rangesPayload, NewAppError(CodeInvalidRangeJSON)
 		}
+	}
 
-		min, minOk := rangeMap["min"].(float64)
-		max, maxOk := rangeMap["max"].(float64)
-		if !minOk || !maxOk {
-			return rangesPayload, NewAppError(CodeInValidRangeMissingMinMax)
+	// Read the options of the request, if any
+	if value, found := payload["order"]; found {
+		order, ok := value.(string)
+		if !ok || order == "" || validateOrder(order) != nil {
+			return rangesPayload, NewAppError(CodeInvalidOrder)
 		}
+		rangesPayload.Order = order
+	}
+	if value, found := payload["dedupe"]; found {
+		dedupe, ok := value.(bool)
+		if !ok {
+			return rangesPayload, NewAppError(CodeInvalidDedupe)
+		}
+		rangesPayload.Dedupe = &dedupe
+	}
 
-		// Append to the ranges payload
-		rangesPayload.Ranges = append(rangesPayload.Ranges, types.NumberRange{
-			Min: int(min),
-			Max: int(max),
-		})
+	// Validate each range object
+	for _, item := range rangesData {
+		r, err := parseRange(item)
+		if err != nil {
+			return rangesPayload, err
+		}
+		rangesPayload.Ranges = append(rangesPayload.Ranges, r)
 	}
 
 	// Return the validated ranges payload (you can modify this part as needed for your use case)
 	return rangesPayload, nil
 }
 
-// ProcessRanges processes the ranges and generates a list of numbers
+// isDryRun reports whether the request sets the dry_run query parameter,
+// or its count_only alias. It fails if any other query parameter is set.
+func isDryRun(c *gin.Context) (bool, error) {
+	dryRun := false
+	for param, values := range c.Request.URL.Query() {
+		if param != "dry_run" && param != "count_only" {
+			return false, NewAppError(CodeQueryParamInPostRequest)
+		}
+		for _, value := range values {
+			// A parameter without a value, e.g. "?dry_run", enables it
+			enabled, err := strconv.ParseBool(value)
+			if value == "" {
+				enabled, err = true, nil
+			}
+			if err != nil {
+				return false, NewAppError(CodeQueryParamInPostRequest)
+			}
+			dryRun = dryRun || enabled
+		}
+	}
+	return dryRun, nil
+}
+
+// validateRange checks that r is within bounds and not reversed, and
+// that its step and exclusions are valid.
+func validateRange(r types.NumberRange) error {
+	if r.Min < LowerLimit || r.Max > UpperLimit {
+		return NewAppError(CodeInvalidRangeBounds)
+	}
+	if r.Min > r.Max {
+		return NewAppError(CodeInvalidRangeMinMoreMax)
+	}
+	if r.Step < 0 {
+		return NewAppError(CodeInvalidRangeStep)
+	}
+	for _, number := range r.Exclude {
+		if number < LowerLimit || number > UpperLimit {
+			return NewAppError(CodeInvalidRangeExclude)
+		}
+	}
+	return nil
+}
+
+// ProcessRanges processes the ranges and generates the list of numbers of
+// the results, in the order of the payload. Numbers covered by several
+// ranges are listed once unless the payload keeps duplicates.
 func ProcessRanges(payload types.RangesPayload) ([]int, error) {
-	var numbers []int
+	return ProcessRangesContext(context.Background(), payload)
+}
 
+// ProcessRangesContext processes the ranges like ProcessRanges, stopping
+// with a *CanceledError once ctx is done.
+func ProcessRangesContext(ctx context.Context, payload types.RangesPayload) ([]int, error) {
 	for _, r := range payload.Ranges {
-		if r.Min < LowerLimit || r.Max > UpperLimit {
-			return nil, NewAppError(CodeInvalidRangeBounds)
-		}
-		if r.Min > r.Max {
-			return nil, NewAppError(CodeInvalidRangeMinMoreMax)
+		if err := validateRange(r); err != nil {
+			return nil, err
 		}
-		for i := r.Min; i <= r.Max; i++ {
-			numbers = append(numbers, i)
+	}
+	if err := validateOrder(payload.Order); err != nil {
+		return nil, err
+	}
+	dedupe := dedupes(payload)
+
+	// Count how many times the ranges cover every number, which sorts
+	// them without materialising the duplicates
+	var counts [UpperLimit + 1]int
+	var numbers []int
+	visited := 0
+	for _, r := range payload.Ranges {
+		err := walkRange(r, func(number int) error {
+			if visited%cancellationCheckInterval == 0 {
+				if err := checkContext(ctx); err != nil {
+					return err
+				}
+			}
+			visited++
+			if payload.Order == OrderInput && (!dedupe || counts[number] == 0) {
+				numbers = append(numbers, number)
+			}
+			counts[number]++
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
+	if payload.Order == OrderInput {
+		return numbers, nil
+	}
 
+	for i := LowerLimit; i <= UpperLimit; i++ {
+		number := i
+		if payload.Order == OrderDesc {
+			number = UpperLimit + LowerLimit - i
+		}
+		repeat := counts[number]
+		if dedupe && repeat > 1 {
+			repeat = 1
+		}
+		for ; repeat > 0; repeat-- {
+			numbers = append(numbers, number)
+		}
+	}
 	return numbers, nil
 }
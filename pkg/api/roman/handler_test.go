@@ -2,6 +2,7 @@ package roman_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -37,6 +38,24 @@ func TestHealthcheck(t *testing.T) {
 	assert.JSONEq(t, expectedBody, w.Body.String())
 }
 
+func TestReadinessHealthcheck(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ready := true
+	router := gin.New()
+	router.GET("/health", roman.ReadinessHealthcheck(func(context.Context) bool { return ready }))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"message":"Decimal to Roman Numerals Converter","status":"success"}`, w.Body.String())
+
+	ready = false
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"message":"Decimal to Roman Numerals Converter is not ready","status":"failure"}`, w.Body.String())
+}
+
 func TestConvertNumbersToRoman(t *testing.T) {
 	// Create a Gin router
 	router := gin.Default()
@@ -223,6 +242,82 @@ func TestParseNumberList(t *testing.T) {
 	}
 }
 
+func TestParseNumberListDetailed(t *testing.T) {
+	numbers, invalidNumbers := roman.ParseNumberListDetailed([]string{"1, abc,, 4000", "-1,+2,99999999999999999999,1.5"})
+	assert.Equal(t, []int{1, 2}, numbers)
+
+	codes := map[string]string{}
+	for _, invalid := range invalidNumbers {
+		codes[invalid.Value] = invalid.Code
+		assert.Equal(t, roman.ErrorMap[invalid.Code], invalid.Message)
+	}
+	assert.Equal(t, map[string]string{
+		"abc":                  roman.CodeNotANumber,
+		"":                     roman.CodeEmptyNumber,
+		"4000":                 roman.CodeOutOfBounds,
+		"-1":                   roman.CodeOutOfBounds,
+		"99999999999999999999": roman.CodeOutOfBounds,
+		"1.5":                  roman.CodeNotANumber,
+	}, codes)
+}
+
+func TestConvertNumbersToRomanLenient(t *testing.T) {
+	router := gin.New()
+	router.GET("/convert", roman.ConvertNumbersToRoman)
+	router.GET("/v2/convert", roman.APIVersion(roman.Version2), roman.ConvertNumbersToRoman)
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	w := get("/convert?numbers=4,abc,,4000,1&mode=lenient")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"results": [{"number": 1, "roman": "I"}, {"number": 4, "roman": "IV"}],
+		"invalid_numbers": [
+			{"value": "abc", "code": "ERR1024", "message": "invalid number: expected an integer"},
+			{"value": "", "code": "ERR1025", "message": "invalid number: the value is empty"},
+			{"value": "4000", "code": "ERR1003", "message": "input out of bounds, must be between 1 and 3999"}
+		]
+	}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("ETag"), "partial results should not be cached")
+
+	// Without invalid numbers, the response is the strict one
+	w = get("/convert?numbers=4,1&mode=lenient")
+	assert.Equal(t, http.StatusOK, w.Code)
+	strict := get("/convert?numbers=4,1")
+	assert.Equal(t, strict.Body.String(), w.Body.String())
+	assert.Equal(t, strict.Header().Get("ETag"), w.Header().Get("ETag"))
+
+	// Without valid numbers, there are no results
+	w = get("/convert?numbers=abc&mode=lenient")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"results":[]`)
+
+	// Strict is the default
+	w = get("/convert?numbers=4,abc&mode=strict")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "`+roman.NewAppError(roman.CodeInvalidInput).Error()+`", "invalid_numbers": ["abc"]}`, w.Body.String())
+
+	for _, mode := range []string{"", "Lenient", "partial"} {
+		w = get("/convert?numbers=4&mode=" + mode)
+		assert.Equal(t, http.StatusBadRequest, w.Code, mode)
+		assert.JSONEq(t, `{"error": "`+roman.NewAppError(roman.CodeInvalidMode).Error()+`"}`, w.Body.String(), mode)
+	}
+
+	// v2 reports the invalid numbers in the errors
+	w = get("/v2/convert?numbers=4,abc&mode=lenient")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var envelope types.ConversionEnvelope
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Equal(t, []types.RomanNumeral{{Decimal: 4, Roman: "IV"}}, envelope.Data)
+	assert.Equal(t, 1, envelope.Meta.Count)
+	assert.Equal(t, 2, envelope.Meta.Requested)
+	value := "abc"
+	assert.Equal(t, []types.ErrorDetail{{Code: roman.CodeNotANumber, Message: roman.ErrorMap[roman.CodeNotANumber], Value: &value}}, envelope.Errors)
+}
+
 // TestConvertNumbersToRomanNumerals tests the ConvertNumbersToRomanNumerals function
 func TestConvertNumbersToRomanNumerals(t *testing.T) {
 	tests := []struct {
@@ -336,6 +431,96 @@ func TestProcessRanges(t *testing.T) {
 	}
 }
 
+func TestProcessRangesOptions(t *testing.T) {
+	keep := false
+	tests := []struct {
+		name     string
+		payload  types.RangesPayload
+		expected []int
+	}{
+		{"Step", types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 12, Step: 5}}}, []int{1, 6, 11}},
+		{"LargeStep", types.RangesPayload{Ranges: []types.NumberRange{{Min: 3990, Max: 3999, Step: 1 << 30}}}, []int{3990}},
+		{"Exclude", types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 5, Exclude: []int{2, 4, 100}}}}, []int{1, 3, 5}},
+		{"AllExcluded", types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 2, Exclude: []int{1, 2}}}}, nil},
+		{"Desc", types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 3}, {Min: 2, Max: 4}}, Order: roman.OrderDesc}, []int{4, 3, 2, 1}},
+		{"Input", types.RangesPayload{Ranges: []types.NumberRange{{Min: 5, Max: 6}, {Min: 1, Max: 5}}, Order: roman.OrderInput}, []int{5, 6, 1, 2, 3, 4}},
+		{"InputKeepingDuplicates", types.RangesPayload{Ranges: []types.NumberRange{{Min: 5, Max: 6}, {Min: 1, Max: 5}}, Order: roman.OrderInput, Dedupe: &keep}, []int{5, 6, 1, 2, 3, 4, 5}},
+		{"AscKeepingDuplicates", types.RangesPayload{Ranges: []types.NumberRange{{Min: 2, Max: 3}, {Min: 1, Max: 2}}, Dedupe: &keep}, []int{1, 2, 2, 3}},
+		{"DescKeepingDuplicates", types.RangesPayload{Ranges: []types.NumberRange{{Min: 2, Max: 3}, {Min: 1, Max: 2}}, Order: roman.OrderDesc, Dedupe: &keep}, []int{3, 2, 2, 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			numbers, err := roman.ProcessRanges(test.payload)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, numbers)
+		})
+	}
+
+	errorTests := []struct {
+		name         string
+		payload      types.RangesPayload
+		expectedCode string
+	}{
+		{"NegativeStep", types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 5, Step: -1}}}, roman.CodeInvalidRangeStep},
+		{"ExcludeOutOfBounds", types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 5, Exclude: []int{4000}}}}, roman.CodeInvalidRangeExclude},
+		{"UnknownOrder", types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 5}}, Order: "random"}, roman.CodeInvalidOrder},
+	}
+
+	for _, test := range errorTests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := roman.ProcessRanges(test.payload)
+			assert.Equal(t, roman.NewAppError(test.expectedCode), err)
+		})
+	}
+}
+
+func TestConvertRangesToRomanOptions(t *testing.T) {
+	router := cachingRouter()
+
+	resp := serveConditional(router, http.MethodPost, "/convert", `{"ranges": [{"min": 10, "max": 30, "step": 5, "exclude": [20]}, {"min": 24, "max": 26}], "order": "desc", "dedupe": false}`, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"results": [
+		{"number": 30, "roman": "XXX"}, {"number": 26, "roman": "XXVI"}, {"number": 25, "roman": "XXV"}, {"number": 25, "roman": "XXV"},
+		{"number": 24, "roman": "XXIV"}, {"number": 15, "roman": "XV"}, {"number": 10, "roman": "X"}
+	]}`, resp.Body.String())
+
+	// Ranges whose numbers are all excluded have no results
+	resp = serveConditional(router, http.MethodPost, "/convert", `{"ranges": [{"min": 1, "max": 1, "exclude": [1]}]}`, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"results": []}`, resp.Body.String())
+
+	resp = serveConditional(router, http.MethodPost, "/convert?dry_run=true", `{"ranges": [{"min": 1, "max": 9, "step": 2}, {"min": 3, "max": 6}], "dedupe": false}`, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"dry_run": true, "ranges": 2, "expanded": 9, "count": 9}`, resp.Body.String())
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode string
+	}{
+		{"ZeroStep", `{"ranges": [{"min": 1, "max": 5, "step": 0}]}`, roman.CodeInvalidRangeStep},
+		{"FractionalStep", `{"ranges": [{"min": 1, "max": 5, "step": 1.5}]}`, roman.CodeInvalidRangeStep},
+		{"StringStep", `{"ranges": [{"min": 1, "max": 5, "step": "2"}]}`, roman.CodeInvalidRangeStep},
+		{"ExcludeNotAnArray", `{"ranges": [{"min": 1, "max": 5, "exclude": 3}]}`, roman.CodeInvalidRangeExclude},
+		{"ExcludeNotANumber", `{"ranges": [{"min": 1, "max": 5, "exclude": ["3"]}]}`, roman.CodeInvalidRangeExclude},
+		{"ExcludeOutOfBounds", `{"ranges": [{"min": 1, "max": 5, "exclude": [0]}]}`, roman.CodeInvalidRangeExclude},
+		{"UnknownOrder", `{"ranges": [{"min": 1, "max": 5}], "order": "random"}`, roman.CodeInvalidOrder},
+		{"EmptyOrder", `{"ranges": [{"min": 1, "max": 5}], "order": ""}`, roman.CodeInvalidOrder},
+		{"StringDedupe", `{"ranges": [{"min": 1, "max": 5}], "dedupe": "false"}`, roman.CodeInvalidDedupe},
+		{"UnknownOption", `{"ranges": [{"min": 1, "max": 5}], "limit": 2}`, roman.CodeInvalidRangeJSON},
+		{"OptionsWithoutRanges", `{"order": "desc"}`, roman.CodeInvalidRangeJSON},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := serveConditional(router, http.MethodPost, "/convert", test.body, "")
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+			assert.JSONEq(t, `{"error": "`+roman.NewAppError(test.expectedCode).Error()+`"}`, resp.Body.String())
+		})
+	}
+}
+
 // TestConvertRangesToRoman tests the ConvertRangesToRoman handler function
 func TestConvertRangesToRoman(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -611,7 +796,8 @@ func TestConvertRangesToRomanSpecialCases(t *testing.T) {
 			expectedError: roman.NewAppError(roman.CodeInValidRangeMissingMinMax).Error(),
 		},
 		{
-			name: "InvalidJSON_DuplicateRanges",
+			// An array of payloads has no duplicate keys, it is not an object
+			name: "InvalidJSON_ArrayOfPayloads",
 			input: []types.RangesPayload{
 				{
 					Ranges: []types.NumberRange{
@@ -625,7 +811,7 @@ func TestConvertRangesToRomanSpecialCases(t *testing.T) {
 				},
 			},
 			expected:      nil,
-			expectedError: roman.NewAppError(roman.CodeInvalidJSONDuplicateKeys).Error(),
+			expectedError: roman.NewAppError(roman.CodeInValidJSON).Error(),
 		},
 		{
 			name:          "InvalidJSON_Null",
@@ -0,0 +1,173 @@
+package roman
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// JSONProblem describes a problem found in a JSON document.
+type JSONProblem struct {
+	// Path is the JSONPath of the problem, e.g. "$.ranges[0].min".
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// JSONValidationError is returned for JSON documents with problems.
+// It unwraps to the AppError describing the kind of problem.
+type JSONValidationError struct {
+	Err      *AppError
+	Problems []JSONProblem
+}
+
+func (e *JSONValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *JSONValidationError) Unwrap() error {
+	return e.Err
+}
+
+// MaxJSONDepth is the deepest nesting of objects and arrays ValidateJSON
+// accepts. Request bodies are only a few levels deep, and every level
+// costs a longer path.
+const MaxJSONDepth = 64
+
+// identifierPattern matches keys that can use the dot notation in paths.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// childPath returns the path of key in the object at path.
+func childPath(path, key string) string {
+	if identifierPattern.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+// ValidateJSON checks that data holds exactly one JSON value without
+// duplicate object keys at any depth, nested at most MaxJSONDepth levels.
+// Duplicate keys are all reported, while parsing stops at the first
+// syntax error or at a value nested too deep. It returns nil if no
+// problems were found.
+func ValidateJSON(data []byte) *JSONValidationError {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	validator := &jsonValidator{decoder: decoder}
+	if err := validator.value("$"); err != nil {
+		validator.syntaxProblem(err)
+	} else if end := decoder.InputOffset(); !isEOF(decoder) {
+		validator.problems = append(validator.problems, JSONProblem{
+			Path:    "$",
+			Message: fmt.Sprintf("unexpected data after the JSON value ending at offset %d", end),
+		})
+		if validator.code == "" {
+			validator.code = CodeInvalidJSONTrailingData
+		}
+	}
+
+	if len(validator.problems) == 0 {
+		return nil
+	}
+	return &JSONValidationError{Err: NewAppError(validator.code), Problems: validator.problems}
+}
+
+// isEOF reports whether decoder has no tokens left.
+func isEOF(decoder *json.Decoder) bool {
+	_, err := decoder.Token()
+	return err == io.EOF
+}
+
+// jsonValidator walks the tokens of a JSON document.
+type jsonValidator struct {
+	decoder  *json.Decoder
+	problems []JSONProblem
+	code     string
+	// path is the path of the value being parsed when an error occurs.
+	path string
+	// depth is the amount of objects and arrays around the value being
+	// parsed.
+	depth int
+}
+
+// syntaxProblem reports the error that stopped the parsing. It takes
+// precedence over duplicate keys found before.
+func (v *jsonValidator) syntaxProblem(err error) {
+	message := err.Error()
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		message = "unexpected end of JSON input"
+	}
+	v.problems = append(v.problems, JSONProblem{Path: v.path, Message: message})
+	v.code = CodeInValidJSON
+}
+
+// value parses the value at path.
+func (v *jsonValidator) value(path string) error {
+	v.path = path
+	token, err := v.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'), json.Delim('['):
+		if v.depth == MaxJSONDepth {
+			return fmt.Errorf("exceeded the maximum nesting depth of %d", MaxJSONDepth)
+		}
+		v.depth++
+		defer func() { v.depth-- }()
+		if token == json.Delim('{') {
+			return v.object(path)
+		}
+		return v.array(path)
+	}
+	return nil
+}
+
+// object parses the members of the object at path, after its '{'.
+func (v *jsonValidator) object(path string) error {
+	keys := make(map[string]struct{})
+	for v.decoder.More() {
+		v.path = path
+		token, err := v.decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("expected an object key, got %v", token)
+		}
+
+		keyPath := childPath(path, key)
+		if _, duplicate := keys[key]; duplicate {
+			v.problems = append(v.problems, JSONProblem{Path: keyPath, Message: fmt.Sprintf("duplicate key '%s'", key)})
+			if v.code == "" {
+				v.code = CodeInvalidJSONDuplicateKeys
+			}
+		}
+		keys[key] = struct{}{}
+
+		if err := v.value(keyPath); err != nil {
+			return err
+		}
+	}
+	v.path = path
+	_, err := v.decoder.Token()
+	return err
+}
+
+// array parses the elements of the array at path, after its '['.
+func (v *jsonValidator) array(path string) error {
+	for index := 0; v.decoder.More(); index++ {
+		if err := v.value(path + "[" + strconv.Itoa(index) + "]"); err != nil {
+			return err
+		}
+	}
+	v.path = path
+	_, err := v.decoder.Token()
+	return err
+}
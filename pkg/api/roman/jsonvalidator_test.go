@@ -0,0 +1,121 @@
+package roman_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+)
+
+func TestValidateJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		code     string
+		problems []roman.JSONProblem
+	}{
+		{
+			name:  "Valid",
+			input: `{"ranges": [{"min": 1, "max": 2}, {"min": 3, "max": 4}]}`,
+		},
+		{
+			name:  "ValidScalar",
+			input: ` 42 `,
+		},
+		{
+			name:  "EscapedKeyIsNotDuplicate",
+			input: `{"ranges": [], "note": "\"ranges\""}`,
+		},
+		{
+			name:  "SameKeyInSiblingObjects",
+			input: `{"ranges": [{"min": 1, "max": 2}, {"min": 3, "max": 4}]}`,
+		},
+		{
+			name:     "DuplicateTopLevelKey",
+			input:    `{"ranges": [], "ranges": []}`,
+			code:     roman.CodeInvalidJSONDuplicateKeys,
+			problems: []roman.JSONProblem{{Path: "$.ranges", Message: "duplicate key 'ranges'"}},
+		},
+		{
+			name:  "DuplicateNestedKeys",
+			input: `{"ranges": [{"min": 1, "max": 2}, {"min": 3, "min": 4, "max": 5, "max": 6}]}`,
+			code:  roman.CodeInvalidJSONDuplicateKeys,
+			problems: []roman.JSONProblem{
+				{Path: "$.ranges[1].min", Message: "duplicate key 'min'"},
+				{Path: "$.ranges[1].max", Message: "duplicate key 'max'"},
+			},
+		},
+		{
+			name:     "DuplicateKeyNeedingQuotes",
+			input:    `{"a b": 1, "a b": 2}`,
+			code:     roman.CodeInvalidJSONDuplicateKeys,
+			problems: []roman.JSONProblem{{Path: `$["a b"]`, Message: "duplicate key 'a b'"}},
+		},
+		{
+			name:     "TrailingValue",
+			input:    `{"ranges": []} {"ranges": []}`,
+			code:     roman.CodeInvalidJSONTrailingData,
+			problems: []roman.JSONProblem{{Path: "$", Message: "unexpected data after the JSON value ending at offset 14"}},
+		},
+		{
+			name:     "TrailingGarbage",
+			input:    `{"ranges": []}x`,
+			code:     roman.CodeInvalidJSONTrailingData,
+			problems: []roman.JSONProblem{{Path: "$", Message: "unexpected data after the JSON value ending at offset 14"}},
+		},
+		{
+			name:     "Empty",
+			input:    ``,
+			code:     roman.CodeInValidJSON,
+			problems: []roman.JSONProblem{{Path: "$", Message: "unexpected end of JSON input"}},
+		},
+		{
+			name:     "Truncated",
+			input:    `{"ranges": [{"min": 1`,
+			code:     roman.CodeInValidJSON,
+			problems: []roman.JSONProblem{{Path: "$.ranges[0]", Message: "unexpected end of JSON input"}},
+		},
+		{
+			name:  "MaxDepth",
+			input: strings.Repeat(`{"a":[`, roman.MaxJSONDepth/2) + strings.Repeat(`]}`, roman.MaxJSONDepth/2),
+		},
+		{
+			name:     "TooDeep",
+			input:    `{"a":` + strings.Repeat("[", roman.MaxJSONDepth) + strings.Repeat("]", roman.MaxJSONDepth) + `}`,
+			code:     roman.CodeInValidJSON,
+			problems: []roman.JSONProblem{{Path: "$.a" + strings.Repeat("[0]", roman.MaxJSONDepth-1), Message: "exceeded the maximum nesting depth of 64"}},
+		},
+		{
+			// Parsing stops at the limit, whatever the size of the body
+			name:     "DeeplyNestedBody",
+			input:    strings.Repeat("[", 1<<20),
+			code:     roman.CodeInValidJSON,
+			problems: []roman.JSONProblem{{Path: "$" + strings.Repeat("[0]", roman.MaxJSONDepth), Message: "exceeded the maximum nesting depth of 64"}},
+		},
+		{
+			name:  "SyntaxErrorAfterDuplicate",
+			input: `{"a": 1, "a": 2, "b": }`,
+			code:  roman.CodeInValidJSON,
+			problems: []roman.JSONProblem{
+				{Path: "$.a", Message: "duplicate key 'a'"},
+				{Path: "$.b", Message: "missing value after object key"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := roman.ValidateJSON([]byte(test.input))
+			if test.code == "" {
+				assert.Nil(t, err)
+				return
+			}
+			if assert.NotNil(t, err) {
+				assert.Equal(t, roman.NewAppError(test.code).Error(), err.Error())
+				assert.Equal(t, test.problems, err.Problems)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package roman
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+const limitsKey = "roman.limits"
+
+// Limits bounds the work a single POST request can ask for. The limits
+// of ranges and numbers are shared by all the operations of a batch.
+type Limits struct {
+	// MaxRanges is the amount of ranges a request may contain.
+	MaxRanges int
+	// MaxRangeSpan is the amount of numbers a single range may cover,
+	// once its step and exclusions are applied.
+	MaxRangeSpan int
+	// MaxExpanded is the amount of numbers all the ranges of a request
+	// may cover together, counting overlapping numbers once per range.
+	// The numbers converted by a batch count too.
+	MaxExpanded int
+	// MaxOperations is the amount of operations a batch may contain.
+	MaxOperations int
+}
+
+// DefaultLimits returns the limits used when nothing has been set.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxRanges:     100,
+		MaxRangeSpan:  UpperLimit - LowerLimit + 1,
+		MaxExpanded:   20000,
+		MaxOperations: 100,
+	}
+}
+
+// LimitsFromEnv reads the limits from MAX_RANGES, MAX_RANGE_SPAN,
+// MAX_EXPANDED_NUMBERS and MAX_BATCH_OPERATIONS. Unset, invalid or
+// non-positive values fall back to DefaultLimits.
+func LimitsFromEnv() Limits {
+	limits := DefaultLimits()
+	if value, err := strconv.Atoi(os.Getenv("MAX_RANGES")); err == nil && value > 0 {
+		limits.MaxRanges = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("MAX_RANGE_SPAN")); err == nil && value > 0 {
+		limits.MaxRangeSpan = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("MAX_EXPANDED_NUMBERS")); err == nil && value > 0 {
+		limits.MaxExpanded = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("MAX_BATCH_OPERATIONS")); err == nil && value > 0 {
+		limits.MaxOperations = value
+	}
+	return limits
+}
+
+// RangeLimits applies limits to the requests of the routes it is used on.
+// Non-positive limits fall back to DefaultLimits.
+func RangeLimits(limits Limits) gin.HandlerFunc {
+	defaults := DefaultLimits()
+	if limits.MaxRanges <= 0 {
+		limits.MaxRanges = defaults.MaxRanges
+	}
+	if limits.MaxRangeSpan <= 0 {
+		limits.MaxRangeSpan = defaults.MaxRangeSpan
+	}
+	if limits.MaxExpanded <= 0 {
+		limits.MaxExpanded = defaults.MaxExpanded
+	}
+	if limits.MaxOperations <= 0 {
+		limits.MaxOperations = defaults.MaxOperations
+	}
+	return func(c *gin.Context) {
+		c.Set(limitsKey, limits)
+		c.Next()
+	}
+}
+
+// limitsFrom returns the limits installed by RangeLimits, or DefaultLimits.
+func limitsFrom(c *gin.Context) Limits {
+	if value, ok := c.Get(limitsKey); ok {
+		if limits, ok := value.(Limits); ok {
+			return limits
+		}
+	}
+	return DefaultLimits()
+}
+
+// LimitError is returned for requests exceeding one of the Limits.
+// It unwraps to the AppError describing the exceeded limit.
+type LimitError struct {
+	Err       *AppError
+	Limit     int
+	Requested int
+}
+
+func (e *LimitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+// CountRanges counts the numbers covered by the ranges of payload without
+// producing them. Ranges are expected to be valid.
+func CountRanges(payload types.RangesPayload) types.RangesCount {
+	count := types.RangesCount{Ranges: len(payload.Ranges)}
+	for _, r := range payload.Ranges {
+		count.Expanded += rangeCount(r)
+	}
+	if !dedupes(payload) {
+		count.Results = count.Expanded
+		return count
+	}
+
+	// Mark the covered numbers to count every number once
+	var covered [UpperLimit + 1]bool
+	for _, r := range payload.Ranges {
+		_ = walkRange(r, func(number int) error {
+			if !covered[number] {
+				covered[number] = true
+				count.Results++
+			}
+			return nil
+		})
+	}
+	return count
+}
+
+// CheckRangeLimits checks that the ranges of payload stay within limits,
+// without expanding them. It returns a *LimitError for the first exceeded
+// limit. Invalid ranges are skipped and left to ProcessRanges to report.
+func CheckRangeLimits(payload types.RangesPayload, limits Limits) error {
+	return checkLimits(payload.Ranges, 0, limits)
+}
+
+// checkLimits checks the limits of a request converting ranges and, on
+// top of them, numbers numbers.
+func checkLimits(ranges []types.NumberRange, numbers int, limits Limits) error {
+	if err := checkRangeCount(ranges, limits); err != nil {
+		return err
+	}
+
+	expanded := numbers
+	for _, r := range ranges {
+		if validateRange(r) != nil {
+			continue
+		}
+		span := rangeCount(r)
+		if span > limits.MaxRangeSpan {
+			return &LimitError{Err: NewAppError(CodeRangeSpanTooLarge), Limit: limits.MaxRangeSpan, Requested: span}
+		}
+		expanded += span
+	}
+	if expanded > limits.MaxExpanded {
+		return &LimitError{Err: NewAppError(CodeTooManyNumbers), Limit: limits.MaxExpanded, Requested: expanded}
+	}
+	return nil
+}
+
+// checkRangeCount checks the amount of ranges of a request, which bounds
+// the work of counting them.
+func checkRangeCount(ranges []types.NumberRange, limits Limits) error {
+	if len(ranges) > limits.MaxRanges {
+		return &LimitError{Err: NewAppError(CodeTooManyRanges), Limit: limits.MaxRanges, Requested: len(ranges)}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package roman_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+func limitsRouter(limits *roman.Limits) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if limits != nil {
+		router.Use(roman.RangeLimits(*limits))
+	}
+	router.POST("/convert", roman.ConvertRangesToRoman)
+	return router
+}
+
+func postRanges(router http.Handler, query string, ranges ...types.NumberRange) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(types.RangesPayload{Ranges: ranges})
+	req, _ := http.NewRequest("POST", "/convert"+query, strings.NewReader(string(body)))
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestLimitsFromEnv(t *testing.T) {
+	t.Setenv("MAX_RANGES", "10")
+	t.Setenv("MAX_RANGE_SPAN", "0")
+	t.Setenv("MAX_EXPANDED_NUMBERS", "invalid")
+	t.Setenv("MAX_BATCH_OPERATIONS", "20")
+
+	limits := roman.LimitsFromEnv()
+	assert.Equal(t, 10, limits.MaxRanges)
+	assert.Equal(t, roman.DefaultLimits().MaxRangeSpan, limits.MaxRangeSpan, "non-positive values should fall back to the default")
+	assert.Equal(t, roman.DefaultLimits().MaxExpanded, limits.MaxExpanded, "invalid values should fall back to the default")
+	assert.Equal(t, 20, limits.MaxOperations)
+}
+
+func TestCountRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		ranges   []types.NumberRange
+		expected types.RangesCount
+	}{
+		{"Single", []types.NumberRange{{Min: 1, Max: 10}}, types.RangesCount{Ranges: 1, Expanded: 10, Results: 10}},
+		{"Disjoint", []types.NumberRange{{Min: 20, Max: 25}, {Min: 10, Max: 15}}, types.RangesCount{Ranges: 2, Expanded: 12, Results: 12}},
+		{"Overlapping", []types.NumberRange{{Min: 3, Max: 4}, {Min: 2, Max: 5}}, types.RangesCount{Ranges: 2, Expanded: 6, Results: 4}},
+		{"Adjacent", []types.NumberRange{{Min: 1, Max: 5}, {Min: 5, Max: 9}}, types.RangesCount{Ranges: 2, Expanded: 10, Results: 9}},
+		{"Duplicated", []types.NumberRange{{Min: 1, Max: 3999}, {Min: 1, Max: 3999}}, types.RangesCount{Ranges: 2, Expanded: 7998, Results: 3999}},
+		{"Stepped", []types.NumberRange{{Min: 1, Max: 10, Step: 3}}, types.RangesCount{Ranges: 1, Expanded: 4, Results: 4}},
+		{"Excluded", []types.NumberRange{{Min: 1, Max: 10, Step: 3, Exclude: []int{4, 5, 4, 3999}}}, types.RangesCount{Ranges: 1, Expanded: 3, Results: 3}},
+		{"SteppedOverlapping", []types.NumberRange{{Min: 1, Max: 9, Step: 2}, {Min: 3, Max: 6}}, types.RangesCount{Ranges: 2, Expanded: 9, Results: 7}},
+		{"AllExcluded", []types.NumberRange{{Min: 2, Max: 2, Exclude: []int{2}}}, types.RangesCount{Ranges: 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			payload := types.RangesPayload{Ranges: test.ranges}
+			assert.Equal(t, test.expected, roman.CountRanges(payload))
+
+			numbers, err := roman.ProcessRanges(payload)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected.Results, len(numbers))
+			assert.Equal(t, test.expected.Results, len(roman.ConvertNumbersToRomanNumerals(numbers)))
+
+			// Without dedupe, every covered number is a result
+			dedupe := false
+			payload.Dedupe = &dedupe
+			count := roman.CountRanges(payload)
+			assert.Equal(t, test.expected.Expanded, count.Results)
+			numbers, err = roman.ProcessRanges(payload)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected.Expanded, len(numbers))
+		})
+	}
+}
+
+func TestCheckRangeLimits(t *testing.T) {
+	limits := roman.Limits{MaxRanges: 2, MaxRangeSpan: 10, MaxExpanded: 15}
+
+	tests := []struct {
+		name          string
+		ranges        []types.NumberRange
+		expectedCode  string
+		expectedLimit int
+		requested     int
+	}{
+		{"WithinLimits", []types.NumberRange{{Min: 1, Max: 10}, {Min: 1, Max: 5}}, "", 0, 0},
+		{"TooManyRanges", []types.NumberRange{{Min: 1, Max: 1}, {Min: 2, Max: 2}, {Min: 3, Max: 3}}, roman.CodeTooManyRanges, 2, 3},
+		{"SpanTooLarge", []types.NumberRange{{Min: 1, Max: 11}}, roman.CodeRangeSpanTooLarge, 10, 11},
+		{"TooManyNumbers", []types.NumberRange{{Min: 1, Max: 10}, {Min: 1, Max: 6}}, roman.CodeTooManyNumbers, 15, 16},
+		// Steps and exclusions reduce the numbers a range covers
+		{"SteppedSpan", []types.NumberRange{{Min: 1, Max: 28, Step: 3}}, "", 0, 0},
+		{"ExcludedSpan", []types.NumberRange{{Min: 1, Max: 11, Exclude: []int{11}}}, "", 0, 0},
+		// Invalid ranges are reported by ProcessRanges
+		{"InvalidRange", []types.NumberRange{{Min: 1, Max: 100000}}, "", 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := roman.CheckRangeLimits(types.RangesPayload{Ranges: test.ranges}, limits)
+			if test.expectedCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var limitErr *roman.LimitError
+			require.ErrorAs(t, err, &limitErr)
+			assert.Equal(t, test.expectedCode, limitErr.Err.Code)
+			assert.Equal(t, test.expectedLimit, limitErr.Limit)
+			assert.Equal(t, test.requested, limitErr.Requested)
+		})
+	}
+}
+
+func TestConvertRangesToRomanLimits(t *testing.T) {
+	router := limitsRouter(&roman.Limits{MaxRanges: 2})
+
+	resp := postRanges(router, "", types.NumberRange{Min: 1, Max: 1}, types.NumberRange{Min: 2, Max: 2}, types.NumberRange{Min: 3, Max: 3})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error": "`+roman.NewAppError(roman.CodeTooManyRanges).Error()+`", "limit": 2, "requested": 3}`, resp.Body.String())
+
+	// Unset limits fall back to the defaults
+	full := types.NumberRange{Min: 1, Max: 3999}
+	resp = postRanges(router, "", full, full)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// Without RangeLimits, the default limits apply
+	ranges := []types.NumberRange{full, full, full, full, full, full}
+	resp = postRanges(limitsRouter(nil), "", ranges...)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error": "`+roman.NewAppError(roman.CodeTooManyNumbers).Error()+`", "limit": 20000, "requested": 23994}`, resp.Body.String())
+}
+
+func TestConvertRangesToRomanDryRun(t *testing.T) {
+	router := limitsRouter(&roman.Limits{MaxRangeSpan: 100})
+	ranges := []types.NumberRange{{Min: 3, Max: 4}, {Min: 2, Max: 5}}
+
+	for _, query := range []string{"?dry_run=true", "?dry_run", "?count_only=1", "?dry_run=false&count_only=true"} {
+		t.Run(query, func(t *testing.T) {
+			resp := postRanges(router, query, ranges...)
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.JSONEq(t, `{"dry_run": true, "ranges": 2, "expanded": 6, "count": 4}`, resp.Body.String())
+		})
+	}
+
+	// Dry runs exceeding the limits report the limit with the count
+	resp := postRanges(router, "?count_only", types.NumberRange{Min: 1, Max: 101}, types.NumberRange{Min: 1, Max: 2})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"dry_run": true, "ranges": 2, "expanded": 103, "count": 101,
+		"exceeded": {"error": "`+roman.NewAppError(roman.CodeRangeSpanTooLarge).Error()+`", "limit": 100, "requested": 101}}`, resp.Body.String())
+
+	// A disabled dry run produces the results
+	resp = postRanges(router, "?dry_run=false", ranges...)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"results"`)
+
+	tests := []struct {
+		name         string
+		query        string
+		ranges       []types.NumberRange
+		expectedCode string
+	}{
+		{"InvalidValue", "?dry_run=maybe", ranges, roman.CodeQueryParamInPostRequest},
+		{"OtherParam", "?dry_run=true&numbers=1", ranges, roman.CodeQueryParamInPostRequest},
+		{"TooManyRanges", "?dry_run=true", make([]types.NumberRange, 101), roman.CodeTooManyRanges},
+		{"InvalidRange", "?dry_run=true", []types.NumberRange{{Min: 5, Max: 1}}, roman.CodeInvalidRangeMinMoreMax},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := postRanges(router, test.query, test.ranges...)
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+			assert.Contains(t, resp.Body.String(), test.expectedCode)
+		})
+	}
+}
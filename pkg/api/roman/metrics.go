@@ -0,0 +1,193 @@
+package roman
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
+)
+
+const (
+	metricNumbersConverted = "roman_numbers_converted"
+	metricRangesPerRequest = "roman_ranges_per_request"
+	metricRangeSpan        = "roman_range_span"
+	metricErrorsTotal      = "roman_errors_total"
+	metricConversionsTotal = "roman_conversions_total"
+	metricLenientInvalid   = "roman_lenient_invalid_numbers_total"
+	metricOperationErrors  = "roman_batch_operation_errors_total"
+
+	// NotationStandard is the subtractive notation produced by BasicRomanConverter.
+	NotationStandard = "standard"
+	// FormatJSON is the only response format of the HTTP handlers.
+	FormatJSON = "json"
+
+	recorderKey = "roman.metrics_recorder"
+)
+
+var (
+	numberBuckets = []float64{1, 5, 10, 50, 100, 500, 1000, 2000, 4000}
+	rangeBuckets  = []float64{1, 2, 5, 10, 20, 50, 100}
+)
+
+// MetricsRecorder records domain metrics of the conversion handlers.
+type MetricsRecorder interface {
+	// ObserveNumbersConverted records how many numbers a request converted.
+	ObserveNumbersConverted(endpoint string, count int)
+	// ObserveRanges records how many ranges a POST request contained.
+	ObserveRanges(count int)
+	// ObserveRangeSpan records the size of a single range.
+	ObserveRangeSpan(span int)
+	// IncError counts an error response by its AppError code.
+	IncError(code string)
+	// IncConversion counts a successful conversion by notation and format.
+	IncConversion(notation, format string)
+	// IncLenientInvalid counts an invalid number skipped by a lenient
+	// request by its AppError code. Unlike IncError, the response succeeds.
+	IncLenientInvalid(code string)
+	// IncOperationError counts a failed operation of a batch by operation
+	// and AppError code. Unlike IncError, the batch response succeeds.
+	IncOperationError(op, code string)
+}
+
+// noopRecorder is used when no recorder has been installed.
+type noopRecorder struct{}
+
+func (noopRecorder) ObserveNumbersConverted(string, int) {}
+func (noopRecorder) ObserveRanges(int)                   {}
+func (noopRecorder) ObserveRangeSpan(int)                {}
+func (noopRecorder) IncError(string)                     {}
+func (noopRecorder) IncConversion(string, string)        {}
+func (noopRecorder) IncLenientInvalid(string)            {}
+func (noopRecorder) IncOperationError(string, string)    {}
+
+// monitorRecorder records the domain metrics with a middleware.Monitor.
+type monitorRecorder struct {
+	monitor *middleware.Monitor
+}
+
+// NewMonitorRecorder registers the domain metrics with m and returns
+// a MetricsRecorder that updates them.
+func NewMonitorRecorder(m *middleware.Monitor) MetricsRecorder {
+	_ = m.AddMetric(&middleware.Metric{
+		Type:        middleware.Histogram,
+		Name:        metricNumbersConverted,
+		Description: "the amount of numbers converted per request.",
+		Labels:      []string{"endpoint"},
+		Buckets:     numberBuckets,
+	})
+	_ = m.AddMetric(&middleware.Metric{
+		Type:        middleware.Histogram,
+		Name:        metricRangesPerRequest,
+		Description: "the amount of ranges per POST request.",
+		Buckets:     rangeBuckets,
+	})
+	_ = m.AddMetric(&middleware.Metric{
+		Type:        middleware.Histogram,
+		Name:        metricRangeSpan,
+		Description: "the amount of numbers covered by a single range.",
+		Buckets:     numberBuckets,
+	})
+	_ = m.AddMetric(&middleware.Metric{
+		Type:        middleware.Counter,
+		Name:        metricErrorsTotal,
+		Description: "the error responses by AppError code.",
+		Labels:      []string{"code"},
+	})
+	_ = m.AddMetric(&middleware.Metric{
+		Type:        middleware.Counter,
+		Name:        metricConversionsTotal,
+		Description: "the successful conversions by notation and format.",
+		Labels:      []string{"notation", "format"},
+	})
+	_ = m.AddMetric(&middleware.Metric{
+		Type:        middleware.Counter,
+		Name:        metricLenientInvalid,
+		Description: "the invalid numbers skipped by lenient requests by AppError code.",
+		Labels:      []string{"code"},
+	})
+	_ = m.AddMetric(&middleware.Metric{
+		Type:        middleware.Counter,
+		Name:        metricOperationErrors,
+		Description: "the failed operations of batches by operation and AppError code.",
+		Labels:      []string{"op", "code"},
+	})
+	return &monitorRecorder{monitor: m}
+}
+
+func (r *monitorRecorder) ObserveNumbersConverted(endpoint string, count int) {
+	_ = r.monitor.GetMetric(metricNumbersConverted).Observe([]string{endpoint}, float64(count))
+}
+
+func (r *monitorRecorder) ObserveRanges(count int) {
+	_ = r.monitor.GetMetric(metricRangesPerRequest).Observe(nil, float64(count))
+}
+
+func (r *monitorRecorder) ObserveRangeSpan(span int) {
+	_ = r.monitor.GetMetric(metricRangeSpan).Observe(nil, float64(span))
+}
+
+func (r *monitorRecorder) IncError(code string) {
+	_ = r.monitor.GetMetric(metricErrorsTotal).Inc([]string{code})
+}
+
+func (r *monitorRecorder) IncConversion(notation, format string) {
+	_ = r.monitor.GetMetric(metricConversionsTotal).Inc([]string{notation, format})
+}
+
+func (r *monitorRecorder) IncLenientInvalid(code string) {
+	_ = r.monitor.GetMetric(metricLenientInvalid).Inc([]string{code})
+}
+
+func (r *monitorRecorder) IncOperationError(op, code string) {
+	_ = r.monitor.GetMetric(metricOperationErrors).Inc([]string{op, code})
+}
+
+// Metrics makes recorder available to the handlers of the routes it is used on.
+func Metrics(recorder MetricsRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(recorderKey, recorder)
+		c.Next()
+	}
+}
+
+// recorderFrom returns the recorder installed by Metrics, or a no-op recorder.
+func recorderFrom(c *gin.Context) MetricsRecorder {
+	if value, ok := c.Get(recorderKey); ok {
+		if recorder, ok := value.(MetricsRecorder); ok {
+			return recorder
+		}
+	}
+	return noopRecorder{}
+}
+
+// errorCode returns the AppError code of err, or "unknown".
+func errorCode(err error) string {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return "unknown"
+}
+
+// respondWithError writes a JSON error response and counts the error code.
+// Extra fields are added next to the "error" field, or reported in the
+// errors of v2 responses.
+func respondWithError(c *gin.Context, status int, err error, extra gin.H) {
+	code := errorCode(err)
+	recorderFrom(c).IncError(code)
+	if code == CodeRequestTimeout {
+		middleware.MarkTimedOut(c)
+	}
+
+	if VersionFrom(c) == Version2 {
+		c.JSON(status, errorEnvelope(c, err, extra))
+		return
+	}
+
+	body := gin.H{"error": err.Error()}
+	for key, value := range extra {
+		body[key] = value
+	}
+	c.JSON(status, body)
+}
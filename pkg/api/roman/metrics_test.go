@@ -0,0 +1,171 @@
+package roman_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
+)
+
+// fakeRecorder keeps every recorded value in memory
+type fakeRecorder struct {
+	converted   map[string][]int
+	ranges      []int
+	spans       []int
+	errors      []string
+	conversions []string
+	lenient     []string
+	operations  []string
+}
+
+func newFakeRecorder() *fakeRecorder {
+	return &fakeRecorder{converted: make(map[string][]int)}
+}
+
+func (f *fakeRecorder) ObserveNumbersConverted(endpoint string, count int) {
+	f.converted[endpoint] = append(f.converted[endpoint], count)
+}
+func (f *fakeRecorder) ObserveRanges(count int)   { f.ranges = append(f.ranges, count) }
+func (f *fakeRecorder) ObserveRangeSpan(span int) { f.spans = append(f.spans, span) }
+func (f *fakeRecorder) IncError(code string)      { f.errors = append(f.errors, code) }
+func (f *fakeRecorder) IncConversion(notation, format string) {
+	f.conversions = append(f.conversions, notation+"/"+format)
+}
+func (f *fakeRecorder) IncLenientInvalid(code string) { f.lenient = append(f.lenient, code) }
+func (f *fakeRecorder) IncOperationError(op, code string) {
+	f.operations = append(f.operations, op+"/"+code)
+}
+
+func setupRecordedRouter(recorder roman.MetricsRecorder) *gin.Engine {
+	router := gin.New()
+	router.Use(roman.Metrics(recorder))
+	router.GET("/convert", roman.ConvertNumbersToRoman)
+	router.POST("/convert", roman.ConvertRangesToRoman)
+	router.POST("/batch", roman.ConvertBatch)
+	return router
+}
+
+func TestMetricsRecorderGet(t *testing.T) {
+	recorder := newFakeRecorder()
+	router := setupRecordedRouter(recorder)
+
+	req, _ := http.NewRequest(http.MethodGet, "/convert?numbers=1,2,2,3", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest(http.MethodGet, "/convert?numbers=1,abc", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest(http.MethodGet, "/convert?number=1", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []int{3}, recorder.converted["get"])
+	assert.Equal(t, []string{"standard/json"}, recorder.conversions)
+	assert.Equal(t, []string{roman.CodeInvalidInput, roman.CodeInvalidParam}, recorder.errors)
+	assert.Empty(t, recorder.ranges, "GET requests have no ranges")
+
+	// The invalid numbers of lenient requests are not error responses
+	req, _ = http.NewRequest(http.MethodGet, "/convert?numbers=1,abc,4000&mode=lenient", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []string{roman.CodeInvalidInput, roman.CodeInvalidParam}, recorder.errors)
+	assert.Equal(t, []string{roman.CodeNotANumber, roman.CodeOutOfBounds}, recorder.lenient)
+}
+
+func TestMetricsRecorderPost(t *testing.T) {
+	recorder := newFakeRecorder()
+	router := setupRecordedRouter(recorder)
+
+	payload := `{"ranges": [{"min": 1, "max": 10}, {"min": 5, "max": 14}, {"min": 100, "max": 100}]}`
+	req, _ := http.NewRequest(http.MethodPost, "/convert", strings.NewReader(payload))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest(http.MethodPost, "/convert", strings.NewReader(`{"ranges": [{"min": 10, "max": 5}]}`))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest(http.MethodPost, "/convert", strings.NewReader(`invalid`))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []int{3}, recorder.ranges)
+	assert.Equal(t, []int{10, 10, 1}, recorder.spans)
+	assert.Equal(t, []int{15}, recorder.converted["post"])
+	assert.Equal(t, []string{"standard/json"}, recorder.conversions)
+	assert.Equal(t, []string{roman.CodeInvalidRangeMinMoreMax, roman.CodeInValidJSON}, recorder.errors)
+}
+
+func TestMetricsRecorderBatch(t *testing.T) {
+	recorder := newFakeRecorder()
+	router := setupRecordedRouter(recorder)
+
+	payload := `{"operations": [{"op": "convert", "numbers": [1, 2, 2]}, {"op": "range", "min": 5, "max": 9}, {"op": "parse", "numeral": "IIII"}]}`
+	req, _ := http.NewRequest(http.MethodPost, "/batch", strings.NewReader(payload))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []int{5}, recorder.spans)
+	assert.Equal(t, []int{7}, recorder.converted["batch"])
+	assert.Equal(t, []string{"standard/json"}, recorder.conversions)
+	assert.Equal(t, []string{"parse/" + roman.CodeInvalidRomanNumeral}, recorder.operations, "failed operations should be counted")
+	assert.Empty(t, recorder.errors, "the batch response succeeded")
+
+	// Unknown operations are not used as labels
+	req, _ = http.NewRequest(http.MethodPost, "/batch", strings.NewReader(`{"operations": [{"op": "square", "numbers": [2]}, {"op": "convert", "numbers": [1]}]}`))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, []string{"parse/" + roman.CodeInvalidRomanNumeral, "invalid/" + roman.CodeInvalidOperation}, recorder.operations)
+	assert.Empty(t, recorder.errors)
+
+	// Batches converting nothing are not conversions
+	req, _ = http.NewRequest(http.MethodPost, "/batch", strings.NewReader(`{"operations": [{"op": "parse", "numeral": "IIII"}, {"op": "validate", "numeral": "IIII"}]}`))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, []int{7, 1}, recorder.converted["batch"])
+	assert.Equal(t, []string{"standard/json", "standard/json"}, recorder.conversions)
+}
+
+func TestMetricsWithoutRecorder(t *testing.T) {
+	router := gin.New()
+	router.GET("/convert", roman.ConvertNumbersToRoman)
+
+	// Handlers fall back to a no-op recorder
+	req, _ := http.NewRequest(http.MethodGet, "/convert?numbers=abc", nil)
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestMonitorRecorder(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	monitor := middleware.NewMonitor(middleware.MonitorOptions{Registerer: registry})
+	router := setupRecordedRouter(roman.NewMonitorRecorder(monitor))
+
+	req, _ := http.NewRequest(http.MethodPost, "/convert", strings.NewReader(`{"ranges": [{"min": 1, "max": 4}]}`))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest(http.MethodGet, "/convert?numbers=0", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest(http.MethodGet, "/convert?numbers=1,0&mode=lenient", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	expected := `
+# HELP roman_errors_total the error responses by AppError code.
+# TYPE roman_errors_total counter
+roman_errors_total{code="ERR1002"} 1
+# HELP roman_conversions_total the successful conversions by notation and format.
+# TYPE roman_conversions_total counter
+roman_conversions_total{format="json",notation="standard"} 2
+# HELP roman_lenient_invalid_numbers_total the invalid numbers skipped by lenient requests by AppError code.
+# TYPE roman_lenient_invalid_numbers_total counter
+roman_lenient_invalid_numbers_total{code="ERR1003"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "roman_errors_total", "roman_conversions_total", "roman_lenient_invalid_numbers_total"))
+
+	count, err := testutil.GatherAndCount(registry, "roman_numbers_converted", "roman_ranges_per_request", "roman_range_span")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count, "numbers converted by get and post, ranges and spans")
+}
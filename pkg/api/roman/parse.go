@@ -0,0 +1,9 @@
+package roman
+
+// ParseRoman parses a Roman numeral in the standard notation produced by
+// BasicRomanConverter, e.g. "MCMXCIV", ignoring case and surrounding
+// spaces. Numerals in any other notation, e.g. "IIII" or "IC", are
+// rejected with CodeInvalidRomanNumeral.
+func ParseRoman(numeral string) (int, error) {
+	return converter.Parse(numeral)
+}
@@ -0,0 +1,47 @@
+package roman_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+)
+
+func TestParseRoman(t *testing.T) {
+	tests := []struct {
+		numeral  string
+		expected int
+	}{
+		{"I", 1},
+		{"IV", 4},
+		{"IX", 9},
+		{"XLII", 42},
+		{"MCMXCIV", 1994},
+		{"MMMCMXCIX", 3999},
+		{" mcmxciv ", 1994},
+	}
+
+	for _, test := range tests {
+		number, err := roman.ParseRoman(test.numeral)
+		assert.NoError(t, err, test.numeral)
+		assert.Equal(t, test.expected, number, test.numeral)
+	}
+}
+
+func TestParseRomanInvalid(t *testing.T) {
+	for _, numeral := range []string{"", " ", "IIII", "IC", "VX", "MMMM", "XLIIA", "42", "I V", "IIV", "CMCM"} {
+		_, err := roman.ParseRoman(numeral)
+		assert.Equal(t, roman.NewAppError(roman.CodeInvalidRomanNumeral), err, "%q should be invalid", numeral)
+	}
+}
+
+func TestParseRomanRoundTrip(t *testing.T) {
+	converter := &roman.BasicRomanConverter{}
+	for number := roman.LowerLimit; number <= roman.UpperLimit; number++ {
+		numeral, _ := converter.Convert(number)
+		parsed, err := roman.ParseRoman(numeral)
+		assert.NoError(t, err)
+		assert.Equal(t, number, parsed)
+	}
+}
@@ -0,0 +1,145 @@
+package roman
+
+import (
+	"math"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+// The orders of the results of a request for ranges.
+const (
+	OrderAsc   = "asc"
+	OrderDesc  = "desc"
+	OrderInput = "input"
+)
+
+// parseRange parses a range of a request, decoded without UseNumber.
+// Bounds are left to validateRange to check.
+func parseRange(value interface{}) (types.NumberRange, error) {
+	var r types.NumberRange
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return r, NewAppError(CodeInvalidRangeJSON)
+	}
+
+	min, minOk := fields["min"].(float64)
+	max, maxOk := fields["max"].(float64)
+	if !minOk || !maxOk {
+		return r, NewAppError(CodeInValidRangeMissingMinMax)
+	}
+	r.Min, r.Max = int(min), int(max)
+
+	if value, found := fields["step"]; found {
+		step, ok := floatInt(value)
+		if !ok || step < 1 {
+			return r, NewAppError(CodeInvalidRangeStep)
+		}
+		r.Step = step
+	}
+
+	if value, found := fields["exclude"]; found {
+		values, ok := value.([]interface{})
+		if !ok {
+			return r, NewAppError(CodeInvalidRangeExclude)
+		}
+		for _, value := range values {
+			number, ok := floatInt(value)
+			if !ok {
+				return r, NewAppError(CodeInvalidRangeExclude)
+			}
+			r.Exclude = append(r.Exclude, number)
+		}
+	}
+	return r, nil
+}
+
+// floatInt returns the integer of a JSON number decoded as a float64.
+func floatInt(value interface{}) (int, bool) {
+	number, ok := value.(float64)
+	if !ok || number != math.Trunc(number) || math.Abs(number) > math.MaxInt32 {
+		return 0, false
+	}
+	return int(number), true
+}
+
+// validateOrder checks the order of a request for ranges. The empty
+// order is the default, ascending.
+func validateOrder(order string) error {
+	switch order {
+	case "", OrderAsc, OrderDesc, OrderInput:
+		return nil
+	}
+	return NewAppError(CodeInvalidOrder)
+}
+
+// dedupes reports whether the numbers covered by several ranges of
+// payload are returned once, the default.
+func dedupes(payload types.RangesPayload) bool {
+	return payload.Dedupe == nil || *payload.Dedupe
+}
+
+// sortedResults reports whether the results of payload are sorted and
+// unique, as with the default options.
+func sortedResults(payload types.RangesPayload) bool {
+	return (payload.Order == "" || payload.Order == OrderAsc) && dedupes(payload)
+}
+
+// rangeStep returns the step of r, 1 when unset.
+func rangeStep(r types.NumberRange) int {
+	if r.Step == 0 {
+		return 1
+	}
+	return r.Step
+}
+
+// walkRange calls visit with the numbers of r in ascending order, skipping
+// the excluded ones, until visit fails. r is expected to be valid.
+func walkRange(r types.NumberRange, visit func(number int) error) error {
+	var excluded map[int]struct{}
+	if len(r.Exclude) > 0 {
+		excluded = make(map[int]struct{}, len(r.Exclude))
+		for _, number := range r.Exclude {
+			excluded[number] = struct{}{}
+		}
+	}
+
+	step := rangeStep(r)
+	for number := r.Min; ; number += step {
+		if _, found := excluded[number]; !found {
+			if err := visit(number); err != nil {
+				return err
+			}
+		}
+		// Stop before going past Max, without overflowing on large steps
+		if r.Max-number < step {
+			return nil
+		}
+	}
+}
+
+// rangeCount returns the amount of numbers r covers once its step and
+// exclusions are applied, without walking it. r is expected to be valid.
+func rangeCount(r types.NumberRange) int {
+	step := rangeStep(r)
+	excluded := make(map[int]struct{}, len(r.Exclude))
+	for _, number := range r.Exclude {
+		if number >= r.Min && number <= r.Max && (number-r.Min)%step == 0 {
+			excluded[number] = struct{}{}
+		}
+	}
+	return (r.Max-r.Min)/step + 1 - len(excluded)
+}
+
+// arrangeResults returns the results of the sorted unique numbers in the
+// order of numbers, duplicates included.
+func arrangeResults(results []types.RomanNumeral, numbers []int) []types.RomanNumeral {
+	var romans [UpperLimit + 1]string
+	for _, result := range results {
+		romans[result.Decimal] = result.Roman
+	}
+	arranged := make([]types.RomanNumeral, len(numbers))
+	for i, number := range numbers {
+		arranged[i] = types.RomanNumeral{Decimal: uint(number), Roman: romans[number]}
+	}
+	return arranged
+}
@@ -0,0 +1,136 @@
+package roman
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/cache"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+const cacheKey = "roman.result_cache"
+
+// ResultCache makes results cached in c available to the handlers of
+// the routes it is used on.
+func ResultCache(c *cache.Cache) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(cacheKey, c)
+		ctx.Next()
+	}
+}
+
+// cacheFrom returns the cache installed by ResultCache, or nil.
+func cacheFrom(c *gin.Context) *cache.Cache {
+	if value, ok := c.Get(cacheKey); ok {
+		if resultCache, ok := value.(*cache.Cache); ok {
+			return resultCache
+		}
+	}
+	return nil
+}
+
+// convertNumbersCacheKey returns the canonical key of the conversion
+// of numbers, shared by every request normalising to the same numbers.
+func convertNumbersCacheKey(numbers []int) string {
+	return "roman:convert:" + strings.Trim(ConversionETag(NotationStandard, numbers), `"`)
+}
+
+// processRangesCacheKey returns the canonical key of the expansion of
+// payload, independent of the order of its ranges unless the results
+// keep it.
+func processRangesCacheKey(payload types.RangesPayload) string {
+	ranges := make([]string, 0, len(payload.Ranges))
+	for _, r := range payload.Ranges {
+		key := strconv.Itoa(r.Min) + "-" + strconv.Itoa(r.Max)
+		if r.Step > 1 {
+			key += "/" + strconv.Itoa(r.Step)
+		}
+		if len(r.Exclude) > 0 {
+			excluded := slices.Clone(r.Exclude)
+			slices.Sort(excluded)
+			key += "!" + joinInts(slices.Compact(excluded))
+		}
+		ranges = append(ranges, key)
+	}
+	if payload.Order != OrderInput {
+		sort.Strings(ranges)
+	}
+
+	key := "roman:ranges:" + strings.Join(ranges, ",")
+	if payload.Order != "" && payload.Order != OrderAsc {
+		key += "|order=" + payload.Order
+	}
+	if !dedupes(payload) {
+		key += "|dedupe=false"
+	}
+	return key
+}
+
+// joinInts joins numbers with dots.
+func joinInts(numbers []int) string {
+	texts := make([]string, len(numbers))
+	for i, number := range numbers {
+		texts[i] = strconv.Itoa(number)
+	}
+	return strings.Join(texts, ".")
+}
+
+// cachedConvertNumbers runs tracedConvertNumbers, reusing the result
+// cached by a previous request if any. Errors are not cached.
+func cachedConvertNumbers(c *gin.Context, numbers []int) ([]types.RomanNumeral, error) {
+	resultCache := cacheFrom(c)
+	if resultCache == nil {
+		return tracedConvertNumbers(c.Request.Context(), numbers)
+	}
+
+	var results []types.RomanNumeral
+	key := convertNumbersCacheKey(numbers)
+	if cached(c.Request.Context(), resultCache, key, &results) {
+		return results, nil
+	}
+	results, err := tracedConvertNumbers(c.Request.Context(), numbers)
+	if err == nil {
+		store(c.Request.Context(), resultCache, key, results)
+	}
+	return results, err
+}
+
+// cachedProcessRanges runs tracedProcessRanges, reusing the result
+// cached by a previous request if any. Errors are not cached.
+func cachedProcessRanges(c *gin.Context, payload types.RangesPayload) ([]int, error) {
+	resultCache := cacheFrom(c)
+	if resultCache == nil {
+		return tracedProcessRanges(c.Request.Context(), payload)
+	}
+
+	var numbers []int
+	key := processRangesCacheKey(payload)
+	if cached(c.Request.Context(), resultCache, key, &numbers) {
+		return numbers, nil
+	}
+	numbers, err := tracedProcessRanges(c.Request.Context(), payload)
+	if err == nil {
+		store(c.Request.Context(), resultCache, key, numbers)
+	}
+	return numbers, err
+}
+
+// cached decodes the entry stored under key into value and reports
+// whether it was found. Entries that cannot be decoded count as missing.
+func cached(ctx context.Context, resultCache *cache.Cache, key string, value interface{}) bool {
+	data, found := resultCache.Get(ctx, key)
+	return found && json.Unmarshal(data, value) == nil
+}
+
+// store encodes value and stores it under key.
+func store(ctx context.Context, resultCache *cache.Cache, key string, value interface{}) {
+	if data, err := json.Marshal(value); err == nil {
+		resultCache.Set(ctx, key, data)
+	}
+}
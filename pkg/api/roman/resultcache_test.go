@@ -0,0 +1,109 @@
+package roman_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/cache"
+)
+
+// countingStats counts the cache lookups of the handlers.
+type countingStats struct {
+	hits, misses int
+}
+
+func (s *countingStats) Hit()   { s.hits++ }
+func (s *countingStats) Miss()  { s.misses++ }
+func (s *countingStats) Evict() {}
+
+func resultCacheRouter(stats cache.Stats) (*gin.Engine, *cache.Memory) {
+	gin.SetMode(gin.TestMode)
+	memory := cache.NewMemory(cache.MemoryOptions{})
+	router := gin.New()
+	router.Use(roman.ResultCache(cache.New(memory, cache.Options{Stats: stats})))
+	router.GET("/convert", roman.ConvertNumbersToRoman)
+	router.POST("/convert", roman.ConvertRangesToRoman)
+	return router, memory
+}
+
+func TestResultCacheGet(t *testing.T) {
+	stats := &countingStats{}
+	router, _ := resultCacheRouter(stats)
+
+	first := serveConditional(router, "GET", "/convert?numbers=3,1,2", "", "")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, 0, stats.hits)
+	assert.Equal(t, 1, stats.misses)
+
+	// Requests normalising to the same numbers share the cached result
+	second := serveConditional(router, "GET", "/convert?numbers=1,2,2,3", "", "")
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, 1, stats.hits)
+
+	// Invalid requests never reach the cache
+	serveConditional(router, "GET", "/convert?numbers=0", "", "")
+	assert.Equal(t, 1, stats.hits)
+	assert.Equal(t, 1, stats.misses)
+}
+
+func TestResultCachePost(t *testing.T) {
+	stats := &countingStats{}
+	router, memory := resultCacheRouter(stats)
+
+	first := serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 5, "max": 7}, {"min": 1, "max": 2}]}`, "")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, 2, stats.misses, "both the ranges and the conversion should miss")
+	assert.Equal(t, 2, memory.Len())
+
+	// Reordered ranges hit both cached results
+	second := serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 1, "max": 2}, {"min": 5, "max": 7}]}`, "")
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, 2, stats.hits)
+
+	// Range errors are not cached
+	resp := serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 7, "max": 5}]}`, "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, 2, memory.Len())
+}
+
+func TestResultCachePostOptions(t *testing.T) {
+	stats := &countingStats{}
+	router, _ := resultCacheRouter(stats)
+
+	first := serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 5, "max": 7}, {"min": 1, "max": 2}], "order": "input"}`, "")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, 2, stats.misses)
+
+	// The order of the ranges matters for the input order
+	second := serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 1, "max": 2}, {"min": 5, "max": 7}], "order": "input"}`, "")
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.NotEqual(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, 3, stats.misses, "the ranges should miss")
+	assert.Equal(t, 1, stats.hits, "the conversion of the same numbers should hit")
+
+	// So do the steps and exclusions of the ranges
+	serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 1, "max": 7, "step": 2}]}`, "")
+	serveConditional(router, "POST", "/convert", `{"ranges": [{"min": 1, "max": 7, "step": 2, "exclude": [3]}]}`, "")
+	assert.Equal(t, 7, stats.misses)
+}
+
+func TestResultCacheCorruptEntry(t *testing.T) {
+	stats := &countingStats{}
+	router, memory := resultCacheRouter(stats)
+
+	first := serveConditional(router, "GET", "/convert?numbers=4", "", "")
+	// Overwrite the entry with data that cannot be decoded
+	etag := first.Header().Get("ETag")
+	assert.NoError(t, memory.Set(context.Background(), "roman:convert:"+etag[1:len(etag)-1], []byte("invalid"), 0))
+
+	second := serveConditional(router, "GET", "/convert?numbers=4", "", "")
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String(), "corrupt entries should be recomputed")
+}
@@ -1,7 +1,43 @@
 package roman
 
+import (
+	"context"
+	"fmt"
+)
+
 // Interface for the Roman Converter
-// Converts an integer to its corresponding Roman numeral string.
+// Converts an integer to its corresponding Roman numeral string and back.
+// The handlers only use the conversion library through it, and all its
+// errors are AppErrors.
 type RomanConverter interface {
 	Convert(num int) (string, error)
+	// ConvertContext converts like Convert, failing with a *CanceledError
+	// if ctx is done.
+	ConvertContext(ctx context.Context, num int) (string, error)
+	// Parse converts a Roman numeral back to an integer.
+	Parse(numeral string) (int, error)
+}
+
+// selfTestCases are well known conversions used by SelfTest.
+var selfTestCases = map[int]string{
+	1:    "I",
+	4:    "IV",
+	9:    "IX",
+	1994: "MCMXCIV",
+	3999: "MMMCMXCIX",
+}
+
+// SelfTest verifies that the converter used by the handlers
+// produces the expected result for a set of well known numbers.
+func SelfTest() error {
+	for number, expected := range selfTestCases {
+		result, err := converter.Convert(number)
+		if err != nil {
+			return err
+		}
+		if result != expected {
+			return fmt.Errorf("converter self-test failed: %d converted to %q, expected %q", number, result, expected)
+		}
+	}
+	return nil
 }
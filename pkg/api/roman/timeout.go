@@ -0,0 +1,68 @@
+package roman
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultRequestTimeout is the deadline of requests used when Timeout
+// is given no timeout.
+const DefaultRequestTimeout = 10 * time.Second
+
+// cancellationCheckInterval is the amount of numbers processed between
+// two checks of the context.
+const cancellationCheckInterval = 1024
+
+// CanceledError is returned by the conversion pipeline when its context
+// is done. It unwraps to the AppError describing the cancellation and to
+// the context error, so both errors.As and errors.Is work with it.
+type CanceledError struct {
+	Err   *AppError
+	Cause error
+}
+
+func (e *CanceledError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CanceledError) Unwrap() []error {
+	return []error{e.Err, e.Cause}
+}
+
+// checkContext returns a *CanceledError if ctx is done.
+func checkContext(ctx context.Context) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+	code := CodeRequestCanceled
+	if errors.Is(err, context.DeadlineExceeded) {
+		code = CodeRequestTimeout
+	}
+	return &CanceledError{Err: NewAppError(code), Cause: err}
+}
+
+// Timeout gives the requests of the routes it is used on a deadline of
+// timeout, or DefaultRequestTimeout if it is not positive. The conversion
+// pipeline stops once the deadline passes or the client disconnects,
+// and requests that did not respond by then fail with CodeRequestTimeout
+// or CodeRequestCanceled.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if err := checkContext(ctx); err != nil && !c.Writer.Written() {
+			respondWithError(c, errorStatus(err), err, nil)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package roman_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+// canceledContexts returns a canceled context and a context past its
+// deadline, with the AppError code each one is reported with.
+func canceledContexts() map[string]context.Context {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now())
+	cancel()
+	return map[string]context.Context{
+		roman.CodeRequestCanceled: canceled,
+		roman.CodeRequestTimeout:  expired,
+	}
+}
+
+func assertCanceledError(t *testing.T, err error, ctx context.Context, expectedCode string) {
+	var canceledErr *roman.CanceledError
+	require.ErrorAs(t, err, &canceledErr)
+	var appErr *roman.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, expectedCode, appErr.Code)
+	assert.True(t, errors.Is(err, ctx.Err()), "the context error should be unwrapped")
+}
+
+func TestPipelineContextCanceled(t *testing.T) {
+	payload := types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 3999}}}
+
+	for code, ctx := range canceledContexts() {
+		t.Run(code, func(t *testing.T) {
+			numbers, err := roman.ProcessRangesContext(ctx, payload)
+			assertCanceledError(t, err, ctx, code)
+			assert.Nil(t, numbers)
+
+			results, err := roman.ConvertNumbersToRomanNumeralsContext(ctx, []int{1, 2, 3})
+			assertCanceledError(t, err, ctx, code)
+			assert.Nil(t, results)
+		})
+	}
+
+	// Invalid ranges are still reported as such
+	_, err := roman.ProcessRangesContext(context.Background(), types.RangesPayload{Ranges: []types.NumberRange{{Min: 5, Max: 1}}})
+	assert.Equal(t, roman.NewAppError(roman.CodeInvalidRangeMinMoreMax), err)
+}
+
+func TestPipelineContextLive(t *testing.T) {
+	payload := types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 3999}}}
+	numbers, err := roman.ProcessRangesContext(context.Background(), payload)
+	require.NoError(t, err)
+	assert.Len(t, numbers, 3999)
+
+	results, err := roman.ConvertNumbersToRomanNumeralsContext(context.Background(), numbers)
+	require.NoError(t, err)
+	assert.Equal(t, roman.ConvertNumbersToRomanNumerals(numbers), results)
+}
+
+func timeoutRouter(timeout time.Duration, m *middleware.Monitor) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if m != nil {
+		m.Use(router)
+		router.Use(roman.Metrics(roman.NewMonitorRecorder(m)))
+	}
+	router.Use(roman.Timeout(timeout))
+	router.GET("/convert", roman.ConvertNumbersToRoman)
+	router.POST("/convert", roman.ConvertRangesToRoman)
+	router.GET("/blocking", func(c *gin.Context) {
+		// Ignore the deadline until it has passed, without responding
+		<-c.Request.Context().Done()
+	})
+	return router
+}
+
+func TestTimeout(t *testing.T) {
+	router := timeoutRouter(time.Nanosecond, nil)
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+	}{
+		{"Get", "GET", "/convert?numbers=1,2,3", ""},
+		{"Post", "POST", "/convert", `{"ranges": [{"min": 1, "max": 3999}]}`},
+		{"NotResponding", "GET", "/blocking", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest(test.method, test.url, strings.NewReader(test.body))
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusGatewayTimeout, resp.Code)
+			assert.Equal(t, `{"error":"`+roman.NewAppError(roman.CodeRequestTimeout).Error()+`"}`, resp.Body.String())
+		})
+	}
+}
+
+func TestTimeoutClientCanceled(t *testing.T) {
+	router := timeoutRouter(time.Minute, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/convert?numbers=1", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	assert.Contains(t, resp.Body.String(), roman.CodeRequestCanceled)
+}
+
+func TestTimeoutWithinDeadline(t *testing.T) {
+	// A non-positive timeout uses the default
+	router := timeoutRouter(0, nil)
+
+	req, _ := http.NewRequest("GET", "/convert?numbers=1", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestTimeoutMetrics(t *testing.T) {
+	m := middleware.NewMonitor(middleware.MonitorOptions{Registerer: prometheus.NewRegistry()})
+	router := timeoutRouter(time.Nanosecond, m)
+
+	req, _ := http.NewRequest("GET", "/convert?numbers=1", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	families, err := m.Gatherer().Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			values[family.GetName()] += metric.GetCounter().GetValue()
+		}
+	}
+	assert.Equal(t, float64(1), values["gin_timeout_request_total"])
+	assert.Equal(t, float64(1), values["roman_errors_total"])
+}
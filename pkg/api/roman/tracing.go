@@ -0,0 +1,60 @@
+package roman
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/tracing"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+// Span attribute keys recorded by the conversion pipeline.
+const (
+	attrRangeCount     = attribute.Key("roman.range_count")
+	attrNumberCount    = attribute.Key("roman.number_count")
+	attrResultCount    = attribute.Key("roman.result_count")
+	attrOperationCount = attribute.Key("roman.operation_count")
+)
+
+// startSpan starts a child span of the span stored in ctx.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on the span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedCheckRangeLimits runs CheckRangeLimits inside a child span of ctx.
+func tracedCheckRangeLimits(ctx context.Context, payload types.RangesPayload, limits Limits) error {
+	_, span := startSpan(ctx, "CheckRangeLimits", attrRangeCount.Int(len(payload.Ranges)))
+	err := CheckRangeLimits(payload, limits)
+	endSpan(span, err)
+	return err
+}
+
+// tracedProcessRanges runs ProcessRangesContext inside a child span of ctx.
+func tracedProcessRanges(ctx context.Context, payload types.RangesPayload) ([]int, error) {
+	ctx, span := startSpan(ctx, "ProcessRanges", attrRangeCount.Int(len(payload.Ranges)))
+	numbers, err := ProcessRangesContext(ctx, payload)
+	span.SetAttributes(attrNumberCount.Int(len(numbers)))
+	endSpan(span, err)
+	return numbers, err
+}
+
+// tracedConvertNumbers runs ConvertNumbersToRomanNumeralsContext inside a child span of ctx.
+func tracedConvertNumbers(ctx context.Context, numbers []int) ([]types.RomanNumeral, error) {
+	ctx, span := startSpan(ctx, "ConvertNumbersToRomanNumerals", attrNumberCount.Int(len(numbers)))
+	results, err := ConvertNumbersToRomanNumeralsContext(ctx, numbers)
+	span.SetAttributes(attrResultCount.Int(len(results)))
+	endSpan(span, err)
+	return results, err
+}
@@ -0,0 +1,110 @@
+package roman_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
+)
+
+func setupTracedRouter(t *testing.T) (*gin.Engine, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	router := gin.New()
+	router.Use(middleware.Tracing())
+	router.GET("/convert", roman.ConvertNumbersToRoman)
+	router.POST("/convert", roman.ConvertRangesToRoman)
+	return router, exporter
+}
+
+// spansByName indexes the finished spans by their name
+func spansByName(exporter *tracetest.InMemoryExporter) map[string]tracetest.SpanStub {
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	return spans
+}
+
+func intAttribute(span tracetest.SpanStub, key attribute.Key) int64 {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value.AsInt64()
+		}
+	}
+	return -1
+}
+
+func TestConvertRangesToRomanSpans(t *testing.T) {
+	router, exporter := setupTracedRouter(t)
+
+	payload := `{"ranges": [{"min": 1, "max": 3}, {"min": 2, "max": 5}]}`
+	req, _ := http.NewRequest(http.MethodPost, "/convert", strings.NewReader(payload))
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	spans := spansByName(exporter)
+	server, ok := spans["POST /convert"]
+	assert.True(t, ok, "server span should be recorded")
+
+	for _, name := range []string{"getRangesPayload", "ProcessRanges", "ConvertNumbersToRomanNumerals"} {
+		span, ok := spans[name]
+		assert.True(t, ok, "span %s should be recorded", name)
+		assert.Equal(t, server.SpanContext.SpanID(), span.Parent.SpanID(), "span %s should be a child of the server span", name)
+	}
+
+	assert.Equal(t, int64(2), intAttribute(spans["getRangesPayload"], "roman.range_count"))
+	assert.Equal(t, int64(2), intAttribute(spans["ProcessRanges"], "roman.range_count"))
+	assert.Equal(t, int64(5), intAttribute(spans["ProcessRanges"], "roman.number_count"))
+	assert.Equal(t, int64(5), intAttribute(spans["ConvertNumbersToRomanNumerals"], "roman.number_count"))
+	assert.Equal(t, int64(5), intAttribute(spans["ConvertNumbersToRomanNumerals"], "roman.result_count"))
+}
+
+func TestConvertRangesToRomanSpansError(t *testing.T) {
+	router, exporter := setupTracedRouter(t)
+
+	payload := `{"ranges": [{"min": 10, "max": 5}]}`
+	req, _ := http.NewRequest(http.MethodPost, "/convert", strings.NewReader(payload))
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+
+	spans := spansByName(exporter)
+	assert.Equal(t, codes.Unset, spans["getRangesPayload"].Status.Code)
+	assert.Equal(t, codes.Error, spans["ProcessRanges"].Status.Code)
+	assert.Contains(t, spans["ProcessRanges"].Status.Description, roman.CodeInvalidRangeMinMoreMax)
+	_, converted := spans["ConvertNumbersToRomanNumerals"]
+	assert.False(t, converted, "conversion should not run for invalid ranges")
+}
+
+func TestConvertNumbersToRomanSpans(t *testing.T) {
+	router, exporter := setupTracedRouter(t)
+
+	req, _ := http.NewRequest(http.MethodGet, "/convert?numbers=1,1,2", nil)
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	spans := spansByName(exporter)
+	span, ok := spans["ConvertNumbersToRomanNumerals"]
+	assert.True(t, ok, "conversion span should be recorded")
+	assert.Equal(t, spans["GET /convert"].SpanContext.SpanID(), span.Parent.SpanID())
+	assert.Equal(t, int64(3), intAttribute(span, "roman.number_count"))
+	assert.Equal(t, int64(2), intAttribute(span, "roman.result_count"))
+}
@@ -0,0 +1,88 @@
+package roman
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Version1 is the original API. Its responses never change.
+	Version1 = "v1"
+	// Version2 wraps the responses in the typed envelopes of package types.
+	Version2 = "v2"
+
+	versionKey = "roman.api_version"
+	startKey   = "roman.start"
+)
+
+// Versions lists the supported API versions, oldest first.
+var Versions = []string{Version1, Version2}
+
+// ParseVersion returns the API version named by name, e.g. "v2", "V2"
+// or "2". Other names fail with CodeUnsupportedVersion.
+func ParseVersion(name string) (string, error) {
+	number := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "v")
+	for _, version := range Versions {
+		if number == strings.TrimPrefix(version, "v") {
+			return version, nil
+		}
+	}
+	return "", NewAppError(CodeUnsupportedVersion)
+}
+
+// APIVersion serves the routes it is used on with version.
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(startKey, time.Now())
+		c.Set(versionKey, version)
+		c.Next()
+	}
+}
+
+// NegotiateVersion serves the routes it is used on with the version of
+// the Accept-Version header, or with defaultVersion when the header is
+// not set. The version is sent back in the API-Version header. Requests
+// asking for an unsupported version fail with CodeUnsupportedVersion.
+func NegotiateVersion(defaultVersion string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(startKey, time.Now())
+		c.Set(versionKey, defaultVersion)
+		c.Writer.Header().Add("Vary", "Accept-Version")
+
+		if header := c.GetHeader("Accept-Version"); header != "" {
+			version, err := ParseVersion(header)
+			if err != nil {
+				respondWithError(c, errorStatus(err), err, nil)
+				c.Abort()
+				return
+			}
+			c.Set(versionKey, version)
+		}
+		c.Header("API-Version", VersionFrom(c))
+		c.Next()
+	}
+}
+
+// VersionFrom returns the version installed by APIVersion or
+// NegotiateVersion, or Version1.
+func VersionFrom(c *gin.Context) string {
+	if value, ok := c.Get(versionKey); ok {
+		if version, ok := value.(string); ok {
+			return version
+		}
+	}
+	return Version1
+}
+
+// elapsed returns the time spent on the request since the version was
+// installed, in milliseconds rounded to the microsecond.
+func elapsed(c *gin.Context) float64 {
+	if value, ok := c.Get(startKey); ok {
+		if start, ok := value.(time.Time); ok {
+			return float64(time.Since(start).Microseconds()) / 1000
+		}
+	}
+	return 0
+}
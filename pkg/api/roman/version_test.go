@@ -0,0 +1,79 @@
+package roman_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+func TestParseVersion(t *testing.T) {
+	for name, expected := range map[string]string{"v1": "v1", "1": "v1", " V2 ": "v2", "2": "v2"} {
+		version, err := roman.ParseVersion(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, expected, version, name)
+	}
+	for _, name := range []string{"", "v3", "2.0", "vv2", "latest"} {
+		_, err := roman.ParseVersion(name)
+		assert.Equal(t, roman.NewAppError(roman.CodeUnsupportedVersion), err, "%q should be unsupported", name)
+	}
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(roman.NegotiateVersion(roman.Version2))
+	router.GET("/version", func(c *gin.Context) {
+		c.String(http.StatusOK, roman.VersionFrom(c))
+	})
+
+	serve := func(version string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/version", nil)
+		if version != "" {
+			req.Header.Set("Accept-Version", version)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("")
+	assert.Equal(t, "v2", w.Body.String())
+	assert.Equal(t, "v2", w.Header().Get("API-Version"))
+	assert.Equal(t, "Accept-Version", w.Header().Get("Vary"))
+	assert.Equal(t, "v1", serve("1").Body.String())
+
+	// Unsupported versions fail in the format of the default version
+	w = serve("v9")
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	var envelope types.ConversionEnvelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Equal(t, []types.ErrorDetail{{Code: roman.CodeUnsupportedVersion, Message: roman.ErrorMap[roman.CodeUnsupportedVersion]}}, envelope.Errors)
+}
+
+func TestVersionTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(roman.Timeout(time.Nanosecond))
+	router.GET("/v2/convert", roman.APIVersion(roman.Version2), roman.ConvertNumbersToRoman)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/convert?numbers=1,2,3", nil))
+
+	// Errors written by the middlewares use the envelope too
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	var envelope types.ConversionEnvelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Nil(t, envelope.Data)
+	assert.Equal(t, roman.Version2, envelope.Meta.Version)
+	require.Len(t, envelope.Errors, 1)
+	assert.Equal(t, roman.CodeRequestTimeout, envelope.Errors[0].Code)
+}
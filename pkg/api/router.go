@@ -1,58 +1,241 @@
 package api
 
 import (
-	"net/http"
+	"context"
+	"log"
+	"strings"
+	"time"
 
 	docs "github.com/mrtyormaa/decimal-to-roman-numerals/docs"
 	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/cache"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/health"
 	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
-	swaggerfiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// Options defines the state the router shares with the rest of the process.
+type Options struct {
+	// Health backs the /livez, /readyz and /startupz endpoints. If it is
+	// nil, a registry that is already ready is used.
+	Health *health.Registry
+	// HealthConfig defines the thresholds of the built-in health checks.
+	// The zero value uses health.DefaultConfig.
+	HealthConfig health.Config
+	// Security defines the security headers applied in release mode.
+	// If it is nil, middleware.DefaultSecurityConfig is used.
+	Security *middleware.SecurityConfig
+	// CacheConfig defines the limits of the result cache. The zero value
+	// uses cache.DefaultConfig.
+	CacheConfig cache.Config
+	// CacheBackend stores the cached results, e.g. in a shared Redis.
+	// If it is nil, an in-memory LRU backend limited by CacheConfig is
+	// used, and caching is disabled when CacheConfig.MaxEntries is zero.
+	CacheBackend cache.Backend
+	// MaxBodyBytes limits the size of request bodies. The zero value uses
+	// roman.DefaultMaxBodyBytes.
+	MaxBodyBytes int64
+	// RangeLimits bounds the ranges a POST request may ask for. Zero
+	// fields use the values of roman.DefaultLimits.
+	RangeLimits roman.Limits
+	// RequestTimeout is the deadline of every request, after which the
+	// conversion stops. The zero value uses roman.DefaultRequestTimeout.
+	RequestTimeout time.Duration
+	// Compression defines which responses are compressed. If it is nil,
+	// middleware.DefaultCompressionConfig is used.
+	Compression *middleware.CompressionConfig
+	// Cors defines the cross-origin requests the API accepts. If it is
+	// nil, middleware.DefaultCorsConfig is used.
+	Cors *middleware.CorsConfig
+	// TrustedProxies lists the proxy IPs or CIDRs whose forwarding headers
+	// are trusted to resolve the client IP. No proxy is trusted when it
+	// is empty.
+	TrustedProxies []string
+	// Middleware runs before the handlers of every route, after the
+	// built-in middlewares, e.g. an openapi.Validator in tests.
+	Middleware []gin.HandlerFunc
+	// Docs defines how the documentation is served. If it is nil,
+	// DefaultDocsConfig is used.
+	Docs *DocsConfig
+	// Versions defines how the API versions are served. If it is nil,
+	// DefaultVersionConfig is used.
+	Versions *VersionConfig
+}
+
+// SecurityReport describes the effective security posture of a router
+// created with opts in the current gin mode, one setting per line.
+func SecurityReport(opts Options) []string {
+	proxies := "none"
+	if len(opts.TrustedProxies) > 0 {
+		proxies = strings.Join(opts.TrustedProxies, ", ")
+	}
+	report := []string{
+		"mode: " + gin.Mode(),
+		"trusted proxies: " + proxies,
+		"cors allowed origins: " + strings.Join(corsConfig(opts).AllowOrigins, ", "),
+		docsConfig(opts).Report(gin.Mode()),
+	}
+	if gin.Mode() != gin.ReleaseMode {
+		return append(report, "security headers: disabled outside release mode")
+	}
+	return append(report, securityConfig(opts).Report()...)
+}
+
+// newResultCache creates the result cache configured by opts,
+// or returns nil if caching is disabled.
+func newResultCache(opts Options, m *middleware.Monitor) *cache.Cache {
+	cfg := opts.CacheConfig
+	if cfg == (cache.Config{}) {
+		cfg = cache.DefaultConfig()
+	}
+	// Unset byte limits fall back to the defaults, so the cache is always
+	// bounded in size
+	defaults := cache.DefaultConfig()
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaults.MaxBytes
+	}
+	if cfg.MaxEntryBytes <= 0 {
+		cfg.MaxEntryBytes = defaults.MaxEntryBytes
+	}
+
+	stats := cache.NewMonitorStats(m)
+	backend := opts.CacheBackend
+	if backend == nil {
+		if cfg.MaxEntries == 0 {
+			return nil
+		}
+		backend = cache.NewMemory(cache.MemoryOptions{
+			MaxEntries:    cfg.MaxEntries,
+			MaxBytes:      cfg.MaxBytes,
+			MaxEntryBytes: cfg.MaxEntryBytes,
+			Stats:         stats,
+		})
+	}
+	return cache.New(backend, cache.Options{TTL: cfg.TTL, Stats: stats})
+}
+
+func compressionConfig(opts Options) middleware.CompressionConfig {
+	if opts.Compression == nil {
+		return middleware.DefaultCompressionConfig()
+	}
+	return *opts.Compression
+}
+
+func corsConfig(opts Options) middleware.CorsConfig {
+	if opts.Cors == nil {
+		return middleware.DefaultCorsConfig()
+	}
+	return *opts.Cors
+}
+
+func securityConfig(opts Options) middleware.SecurityConfig {
+	if opts.Security == nil {
+		return middleware.DefaultSecurityConfig()
+	}
+	return *opts.Security
+}
+
 // InitRouter initializes the Gin router with middleware, routes, and Swagger documentation.
 func InitRouter() *gin.Engine {
+	return NewRouter(Options{})
+}
+
+// NewRouter initializes the Gin router like InitRouter, using the given options.
+// It panics if opts.Cors is invalid; validate it at startup instead.
+func NewRouter(opts Options) *gin.Engine {
+	registry := opts.Health
+	if registry == nil {
+		registry = health.NewRegistry()
+		registry.SetReady(true)
+	}
+	healthConfig := opts.HealthConfig
+	if healthConfig == (health.Config{}) {
+		healthConfig = health.DefaultConfig()
+	}
+
 	r := gin.Default()
 
-	// Get global Monitor object and configure it
-	m := middleware.GetMonitor()
+	// Only resolve the client IP from the headers of trusted proxies
+	if err := r.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		log.Printf("invalid trusted proxies, trusting none: %v", err)
+		_ = r.SetTrustedProxies(nil)
+	}
+
+	// Count in-flight requests for the queue depth check
+	inFlight := &middleware.InFlight{}
+	r.Use(inFlight.Handler)
+
+	// Start a server span for every request
+	r.Use(middleware.Tracing())
+
+	// Create a Monitor with its own registry and configure it,
+	// so that every router exposes its own set of metrics
+	m := middleware.NewMonitor(middleware.MonitorOptions{})
 	m.SetMetricPath("/metrics")
 	m.SetSlowTime(10)
 	m.SetDuration([]float64{0.1, 0.3, 1.2, 5, 10})
 
 	// Apply middleware to the router
 	m.Use(r)
+	r.Use(roman.Metrics(roman.NewMonitorRecorder(m)))
+	r.Use(roman.BodyLimit(opts.MaxBodyBytes))
+	r.Use(roman.RangeLimits(opts.RangeLimits))
+
+	// Stop conversions of requests running out of time, counting them
+	// in the Monitor next to the slow requests
+	r.Use(roman.Timeout(opts.RequestTimeout))
+
+	// Compress responses inside the Monitor, so it accounts for the
+	// compressed and uncompressed body sizes
+	r.Use(middleware.CompressionWithConfig(compressionConfig(opts)))
+
+	// Cache conversion results, counting hits, misses and evictions
+	resultCache := newResultCache(opts, m)
+	if resultCache != nil {
+		r.Use(roman.ResultCache(resultCache))
+	}
 	r.Use(gin.Logger())
-	r.Use(middleware.Cors())
+	r.Use(middleware.CorsWithConfig(corsConfig(opts)))
 
 	if gin.Mode() == gin.ReleaseMode {
-		r.Use(middleware.Security())
+		r.Use(middleware.SecurityWithConfig(securityConfig(opts)))
 	}
 
-	// Serve Swagger UI
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+	r.Use(opts.Middleware...)
 
 	// Define API version and base path for Swagger
 	version := "/api/v1"
 	docs.SwaggerInfo.BasePath = version
 
-	// Root endpoint to display message to use API version
-	r.GET("/", func(c *gin.Context) {
-		c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
-	})
+	// Serve Swagger UI, ReDoc and the OpenAPI 3.1 document
+	registerDocs(r, docsConfig(opts))
 
-	// Healthcheck endpoint at the root level
-	r.GET("/health", roman.Healthcheck)
+	// Healthcheck endpoint at the root level, kept as an alias of the
+	// readiness probe below
+	healthcheck := roman.ReadinessHealthcheck(func(ctx context.Context) bool {
+		return registry.Run(ctx, health.Readiness).Status == health.StatusOK
+	})
+	r.GET("/health", healthcheck)
 
-	// Group v1 routes
-	v1 := r.Group(version)
-	{
-		v1.GET("/health", roman.Healthcheck)
-		v1.GET("/convert", roman.ConvertNumbersToRoman)
-		v1.POST("/convert", roman.ConvertRangesToRoman)
+	// Liveness, readiness and startup probes
+	memory := health.MemoryCheck(healthConfig.MemoryLimit, healthConfig.MemoryThreshold)
+	converter := func(context.Context) error { return roman.SelfTest() }
+	registry.Register(health.Liveness, "memory", memory)
+	registry.Register(health.Readiness, "converter", converter)
+	registry.Register(health.Readiness, "queue", health.QueueDepthCheck(inFlight.Depth, healthConfig.MaxQueueDepth))
+	registry.Register(health.Readiness, "memory", memory)
+	if resultCache != nil {
+		registry.Register(health.Readiness, "cache", resultCache.Ping)
 	}
+	registry.Register(health.Startup, "converter", converter)
+	r.GET("/livez", registry.Handler(health.Liveness))
+	r.GET("/readyz", registry.Handler(health.Readiness))
+	r.GET("/startupz", registry.Handler(health.Startup))
+
+	// Serve every API version under its path, and the version of the
+	// Accept-Version header under /api
+	registerVersions(r, versionConfig(opts), healthcheck)
 
 	return r
 }
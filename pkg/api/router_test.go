@@ -5,8 +5,13 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/cache"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/health"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -73,3 +78,180 @@ func TestInitRouter(t *testing.T) {
 		// Add assertions based on the expected response for this endpoint
 	})
 }
+
+func TestInitRouterTwice(t *testing.T) {
+	first := api.InitRouter()
+	second := api.InitRouter()
+
+	req, _ := http.NewRequest("GET", "/api/v1/convert?numbers=1", nil)
+	first.ServeHTTP(httptest.NewRecorder(), req)
+
+	scrape := func(router http.Handler) string {
+		req, _ := http.NewRequest("GET", "/metrics", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), "go_goroutines")
+		return resp.Body.String()
+	}
+
+	// Every router exposes the metrics of its own registry
+	assert.Contains(t, scrape(first), "gin_request_total 1")
+	assert.NotContains(t, scrape(second), "gin_request_total")
+}
+
+func TestNewRouterReadiness(t *testing.T) {
+	registry := health.NewRegistry()
+	router := api.NewRouter(api.Options{Health: registry})
+
+	serve := func(router http.Handler, url string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", url, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Before the server starts, only liveness passes
+	assert.Equal(t, http.StatusOK, serve(router, "/livez").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, serve(router, "/health").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, serve(router, "/readyz").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, serve(router, "/startupz").Code)
+
+	registry.SetReady(true)
+	assert.Equal(t, http.StatusOK, serve(router, "/readyz").Code)
+	assert.Equal(t, http.StatusOK, serve(router, "/startupz").Code)
+
+	assert.Equal(t, http.StatusOK, serve(router, "/health").Code)
+
+	// Shutdown fails readiness only
+	registry.SetReady(false)
+	resp := serve(router, "/readyz?verbose")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"name":"shutdown","status":"failed"`)
+
+	// The legacy health endpoints follow readiness, in their own format
+	for _, url := range []string{"/health", "/api/v1/health", "/api/v2/health"} {
+		resp = serve(router, url)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.Code, url)
+		assert.Contains(t, resp.Body.String(), `"status":"failure"`, url)
+	}
+	assert.Equal(t, http.StatusOK, serve(router, "/startupz").Code)
+	assert.Equal(t, http.StatusOK, serve(router, "/livez").Code)
+
+	// InitRouter is always ready, and reports every check with ?verbose
+	resp = serve(api.InitRouter(), "/readyz?verbose")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	for _, name := range []string{"shutdown", "converter", "queue", "memory"} {
+		assert.Contains(t, resp.Body.String(), `"name":"`+name+`"`)
+	}
+}
+
+func TestNewRouterReleaseMode(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.TestMode)
+
+	security := middleware.DefaultSecurityConfig()
+	security.AllowedHosts = []string{"example.com"}
+	opts := api.Options{Security: &security, TrustedProxies: []string{"10.0.0.1"}}
+	router := api.NewRouter(opts)
+
+	serve := func(host, remoteAddr string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "http://"+host+"/health", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := serve("example.com", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "default-src 'self'", resp.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, http.StatusForbidden, serve("evil.com", "10.0.0.1:1234").Code)
+
+	report := api.SecurityReport(opts)
+	assert.Contains(t, report, "mode: release")
+	assert.Contains(t, report, "trusted proxies: 10.0.0.1")
+	assert.Contains(t, report, "allowed hosts: example.com")
+}
+
+func TestNewRouterTrustedProxies(t *testing.T) {
+	var clientIP string
+	serve := func(opts api.Options, remoteAddr string) string {
+		router := api.NewRouter(opts)
+		router.GET("/ip", func(c *gin.Context) { clientIP = c.ClientIP() })
+		req, _ := http.NewRequest("GET", "/ip", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		return clientIP
+	}
+
+	// Forwarding headers are ignored unless they come from a trusted proxy
+	assert.Equal(t, "192.0.2.1", serve(api.Options{}, "192.0.2.1:1234"))
+	assert.Equal(t, "203.0.113.7", serve(api.Options{TrustedProxies: []string{"192.0.2.0/24"}}, "192.0.2.1:1234"))
+	assert.Equal(t, "192.0.2.1", serve(api.Options{TrustedProxies: []string{"invalid"}}, "192.0.2.1:1234"))
+}
+
+func TestSecurityReportDebugMode(t *testing.T) {
+	gin.SetMode(gin.DebugMode)
+	defer gin.SetMode(gin.TestMode)
+
+	assert.Equal(t, []string{
+		"mode: debug",
+		"trusted proxies: none",
+		"cors allowed origins: http://127.0.0.1, http://127.0.0.1:8001, http://localhost, http://localhost:8001",
+		"docs: enabled, public",
+		"security headers: disabled outside release mode",
+	}, api.SecurityReport(api.Options{}))
+}
+
+func TestNewRouterResultCache(t *testing.T) {
+	router := api.NewRouter(api.Options{CacheConfig: cache.Config{MaxEntries: 1}})
+
+	serve := func(url string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", url, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	serve("/api/v1/convert?numbers=1")
+	serve("/api/v1/convert?numbers=1")
+	serve("/api/v1/convert?numbers=2")
+
+	metrics := serve("/metrics").Body.String()
+	assert.Contains(t, metrics, "cache_hits_total 1")
+	assert.Contains(t, metrics, "cache_misses_total 2")
+	assert.Contains(t, metrics, "cache_evictions_total 1")
+
+	resp := serve("/readyz?verbose")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"name":"cache","status":"ok"`)
+
+	metrics = serve("/metrics").Body.String()
+	assert.Contains(t, metrics, "cache_evictions_total 1", "health checks should not evict entries")
+}
+
+func TestNewRouterResultCacheDisabled(t *testing.T) {
+	router := api.NewRouter(api.Options{CacheConfig: cache.Config{TTL: time.Minute}})
+
+	req, _ := http.NewRequest("GET", "/readyz?verbose", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.NotContains(t, resp.Body.String(), `"name":"cache"`)
+}
+
+func TestInitRouterCompression(t *testing.T) {
+	router := api.InitRouter()
+
+	req, _ := http.NewRequest("POST", "/api/v1/convert", strings.NewReader(`{"ranges": [{"min": 1, "max": 3999}]}`))
+	req.Header.Set("Accept-Encoding", "br, gzip")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "br", resp.Header().Get("Content-Encoding"))
+	assert.Less(t, resp.Body.Len(), 20*1024, "the full range should compress well")
+	assert.True(t, strings.HasPrefix(resp.Header().Get("ETag"), `W/"`))
+}
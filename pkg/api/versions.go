@@ -0,0 +1,140 @@
+package api
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
+)
+
+// VersionConfig defines how the versions of the API are served: every
+// version under its path, e.g. /api/v2/convert, and the version of the
+// Accept-Version header under /api, e.g. /api/convert.
+type VersionConfig struct {
+	// Default is the version of requests to /api without an
+	// Accept-Version header.
+	Default string
+	// V1Deprecation is when v1 was deprecated, sent in the Deprecation
+	// header of every v1 response. The date of DefaultVersionConfig is
+	// used when it is zero.
+	V1Deprecation time.Time
+	// V1Sunset is when v1 stops being served, sent in the Sunset header
+	// of every v1 response. The header is omitted when it is zero.
+	V1Sunset time.Time
+	// V1DeprecationLink is a page documenting the deprecation of v1.
+	V1DeprecationLink string
+}
+
+// v1Deprecation and v1Sunset are the dates announced for v1. They are
+// part of the history of the API rather than of a deployment: v1 was
+// deprecated by the release that introduced v2, on 2026-10-19, and is
+// served for another year, so every deployment announces the same dates
+// unless API_V1_DEPRECATION or API_V1_SUNSET override them.
+var (
+	v1Deprecation = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	v1Sunset      = time.Date(2027, time.October, 19, 0, 0, 0, 0, time.UTC)
+)
+
+// DefaultVersionConfig returns the configuration used when nothing has
+// been set: requests without Accept-Version get v1, which was deprecated
+// when v2 was released and is served for another year.
+func DefaultVersionConfig() VersionConfig {
+	return VersionConfig{
+		Default:       roman.Version1,
+		V1Deprecation: v1Deprecation,
+		V1Sunset:      v1Sunset,
+	}
+}
+
+// VersionConfigFromEnv reads the configuration from API_DEFAULT_VERSION,
+// API_V1_DEPRECATION, API_V1_SUNSET and API_V1_DEPRECATION_LINK. Dates use
+// the RFC 3339 format or its date part, e.g. 2027-10-19, and setting
+// API_V1_SUNSET to an empty value omits the Sunset header. Unset or
+// invalid values fall back to DefaultVersionConfig.
+func VersionConfigFromEnv() VersionConfig {
+	cfg := DefaultVersionConfig()
+	if version, err := roman.ParseVersion(os.Getenv("API_DEFAULT_VERSION")); err == nil {
+		cfg.Default = version
+	}
+	if date, ok := parseDate(os.Getenv("API_V1_DEPRECATION")); ok {
+		cfg.V1Deprecation = date
+	}
+	if value, ok := os.LookupEnv("API_V1_SUNSET"); ok && value == "" {
+		cfg.V1Sunset = time.Time{}
+	} else if date, ok := parseDate(value); ok {
+		cfg.V1Sunset = date
+	}
+	cfg.V1DeprecationLink = os.Getenv("API_V1_DEPRECATION_LINK")
+	return cfg
+}
+
+// parseDate parses an RFC 3339 date and time, or a date at midnight UTC.
+func parseDate(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func versionConfig(opts Options) VersionConfig {
+	if opts.Versions == nil {
+		return DefaultVersionConfig()
+	}
+	cfg := *opts.Versions
+	if version, err := roman.ParseVersion(cfg.Default); err == nil {
+		cfg.Default = version
+	} else {
+		cfg.Default = roman.Version1
+	}
+	if cfg.V1Deprecation.IsZero() {
+		cfg.V1Deprecation = v1Deprecation
+	}
+	return cfg
+}
+
+// successorPath returns the v2 path replacing the path of a v1 request.
+func successorPath(path string) string {
+	if rest, ok := strings.CutPrefix(path, "/api/v1/"); ok {
+		return "/api/v2/" + rest
+	}
+	if rest, ok := strings.CutPrefix(path, "/api/"); ok {
+		return "/api/v2/" + rest
+	}
+	return ""
+}
+
+// registerVersions serves the conversion endpoints of every version under
+// its path, and the version negotiated with Accept-Version under /api.
+// v1 responses are marked as deprecated. Every version serves healthcheck.
+func registerVersions(r *gin.Engine, cfg VersionConfig, healthcheck gin.HandlerFunc) {
+	deprecated := middleware.Deprecation(middleware.DeprecationConfig{
+		Deprecation: cfg.V1Deprecation,
+		Sunset:      cfg.V1Sunset,
+		Link:        cfg.V1DeprecationLink,
+		Successor:   successorPath,
+	})
+	deprecatedV1 := func(c *gin.Context) {
+		if roman.VersionFrom(c) == roman.Version1 {
+			deprecated(c)
+		}
+	}
+
+	registerRoutes(r.Group("/api/v1", roman.APIVersion(roman.Version1), deprecated), healthcheck)
+	registerRoutes(r.Group("/api/v2", roman.APIVersion(roman.Version2)), healthcheck)
+	registerRoutes(r.Group("/api", roman.NegotiateVersion(cfg.Default), deprecatedV1), healthcheck)
+}
+
+// registerRoutes registers the endpoints of a version, with healthcheck
+// as its health endpoint.
+func registerRoutes(group *gin.RouterGroup, healthcheck gin.HandlerFunc) {
+	group.GET("/health", healthcheck)
+	group.GET("/convert", roman.ConvertNumbersToRoman)
+	group.POST("/convert", roman.ConvertRangesToRoman)
+	group.POST("/batch", roman.ConvertBatch)
+}
@@ -0,0 +1,75 @@
+package api_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api"
+)
+
+func TestVersionConfigFromEnv(t *testing.T) {
+	assert.Equal(t, api.DefaultVersionConfig(), api.VersionConfigFromEnv())
+
+	t.Setenv("API_DEFAULT_VERSION", "2")
+	t.Setenv("API_V1_DEPRECATION", "2026-01-02T03:04:05Z")
+	t.Setenv("API_V1_SUNSET", "2027-06-30")
+	t.Setenv("API_V1_DEPRECATION_LINK", "https://example.com/v2")
+	assert.Equal(t, api.VersionConfig{
+		Default:           "v2",
+		V1Deprecation:     time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC),
+		V1Sunset:          time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC),
+		V1DeprecationLink: "https://example.com/v2",
+	}, api.VersionConfigFromEnv())
+
+	// Invalid values fall back to the defaults, and an empty sunset
+	// omits the header
+	t.Setenv("API_DEFAULT_VERSION", "v3")
+	t.Setenv("API_V1_DEPRECATION", "yesterday")
+	t.Setenv("API_V1_SUNSET", "")
+	cfg := api.VersionConfigFromEnv()
+	assert.Equal(t, "v1", cfg.Default)
+	assert.Equal(t, api.DefaultVersionConfig().V1Deprecation, cfg.V1Deprecation)
+	assert.True(t, cfg.V1Sunset.IsZero())
+}
+
+func TestVersions(t *testing.T) {
+	router := api.NewRouter(api.Options{Versions: &api.VersionConfig{
+		Default:           "v2",
+		V1Deprecation:     time.Unix(1700000000, 0),
+		V1DeprecationLink: "https://example.com/v2",
+	}})
+
+	resp := serveDocs(router, "/api/v1/health", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "@1700000000", resp.Header().Get("Deprecation"))
+	assert.Empty(t, resp.Header().Get("Sunset"))
+	assert.Equal(t, []string{
+		`<https://example.com/v2>; rel="deprecation"`,
+		`</api/v2/health>; rel="successor-version"`,
+	}, resp.Header().Values("Link"))
+
+	// The default version is configurable
+	resp = serveDocs(router, "/api/convert?numbers=4", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "v2", resp.Header().Get("API-Version"))
+	assert.Empty(t, resp.Header().Get("Deprecation"))
+	assert.Contains(t, resp.Body.String(), `"data":[{"number":4,"roman":"IV"}]`)
+
+	resp = serveDocs(router, "/api/convert?numbers=4", func(req *http.Request) { req.Header.Set("Accept-Version", "v1") })
+	assert.Equal(t, `{"results":[{"number":4,"roman":"IV"}]}`, resp.Body.String())
+	assert.Equal(t, `</api/v2/convert>; rel="successor-version"`, resp.Header().Values("Link")[1])
+}
+
+func TestVersionsWithoutDeprecation(t *testing.T) {
+	router := api.NewRouter(api.Options{Versions: &api.VersionConfig{Default: "v2"}})
+
+	resp := serveDocs(router, "/api/v1/health", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	expected := "@" + strconv.FormatInt(api.DefaultVersionConfig().V1Deprecation.Unix(), 10)
+	assert.Equal(t, expected, resp.Header().Get("Deprecation"), "a zero date should fall back to the default")
+	assert.Empty(t, resp.Header().Get("Sunset"))
+}
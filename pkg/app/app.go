@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/cache"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/config"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/health"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/server"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/tracing"
+)
+
+// Run starts the server configured by cfg and the environment, and
+// blocks until it has shut down.
+func Run(cfg config.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	gin.SetMode(cfg.Mode)
+
+	// Configure trace export from the environment
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.ConfigFromEnv())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	// Readiness fails while the server drains connections on shutdown
+	registry := health.NewRegistry()
+	security := middleware.SecurityConfigFromEnv()
+	compression := middleware.CompressionConfigFromEnv()
+	cors := middleware.CorsConfigFromEnv()
+	docs := api.DocsConfigFromEnv()
+	versions := api.VersionConfigFromEnv()
+	if err := cors.Validate(); err != nil {
+		return err
+	}
+	opts := api.Options{
+		Health:         registry,
+		HealthConfig:   health.ConfigFromEnv(),
+		CacheConfig:    cache.ConfigFromEnv(),
+		Security:       &security,
+		MaxBodyBytes:   cfg.MaxBodyBytes,
+		RangeLimits:    cfg.Limits,
+		RequestTimeout: cfg.RequestTimeout,
+		Compression:    &compression,
+		Cors:           &cors,
+		TrustedProxies: cfg.TrustedProxies,
+		Docs:           &docs,
+		Versions:       &versions,
+	}
+	r := api.NewRouter(opts)
+
+	// Report the effective security posture at startup
+	for _, line := range api.SecurityReport(opts) {
+		log.Printf("security: %s", line)
+	}
+
+	serverConfig := server.ConfigFromEnv()
+	serverConfig.Addr = fmt.Sprintf(":%d", cfg.Port)
+	return server.ListenAndServe(serverConfig, r, registry)
+}
@@ -0,0 +1,21 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/app"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/config"
+)
+
+func TestRunRejectsInvalidConfig(t *testing.T) {
+	cfg := config.Config{Port: config.DefaultPort, Mode: "production"}
+	assert.EqualError(t, app.Run(cfg), "invalid mode 'production': expected debug, release or test")
+
+	// The CORS policy is validated before the server starts
+	t.Setenv("CORS_ALLOW_ORIGINS", "*")
+	cfg.Mode = gin.TestMode
+	assert.EqualError(t, app.Run(cfg), "CORS origin '*' cannot be combined with credentials")
+}
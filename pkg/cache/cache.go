@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Backend stores cache entries. Implementations must be safe for
+// concurrent use. Shared backends, e.g. Redis, can be plugged in by
+// implementing it.
+type Backend interface {
+	// Get returns the value stored under key and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key. The entry expires after ttl, or never
+	// if ttl is zero.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Stats is notified of cache hits, misses and evictions.
+type Stats interface {
+	Hit()
+	Miss()
+	Evict()
+}
+
+// noopStats is used when no Stats have been set.
+type noopStats struct{}
+
+func (noopStats) Hit()   {}
+func (noopStats) Miss()  {}
+func (noopStats) Evict() {}
+
+// Config defines the limits of the cache.
+type Config struct {
+	// MaxEntries is the amount of entries the in-memory backend keeps
+	// before evicting the least recently used one. The cache is disabled
+	// when it is zero.
+	MaxEntries int
+	// MaxBytes is the size of the entries, keys included, the in-memory
+	// backend keeps before evicting the least recently used ones.
+	MaxBytes int
+	// MaxEntryBytes is the size of the largest entry, key included, the
+	// in-memory backend stores.
+	MaxEntryBytes int
+	// TTL is how long entries are kept. Entries never expire when it is zero.
+	TTL time.Duration
+}
+
+// DefaultConfig returns the limits used when nothing has been set.
+func DefaultConfig() Config {
+	return Config{
+		MaxEntries:    10000,
+		MaxBytes:      64 << 20,
+		MaxEntryBytes: 1 << 20,
+		TTL:           time.Hour,
+	}
+}
+
+// ConfigFromEnv reads the limits from CACHE_MAX_ENTRIES, CACHE_MAX_BYTES,
+// CACHE_MAX_ENTRY_BYTES and CACHE_TTL. CACHE_TTL uses the
+// time.ParseDuration format. Unset or invalid values fall back to
+// DefaultConfig, and so do non-positive byte limits.
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	if value, err := strconv.Atoi(os.Getenv("CACHE_MAX_ENTRIES")); err == nil && value >= 0 {
+		cfg.MaxEntries = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("CACHE_MAX_BYTES")); err == nil && value > 0 {
+		cfg.MaxBytes = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("CACHE_MAX_ENTRY_BYTES")); err == nil && value > 0 {
+		cfg.MaxEntryBytes = value
+	}
+	if value, err := time.ParseDuration(os.Getenv("CACHE_TTL")); err == nil && value >= 0 {
+		cfg.TTL = value
+	}
+	return cfg
+}
+
+// Options defines the behaviour of a Cache.
+type Options struct {
+	// TTL is passed to the backend for every entry.
+	TTL time.Duration
+	// Stats is notified of hits and misses. It may be nil.
+	Stats Stats
+}
+
+// Cache reads and writes entries of a Backend, counting hits and misses.
+// Backend errors are logged and treated as misses, so a failing shared
+// backend never fails a request.
+type Cache struct {
+	backend Backend
+	ttl     time.Duration
+	stats   Stats
+}
+
+// New creates a Cache storing its entries in backend.
+func New(backend Backend, opts Options) *Cache {
+	stats := opts.Stats
+	if stats == nil {
+		stats = noopStats{}
+	}
+	return &Cache{backend: backend, ttl: opts.TTL, stats: stats}
+}
+
+// Get returns the value stored under key and whether it was found.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool) {
+	value, found, err := c.backend.Get(ctx, key)
+	if err != nil {
+		log.Printf("cache: failed to get '%s': %v", key, err)
+	}
+	if err != nil || !found {
+		c.stats.Miss()
+		return nil, false
+	}
+	c.stats.Hit()
+	return value, true
+}
+
+// Set stores value under key.
+func (c *Cache) Set(ctx context.Context, key string, value []byte) {
+	if err := c.backend.Set(ctx, key, value, c.ttl); err != nil {
+		log.Printf("cache: failed to set '%s': %v", key, err)
+	}
+}
+
+// pingKey is written and read back by Ping.
+const pingKey = "cache:ping"
+
+// Pinger is implemented by backends that can check their own health
+// without writing an entry.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks the health of the backend, using its Pinger implementation
+// if any, and otherwise checking that it can store and return an entry.
+// It can be used as a health check.
+func (c *Cache) Ping(ctx context.Context) error {
+	if pinger, ok := c.backend.(Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+
+	value := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+	if err := c.backend.Set(ctx, pingKey, value, time.Minute); err != nil {
+		return errors.Wrap(err, "cache backend is not writable")
+	}
+	stored, found, err := c.backend.Get(ctx, pingKey)
+	if err != nil {
+		return errors.Wrap(err, "cache backend is not readable")
+	}
+	if !found || string(stored) != string(value) {
+		return errors.New("cache backend did not return the stored entry")
+	}
+	return nil
+}
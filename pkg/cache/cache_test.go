@@ -0,0 +1,135 @@
+package cache_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/cache"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
+)
+
+// fakeRedis stands in for a shared Redis backend. Like Redis it stores
+// strings with an expiry, and it can be taken down to simulate outages.
+type fakeRedis struct {
+	mu      sync.Mutex
+	data    map[string]string
+	expires map[string]time.Time
+	down    bool
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{data: map[string]string{}, expires: map[string]time.Time{}}
+}
+
+func (r *fakeRedis) Get(_ context.Context, key string) ([]byte, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.down {
+		return nil, false, errors.New("connection refused")
+	}
+	if expires, ok := r.expires[key]; ok && !time.Now().Before(expires) {
+		delete(r.data, key)
+		delete(r.expires, key)
+	}
+	value, ok := r.data[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return []byte(value), true, nil
+}
+
+func (r *fakeRedis) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.down {
+		return errors.New("connection refused")
+	}
+	r.data[key] = string(value)
+	delete(r.expires, key)
+	if ttl > 0 {
+		r.expires[key] = time.Now().Add(ttl)
+	}
+	return nil
+}
+
+func (r *fakeRedis) setDown(down bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.down = down
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("CACHE_MAX_ENTRIES", "50")
+	t.Setenv("CACHE_MAX_BYTES", "4096")
+	t.Setenv("CACHE_MAX_ENTRY_BYTES", "0")
+	t.Setenv("CACHE_TTL", "invalid")
+
+	cfg := cache.ConfigFromEnv()
+	assert.Equal(t, 50, cfg.MaxEntries)
+	assert.Equal(t, 4096, cfg.MaxBytes)
+	assert.Equal(t, cache.DefaultConfig().MaxEntryBytes, cfg.MaxEntryBytes, "non-positive byte limits should fall back to the default")
+	assert.Equal(t, cache.DefaultConfig().TTL, cfg.TTL, "invalid durations should fall back to the default")
+}
+
+func TestCacheSharedBackend(t *testing.T) {
+	ctx := context.Background()
+	redis := newFakeRedis()
+	m := middleware.NewMonitor(middleware.MonitorOptions{Registerer: prometheus.NewRegistry()})
+	stats := cache.NewMonitorStats(m)
+
+	// Two instances sharing a backend see each other's entries
+	first := cache.New(redis, cache.Options{TTL: time.Minute, Stats: stats})
+	second := cache.New(redis, cache.Options{TTL: time.Minute, Stats: stats})
+
+	_, found := first.Get(ctx, "key")
+	assert.False(t, found)
+	first.Set(ctx, "key", []byte("value"))
+	value, found := second.Get(ctx, "key")
+	assert.True(t, found)
+	assert.Equal(t, []byte("value"), value)
+	assert.NoError(t, second.Ping(ctx))
+
+	// Backend errors are reported as misses and by Ping
+	redis.setDown(true)
+	_, found = second.Get(ctx, "key")
+	assert.False(t, found)
+	first.Set(ctx, "other", []byte("value"))
+	assert.EqualError(t, second.Ping(ctx), "cache backend is not writable: connection refused")
+
+	assert.Equal(t, 1.0, counterValue(t, m, "cache_hits_total"))
+	assert.Equal(t, 2.0, counterValue(t, m, "cache_misses_total"))
+}
+
+func TestMonitorStatsEvictions(t *testing.T) {
+	ctx := context.Background()
+	m := middleware.NewMonitor(middleware.MonitorOptions{Registerer: prometheus.NewRegistry()})
+	stats := cache.NewMonitorStats(m)
+	memory := cache.NewMemory(cache.MemoryOptions{MaxEntries: 1, Stats: stats})
+	c := cache.New(memory, cache.Options{Stats: stats})
+
+	c.Set(ctx, "a", []byte("1"))
+	c.Set(ctx, "b", []byte("2"))
+	c.Set(ctx, "c", []byte("3"))
+
+	assert.Equal(t, 2.0, counterValue(t, m, "cache_evictions_total"))
+}
+
+// counterValue returns the value of the unlabelled counter name of m.
+func counterValue(t *testing.T, m *middleware.Monitor, name string) float64 {
+	families, err := m.Gatherer().Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == name {
+			require.Len(t, family.GetMetric(), 1)
+			return family.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryOptions defines the limits of a Memory backend.
+type MemoryOptions struct {
+	// MaxEntries is the amount of entries kept before the least recently
+	// used one is evicted. There is no limit when it is zero.
+	MaxEntries int
+	// MaxBytes is the size of the entries, keys included, kept before the
+	// least recently used ones are evicted. There is no limit when it is
+	// zero.
+	MaxBytes int
+	// MaxEntryBytes is the size of the largest entry, key included, that
+	// is stored. Larger entries are not stored. There is no limit when it
+	// is zero.
+	MaxEntryBytes int
+	// Stats is notified of evictions. It may be nil.
+	Stats Stats
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// Memory is an in-memory LRU Backend with per-entry expiry.
+type Memory struct {
+	mu            sync.Mutex
+	maxEntries    int
+	maxBytes      int
+	maxEntryBytes int
+	bytes         int
+	stats         Stats
+	entries       map[string]*list.Element
+	order         *list.List
+	now           func() time.Time
+}
+
+// NewMemory creates an empty Memory backend.
+func NewMemory(opts MemoryOptions) *Memory {
+	stats := opts.Stats
+	if stats == nil {
+		stats = noopStats{}
+	}
+	return &Memory{
+		maxEntries:    opts.MaxEntries,
+		maxBytes:      opts.MaxBytes,
+		maxEntryBytes: opts.MaxEntryBytes,
+		stats:         stats,
+		entries:       make(map[string]*list.Element),
+		order:         list.New(),
+		now:           time.Now,
+	}
+}
+
+// Get returns the value stored under key. Expired entries are removed.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*memoryEntry)
+	if !entry.expires.IsZero() && !m.now().Before(entry.expires) {
+		m.remove(element)
+		return nil, false, nil
+	}
+	m.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+// Set stores value under key, evicting the least recently used entries
+// if the backend is full. Entries larger than MaxEntryBytes are not
+// stored, and replace no previous value.
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = m.now().Add(ttl)
+	}
+
+	element, ok := m.entries[key]
+	if m.maxEntryBytes > 0 && entrySize(key, value) > m.maxEntryBytes {
+		if ok {
+			m.remove(element)
+		}
+		return nil
+	}
+
+	if ok {
+		entry := element.Value.(*memoryEntry)
+		m.bytes += len(value) - len(entry.value)
+		entry.value = value
+		entry.expires = expires
+		m.order.MoveToFront(element)
+	} else {
+		m.entries[key] = m.order.PushFront(&memoryEntry{key: key, value: value, expires: expires})
+		m.bytes += entrySize(key, value)
+	}
+
+	for m.full() {
+		m.remove(m.order.Back())
+		m.stats.Evict()
+	}
+	return nil
+}
+
+// full reports whether the entries exceed MaxEntries or MaxBytes.
+func (m *Memory) full() bool {
+	return (m.maxEntries > 0 && m.order.Len() > m.maxEntries) || (m.maxBytes > 0 && m.bytes > m.maxBytes)
+}
+
+// entrySize returns the bytes an entry counts for.
+func entrySize(key string, value []byte) int {
+	return len(key) + len(value)
+}
+
+// Ping always succeeds, so health checks do not evict entries.
+func (m *Memory) Ping(context.Context) error {
+	return nil
+}
+
+// Len returns the amount of stored entries, including expired ones
+// that have not been removed yet.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// Bytes returns the size of the stored entries, keys included.
+func (m *Memory) Bytes() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.bytes
+}
+
+func (m *Memory) remove(element *list.Element) {
+	entry := element.Value.(*memoryEntry)
+	m.order.Remove(element)
+	delete(m.entries, entry.key)
+	m.bytes -= entrySize(entry.key, entry.value)
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingStats struct {
+	hits, misses, evictions int
+}
+
+func (s *countingStats) Hit()   { s.hits++ }
+func (s *countingStats) Miss()  { s.misses++ }
+func (s *countingStats) Evict() { s.evictions++ }
+
+func TestMemoryLRU(t *testing.T) {
+	ctx := context.Background()
+	stats := &countingStats{}
+	memory := NewMemory(MemoryOptions{MaxEntries: 2, Stats: stats})
+
+	assert.NoError(t, memory.Set(ctx, "a", []byte("1"), 0))
+	assert.NoError(t, memory.Set(ctx, "b", []byte("2"), 0))
+
+	// Reading "a" makes "b" the least recently used entry
+	value, found, err := memory.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("1"), value)
+
+	assert.NoError(t, memory.Set(ctx, "c", []byte("3"), 0))
+	assert.Equal(t, 2, memory.Len())
+	assert.Equal(t, 1, stats.evictions)
+
+	_, found, _ = memory.Get(ctx, "b")
+	assert.False(t, found, "the least recently used entry should be evicted")
+	_, found, _ = memory.Get(ctx, "a")
+	assert.True(t, found)
+	_, found, _ = memory.Get(ctx, "c")
+	assert.True(t, found)
+
+	// Updating an entry does not evict anything
+	assert.NoError(t, memory.Set(ctx, "a", []byte("4"), 0))
+	value, _, _ = memory.Get(ctx, "a")
+	assert.Equal(t, []byte("4"), value)
+	assert.Equal(t, 1, stats.evictions)
+}
+
+func TestMemoryMaxBytes(t *testing.T) {
+	ctx := context.Background()
+	stats := &countingStats{}
+	memory := NewMemory(MemoryOptions{MaxBytes: 10, MaxEntryBytes: 6, Stats: stats})
+
+	// Every entry counts for its key and value
+	assert.NoError(t, memory.Set(ctx, "a", []byte("1234"), 0))
+	assert.NoError(t, memory.Set(ctx, "b", []byte("1234"), 0))
+	assert.Equal(t, 10, memory.Bytes())
+	assert.Equal(t, 0, stats.evictions)
+
+	// Going over the budget evicts the least recently used entries
+	assert.NoError(t, memory.Set(ctx, "c", []byte("12"), 0))
+	assert.Equal(t, 8, memory.Bytes())
+	assert.Equal(t, 1, stats.evictions)
+	_, found, _ := memory.Get(ctx, "a")
+	assert.False(t, found)
+
+	// Growing an entry counts too
+	assert.NoError(t, memory.Set(ctx, "c", []byte("12345"), 0))
+	assert.Equal(t, 6, memory.Bytes())
+	assert.Equal(t, 2, stats.evictions)
+	_, found, _ = memory.Get(ctx, "b")
+	assert.False(t, found)
+
+	// Entries larger than MaxEntryBytes are not stored, and drop the
+	// previous value
+	assert.NoError(t, memory.Set(ctx, "c", []byte("123456"), 0))
+	assert.NoError(t, memory.Set(ctx, "d", []byte("123456"), 0))
+	assert.Equal(t, 0, memory.Len())
+	assert.Equal(t, 0, memory.Bytes())
+}
+
+func TestMemoryTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	memory := NewMemory(MemoryOptions{})
+	memory.now = func() time.Time { return now }
+
+	assert.NoError(t, memory.Set(ctx, "expiring", []byte("1"), time.Minute))
+	assert.NoError(t, memory.Set(ctx, "forever", []byte("2"), 0))
+
+	now = now.Add(59 * time.Second)
+	_, found, _ := memory.Get(ctx, "expiring")
+	assert.True(t, found)
+
+	now = now.Add(time.Second)
+	_, found, _ = memory.Get(ctx, "expiring")
+	assert.False(t, found, "entries should expire after their TTL")
+	_, found, _ = memory.Get(ctx, "forever")
+	assert.True(t, found, "entries without TTL should never expire")
+	assert.Equal(t, 1, memory.Len(), "expired entries should be removed")
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
+)
+
+const (
+	metricHitsTotal      = "cache_hits_total"
+	metricMissesTotal    = "cache_misses_total"
+	metricEvictionsTotal = "cache_evictions_total"
+)
+
+// monitorStats counts cache events with a middleware.Monitor.
+type monitorStats struct {
+	monitor *middleware.Monitor
+}
+
+// NewMonitorStats registers the cache metrics with m and returns
+// Stats that update them.
+func NewMonitorStats(m *middleware.Monitor) Stats {
+	_ = m.AddMetric(&middleware.Metric{
+		Type:        middleware.Counter,
+		Name:        metricHitsTotal,
+		Description: "the amount of cache lookups that found an entry.",
+	})
+	_ = m.AddMetric(&middleware.Metric{
+		Type:        middleware.Counter,
+		Name:        metricMissesTotal,
+		Description: "the amount of cache lookups that found no entry.",
+	})
+	_ = m.AddMetric(&middleware.Metric{
+		Type:        middleware.Counter,
+		Name:        metricEvictionsTotal,
+		Description: "the amount of entries evicted because the cache was full.",
+	})
+	return &monitorStats{monitor: m}
+}
+
+func (s *monitorStats) Hit() {
+	_ = s.monitor.GetMetric(metricHitsTotal).Inc(nil)
+}
+
+func (s *monitorStats) Miss() {
+	_ = s.monitor.GetMetric(metricMissesTotal).Inc(nil)
+}
+
+func (s *monitorStats) Evict() {
+	_ = s.monitor.GetMetric(metricEvictionsTotal).Inc(nil)
+}
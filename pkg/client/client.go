@@ -0,0 +1,249 @@
+// Package client is a Go client of the Roman numerals HTTP API.
+//
+//	c, err := client.New("http://localhost:8001")
+//	results, err := c.Convert(ctx, []int{1, 4, 1994})
+//
+// Requests answered with 429 Too Many Requests or 503 Service Unavailable
+// are retried with an exponential backoff, waiting at least as long as
+// the Retry-After header asks for. Failed requests return an *Error
+// holding the AppError code of the response, so that
+//
+//	errors.Is(err, &client.Error{Code: client.CodeInvalidInput})
+//
+// reports whether a request failed with ERR1002.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+// Default retry policy of a Client.
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// basePath is the path of the API version the client talks to.
+const basePath = "/api/v1"
+
+// Client calls the API of a server. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	userAgent  string
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends the requests with httpClient instead of
+// http.DefaultClient, e.g. to set timeouts, proxies or TLS settings.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithRetries sets how many times a request answered with 429 or 503 is
+// retried. Zero disables retries.
+func WithRetries(maxRetries int) Option {
+	return func(c *Client) {
+		if maxRetries >= 0 {
+			c.maxRetries = maxRetries
+		}
+	}
+}
+
+// WithBackoff sets the delay before the first retry, doubled on every
+// further retry up to max.
+func WithBackoff(min, max time.Duration) Option {
+	return func(c *Client) {
+		if min > 0 && max >= min {
+			c.minBackoff, c.maxBackoff = min, max
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New returns a client of the server at baseURL, e.g.
+// "http://localhost:8001".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "client: invalid base URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("client: invalid base URL %q: expected an http or https URL", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		userAgent:  "decimal-to-roman-numerals-client",
+		maxRetries: DefaultMaxRetries,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Convert converts numbers to Roman numerals. Like the API, the results
+// are unique and sorted by number.
+func (c *Client) Convert(ctx context.Context, numbers []int) ([]types.RomanNumeral, error) {
+	if len(numbers) == 0 {
+		return nil, errors.New("client: no numbers to convert")
+	}
+	values := make([]string, len(numbers))
+	for i, number := range numbers {
+		values[i] = strconv.Itoa(number)
+	}
+	query := url.Values{"numbers": {strings.Join(values, ",")}}
+
+	var response types.RomanNumeralResponse
+	if err := c.do(ctx, http.MethodGet, "/convert?"+query.Encode(), nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
+
+// ConvertRanges converts every number of the ranges to Roman numerals.
+// Like the API, the results are unique and sorted by number.
+func (c *Client) ConvertRanges(ctx context.Context, ranges []types.NumberRange) ([]types.RomanNumeral, error) {
+	body, err := json.Marshal(types.RangesPayload{Ranges: ranges})
+	if err != nil {
+		return nil, errors.Wrap(err, "client: failed to encode the ranges")
+	}
+
+	var response types.RomanNumeralResponse
+	if err := c.do(ctx, http.MethodPost, "/convert", body, &response); err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
+
+// Parse converts Roman numerals to numbers, in the order given. The API
+// has no endpoint for it, so the numerals are parsed locally with the
+// rules of the server: numerals that are not in the standard notation
+// return an *Error with CodeInvalidRomanNumeral, wrapping the
+// *roman.SyntaxError or *roman.RangeError of the numeral.
+func (c *Client) Parse(ctx context.Context, numerals []string) ([]types.RomanNumeral, error) {
+	results := make([]types.RomanNumeral, 0, len(numerals))
+	for _, numeral := range numerals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		number, err := roman.Parse(numeral)
+		if err != nil {
+			return nil, newError(CodeInvalidRomanNumeral, invalidRomanNumeral, err, []string{numeral})
+		}
+		results = append(results, types.RomanNumeral{Decimal: uint(number), Roman: strings.ToUpper(strings.TrimSpace(numeral))})
+	}
+	return results, nil
+}
+
+// Health checks that the service is up.
+func (c *Client) Health(ctx context.Context) (types.HealthResponse, error) {
+	var response types.HealthResponse
+	err := c.do(ctx, http.MethodGet, "/health", nil, &response)
+	return response, err
+}
+
+// do sends a request to the API path, retrying it if the server is
+// overloaded, and decodes the JSON response into v.
+func (c *Client) do(ctx context.Context, method, path string, body []byte, v interface{}) error {
+	u, err := c.baseURL.Parse(c.baseURL.Path + basePath + path)
+	if err != nil {
+		return errors.Wrap(err, "client: invalid request URL")
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, u.String(), body)
+		if err != nil {
+			return err
+		}
+		if !retryable(resp.StatusCode) || attempt >= c.maxRetries {
+			return decodeResponse(resp, v)
+		}
+
+		delay := c.backoff(attempt, resp.Header.Get("Retry-After"))
+		drain(resp)
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// send sends a single request.
+func (c *Client) send(ctx context.Context, method, u string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "client: failed to create the request")
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "client: %s %s failed", method, req.URL.Path)
+	}
+	return resp, nil
+}
+
+// decodeResponse decodes a successful response into v, or returns the
+// *Error of a failed one.
+func decodeResponse(resp *http.Response, v interface{}) error {
+	defer drain(resp)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodeError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return errors.Wrap(err, "client: failed to decode the response")
+	}
+	return nil
+}
+
+// drain reads the rest of the body, so the connection can be reused,
+// and closes it.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,230 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/client"
+	numerals "github.com/mrtyormaa/decimal-to-roman-numerals/pkg/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+// newServer starts a server of the API, answering the first failures
+// requests with status and the Retry-After header instead.
+func newServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *int32) {
+	gin.SetMode(gin.TestMode)
+	router := api.InitRouter()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		router.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newClient(t *testing.T, url string, opts ...client.Option) *client.Client {
+	opts = append([]client.Option{client.WithBackoff(time.Millisecond, 5*time.Millisecond)}, opts...)
+	c, err := client.New(url, opts...)
+	require.NoError(t, err)
+	return c
+}
+
+func TestNew(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8001", "ftp://localhost", "http://%zz"} {
+		_, err := client.New(baseURL)
+		assert.Error(t, err, baseURL)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	server, _ := newServer(t, 0, 0, "")
+	c := newClient(t, server.URL)
+
+	results, err := c.Convert(context.Background(), []int{4, 1994, 4})
+	require.NoError(t, err)
+	assert.Equal(t, []types.RomanNumeral{{Decimal: 4, Roman: "IV"}, {Decimal: 1994, Roman: "MCMXCIV"}}, results)
+
+	_, err = c.Convert(context.Background(), nil)
+	assert.Error(t, err)
+}
+
+func TestConvertRanges(t *testing.T) {
+	server, _ := newServer(t, 0, 0, "")
+	c := newClient(t, server.URL+"/")
+
+	results, err := c.ConvertRanges(context.Background(), []types.NumberRange{{Min: 3, Max: 4}, {Min: 2, Max: 3}})
+	require.NoError(t, err)
+	assert.Equal(t, []types.RomanNumeral{{Decimal: 2, Roman: "II"}, {Decimal: 3, Roman: "III"}, {Decimal: 4, Roman: "IV"}}, results)
+}
+
+func TestParse(t *testing.T) {
+	c := newClient(t, "http://localhost")
+
+	results, err := c.Parse(context.Background(), []string{"xlii", "IV"})
+	require.NoError(t, err)
+	assert.Equal(t, []types.RomanNumeral{{Decimal: 42, Roman: "XLII"}, {Decimal: 4, Roman: "IV"}}, results)
+
+	_, err = c.Parse(context.Background(), []string{"IV", "IIII"})
+	var clientErr *client.Error
+	require.ErrorAs(t, err, &clientErr)
+	assert.Equal(t, client.CodeInvalidRomanNumeral, clientErr.Code)
+	assert.Equal(t, []string{"IIII"}, clientErr.InvalidNumbers)
+	assert.Zero(t, clientErr.StatusCode)
+	assert.Equal(t, roman.NewAppError(roman.CodeInvalidRomanNumeral).Error(), err.Error())
+	assert.ErrorIs(t, err, numerals.ErrSyntax)
+}
+
+func TestHealth(t *testing.T) {
+	server, _ := newServer(t, 0, 0, "")
+	c := newClient(t, server.URL)
+
+	health, err := c.Health(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "success", health.Status)
+}
+
+func TestAppErrors(t *testing.T) {
+	server, _ := newServer(t, 0, 0, "")
+	c := newClient(t, server.URL)
+
+	_, err := c.Convert(context.Background(), []int{1, 4000})
+	assert.ErrorIs(t, err, &client.Error{Code: client.CodeInvalidInput})
+	assert.NotErrorIs(t, err, &client.Error{Code: client.CodeOutOfBounds})
+	var clientErr *client.Error
+	require.ErrorAs(t, err, &clientErr)
+	assert.Equal(t, http.StatusBadRequest, clientErr.StatusCode)
+	assert.Equal(t, []string{"4000"}, clientErr.InvalidNumbers)
+	assert.Equal(t, roman.NewAppError(roman.CodeInvalidInput).Error(), err.Error())
+	assert.Nil(t, errors.Unwrap(err))
+
+	_, err = c.ConvertRanges(context.Background(), []types.NumberRange{{Min: 10, Max: 1}})
+	assert.ErrorIs(t, err, &client.Error{Code: client.CodeInvalidRangeMinMoreMax})
+
+	ranges := make([]types.NumberRange, roman.DefaultLimits().MaxRanges+1)
+	for i := range ranges {
+		ranges[i] = types.NumberRange{Min: 1, Max: 1}
+	}
+	_, err = c.ConvertRanges(context.Background(), ranges)
+	require.ErrorAs(t, err, &clientErr)
+	assert.Equal(t, client.CodeTooManyRanges, clientErr.Code)
+	assert.Equal(t, roman.DefaultLimits().MaxRanges, clientErr.Limit)
+	assert.Equal(t, len(ranges), clientErr.Requested)
+}
+
+func TestCodes(t *testing.T) {
+	codes := map[string]string{
+		client.CodeInvalidInput:           roman.CodeInvalidInput,
+		client.CodeOutOfBounds:            roman.CodeOutOfBounds,
+		client.CodeInvalidRangeMinMoreMax: roman.CodeInvalidRangeMinMoreMax,
+		client.CodeInvalidRangeBounds:     roman.CodeInvalidRangeBounds,
+		client.CodeRequestBodyTooLarge:    roman.CodeRequestBodyTooLarge,
+		client.CodeTooManyRanges:          roman.CodeTooManyRanges,
+		client.CodeRangeSpanTooLarge:      roman.CodeRangeSpanTooLarge,
+		client.CodeTooManyNumbers:         roman.CodeTooManyNumbers,
+		client.CodeRequestTimeout:         roman.CodeRequestTimeout,
+		client.CodeInvalidRomanNumeral:    roman.CodeInvalidRomanNumeral,
+	}
+	for code, expected := range codes {
+		assert.Equal(t, expected, code, "the client codes should match those of the API")
+	}
+}
+
+func TestUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+	c := newClient(t, server.URL)
+
+	_, err := c.Health(context.Background())
+	var clientErr *client.Error
+	require.ErrorAs(t, err, &clientErr)
+	assert.Equal(t, http.StatusNotFound, clientErr.StatusCode)
+	assert.Empty(t, clientErr.Code)
+	assert.EqualError(t, err, "client: unexpected status 404: Not Found")
+	assert.NotErrorIs(t, err, &client.Error{Code: client.CodeInvalidInput})
+}
+
+func TestRetries(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		server, requests := newServer(t, 2, status, "")
+		c := newClient(t, server.URL)
+
+		results, err := c.Convert(context.Background(), []int{1})
+		require.NoError(t, err, status)
+		assert.Len(t, results, 1)
+		assert.Equal(t, int32(3), atomic.LoadInt32(requests), status)
+	}
+
+	// Requests are given up after the last retry
+	server, requests := newServer(t, 10, http.StatusServiceUnavailable, "")
+	c := newClient(t, server.URL, client.WithRetries(1))
+	_, err := c.ConvertRanges(context.Background(), []types.NumberRange{{Min: 1, Max: 2}})
+	var clientErr *client.Error
+	require.ErrorAs(t, err, &clientErr)
+	assert.Equal(t, http.StatusServiceUnavailable, clientErr.StatusCode)
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+
+	// Other errors are not retried
+	server, requests = newServer(t, 10, http.StatusInternalServerError, "")
+	c = newClient(t, server.URL)
+	_, err = c.Health(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestRetryAfter(t *testing.T) {
+	server, requests := newServer(t, 1, http.StatusTooManyRequests, "1")
+	c := newClient(t, server.URL)
+
+	start := time.Now()
+	_, err := c.Health(context.Background())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "the retry should wait for Retry-After")
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+
+	// The context bounds the wait
+	server, _ = newServer(t, 1, http.StatusTooManyRequests, "60")
+	c = newClient(t, server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.Health(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	server, _ := newServer(t, 0, 0, "")
+	var sent int32
+	httpClient := &http.Client{Transport: roundTripper(func(r *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&sent, 1)
+		assert.Equal(t, "test-agent", r.Header.Get("User-Agent"))
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	c := newClient(t, server.URL, client.WithHTTPClient(httpClient), client.WithUserAgent("test-agent"))
+
+	_, err := c.Health(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&sent))
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
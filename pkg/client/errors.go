@@ -0,0 +1,128 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AppError codes of the responses, for use with errors.Is. They mirror the
+// codes of the API so that the client does not depend on the server.
+const (
+	CodeInvalidInput           = "ERR1002"
+	CodeOutOfBounds            = "ERR1003"
+	CodeInvalidRangeMinMoreMax = "ERR1008"
+	CodeInvalidRangeBounds     = "ERR1009"
+	CodeRequestBodyTooLarge    = "ERR1012"
+	CodeTooManyRanges          = "ERR1014"
+	CodeRangeSpanTooLarge      = "ERR1015"
+	CodeTooManyNumbers         = "ERR1016"
+	CodeRequestTimeout         = "ERR1017"
+	CodeInvalidRomanNumeral    = "ERR1019"
+)
+
+// invalidRomanNumeral is the message of CodeInvalidRomanNumeral.
+const invalidRomanNumeral = "invalid Roman numeral: expected a numeral between I and MMMCMXCIX in standard notation"
+
+// Error is returned for requests the API rejected.
+type Error struct {
+	// StatusCode is the HTTP status of the response, or zero for errors
+	// found by the client itself.
+	StatusCode int
+	// Code is the AppError code, e.g. "ERR1002", or empty if the response
+	// has none, e.g. for a proxy error page.
+	Code    string
+	Message string
+	// InvalidNumbers lists the inputs that were rejected.
+	InvalidNumbers []string
+	// Problems lists the problems found in the JSON payload.
+	Problems []Problem
+	// Limit and Requested describe an exceeded limit.
+	Limit     int
+	Requested int
+
+	// err is the error found by the client itself, if any.
+	err error
+}
+
+// Problem is a problem found in a JSON payload.
+type Problem struct {
+	// Path is the JSONPath of the problem, e.g. "$.ranges[0].min".
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("client: unexpected status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
+}
+
+// Unwrap returns the error found by the client itself, e.g. the
+// *roman.SyntaxError of a numeral it failed to parse.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether target is an *Error with the same code, so that
+//
+//	errors.Is(err, &client.Error{Code: client.CodeInvalidInput})
+//
+// reports whether a request failed with ERR1002.
+func (e *Error) Is(target error) bool {
+	other, ok := target.(*Error)
+	return ok && e.Code != "" && other.Code == e.Code
+}
+
+// errorResponse is the body of failed responses.
+type errorResponse struct {
+	Error          string    `json:"error"`
+	InvalidNumbers []string  `json:"invalid_numbers"`
+	Problems       []Problem `json:"problems"`
+	Limit          int       `json:"limit"`
+	Requested      int       `json:"requested"`
+}
+
+// decodeError returns the *Error of a failed response.
+func decodeError(resp *http.Response) error {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return errors.Wrap(err, "client: failed to read the response")
+	}
+
+	var body errorResponse
+	if err := json.Unmarshal(data, &body); err != nil || body.Error == "" {
+		return &Error{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+	}
+	code, message := splitError(body.Error)
+	return &Error{
+		StatusCode:     resp.StatusCode,
+		Code:           code,
+		Message:        message,
+		InvalidNumbers: body.InvalidNumbers,
+		Problems:       body.Problems,
+		Limit:          body.Limit,
+		Requested:      body.Requested,
+	}
+}
+
+// splitError splits an error message such as "[ERR1002] invalid input"
+// into its code and message.
+func splitError(s string) (code, message string) {
+	if strings.HasPrefix(s, "[") {
+		if end := strings.Index(s, "] "); end > 0 {
+			return s[1:end], s[end+2:]
+		}
+	}
+	return "", s
+}
+
+// newError returns the *Error of an error found by the client itself.
+func newError(code, message string, err error, invalidNumbers []string) error {
+	return &Error{Code: code, Message: message, InvalidNumbers: invalidNumbers, err: err}
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retryable reports whether a response with the status is worth retrying.
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+// backoff returns the delay before retrying after the given attempt. It
+// doubles the minimum backoff on every attempt up to the maximum, adding
+// jitter so clients do not retry in lockstep, but waits at least as long
+// as the Retry-After header asks for.
+func (c *Client) backoff(attempt int, retryAfter string) time.Duration {
+	delay := c.maxBackoff
+	// Compare against the maximum shifted right, as shifting the minimum
+	// left can overflow into a negative delay
+	if c.minBackoff < c.maxBackoff>>attempt {
+		delay = c.minBackoff << attempt
+	}
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+
+	if wait, ok := parseRetryAfter(retryAfter, time.Now()); ok && wait > delay {
+		return wait
+	}
+	return delay
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleep waits for the delay, or until ctx is done.
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"2", 2 * time.Second, true},
+		{" 0 ", 0, true},
+		{"-1", 0, false},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, test := range tests {
+		wait, ok := parseRetryAfter(test.value, now)
+		assert.Equal(t, test.ok, ok, test.value)
+		assert.Equal(t, test.expected, wait, test.value)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := &Client{minBackoff: 100 * time.Millisecond, maxBackoff: time.Second}
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		delay := c.backoff(attempt, "")
+		assert.GreaterOrEqual(t, delay, max/2, "attempt %d", attempt)
+		assert.LessOrEqual(t, delay, max, "attempt %d", attempt)
+	}
+	assert.LessOrEqual(t, c.backoff(100, ""), time.Second, "large attempts should use the maximum backoff")
+	assert.Equal(t, 3*time.Second, c.backoff(0, "3"), "Retry-After should extend the backoff")
+}
+
+func TestBackoffOverflow(t *testing.T) {
+	c := &Client{minBackoff: 5 * time.Second, maxBackoff: time.Minute}
+	for attempt := 0; attempt < 70; attempt++ {
+		delay := c.backoff(attempt, "")
+		assert.Greater(t, delay, time.Duration(0), "attempt %d", attempt)
+		assert.LessOrEqual(t, delay, time.Minute, "attempt %d", attempt)
+	}
+}
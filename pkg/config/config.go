@@ -0,0 +1,137 @@
+package config
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/env"
+)
+
+// DefaultPort is the port the server listens on when nothing has been set.
+const DefaultPort = 8001
+
+// Config holds the settings shared by the server and the roman command,
+// read from the environment and overridable with flags.
+type Config struct {
+	Port int
+	// Mode is the gin mode, one of gin.DebugMode, gin.ReleaseMode or gin.TestMode.
+	Mode string
+	// MaxBodyBytes limits the size of request bodies. Zero uses the default.
+	MaxBodyBytes int64
+	// RequestTimeout is the deadline of requests. Zero uses the default.
+	RequestTimeout time.Duration
+	// TrustedProxies lists the proxy IPs or CIDRs whose forwarding headers
+	// are trusted.
+	TrustedProxies []string
+	// Limits bounds the ranges a single request or command may ask for.
+	Limits roman.Limits
+}
+
+// FromEnv reads the configuration from PORT, GIN_MODE, MAX_BODY_BYTES,
+// REQUEST_TIMEOUT, TRUSTED_PROXIES and the range limit variables read by
+// roman.LimitsFromEnv. Unset or invalid values fall back to the defaults.
+func FromEnv() Config {
+	return Config{
+		Port:           getPort(),
+		Mode:           getMode(),
+		MaxBodyBytes:   getMaxBodyBytes(),
+		RequestTimeout: getRequestTimeout(),
+		TrustedProxies: getTrustedProxies(),
+		Limits:         roman.LimitsFromEnv(),
+	}
+}
+
+// Validate rejects settings that cannot be applied, such as an unknown mode.
+func (cfg Config) Validate() error {
+	switch cfg.Mode {
+	case gin.DebugMode, gin.ReleaseMode, gin.TestMode:
+	default:
+		return errors.Errorf("invalid mode '%s': expected %s, %s or %s", cfg.Mode, gin.DebugMode, gin.ReleaseMode, gin.TestMode)
+	}
+	if cfg.Port < 0 || cfg.Port > 65535 {
+		return errors.Errorf("invalid port %d", cfg.Port)
+	}
+	return nil
+}
+
+// RegisterFlags defines flags for every setting of cfg on fs, using the
+// current values as defaults, so that flags override the environment.
+func (cfg *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.IntVar(&cfg.Port, "port", cfg.Port, "port to listen on (PORT)")
+	fs.StringVar(&cfg.Mode, "mode", cfg.Mode, "gin mode: debug, release or test (GIN_MODE)")
+	fs.Int64Var(&cfg.MaxBodyBytes, "max-body-bytes", cfg.MaxBodyBytes, "maximum size of a request body in bytes, 0 for the default (MAX_BODY_BYTES)")
+	fs.DurationVar(&cfg.RequestTimeout, "request-timeout", cfg.RequestTimeout, "deadline of a request, 0 for the default (REQUEST_TIMEOUT)")
+	fs.Func("trusted-proxies", "comma separated proxy IPs or CIDRs to trust (TRUSTED_PROXIES)", func(value string) error {
+		cfg.TrustedProxies = env.SplitList(value)
+		return nil
+	})
+	cfg.RegisterLimitFlags(fs)
+	fs.IntVar(&cfg.Limits.MaxOperations, "max-batch-operations", cfg.Limits.MaxOperations, "maximum amount of operations in a batch (MAX_BATCH_OPERATIONS)")
+}
+
+// RegisterLimitFlags defines flags for the range limits of cfg on fs,
+// using the current values as defaults.
+func (cfg *Config) RegisterLimitFlags(fs *flag.FlagSet) {
+	fs.IntVar(&cfg.Limits.MaxRanges, "max-ranges", cfg.Limits.MaxRanges, "maximum amount of ranges (MAX_RANGES)")
+	fs.IntVar(&cfg.Limits.MaxRangeSpan, "max-range-span", cfg.Limits.MaxRangeSpan, "maximum amount of numbers in a single range (MAX_RANGE_SPAN)")
+	fs.IntVar(&cfg.Limits.MaxExpanded, "max-expanded-numbers", cfg.Limits.MaxExpanded, "maximum amount of numbers in all ranges (MAX_EXPANDED_NUMBERS)")
+}
+
+// Get the port as set via environment variable
+// If it has not been set, default to 8001
+func getPort() int {
+	port, err := strconv.Atoi(os.Getenv("PORT"))
+	if err != nil {
+		return DefaultPort
+	}
+	return port
+}
+
+// Get the request body limit in bytes as set via the MAX_BODY_BYTES
+// environment variable. If it has not been set or is invalid, return 0
+// so that the default limit is used
+func getMaxBodyBytes() int64 {
+	limit, err := strconv.ParseInt(os.Getenv("MAX_BODY_BYTES"), 10, 64)
+	if err != nil || limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// Get the deadline of requests as set via the REQUEST_TIMEOUT environment
+// variable, e.g. "5s". If it has not been set or is invalid, return 0 so
+// that the default deadline is used
+func getRequestTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv("REQUEST_TIMEOUT"))
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// Get the gin mode as set via the GIN_MODE environment variable
+// If it has not been set or is invalid, default to release mode
+func getMode() string {
+	switch mode := os.Getenv(gin.EnvGinMode); mode {
+	case gin.DebugMode, gin.ReleaseMode, gin.TestMode:
+		return mode
+	case "":
+		return gin.ReleaseMode
+	default:
+		log.Printf("invalid %s %q, using %s mode", gin.EnvGinMode, mode, gin.ReleaseMode)
+		return gin.ReleaseMode
+	}
+}
+
+// Get the trusted proxies as set via the comma separated TRUSTED_PROXIES
+// environment variable. If it has not been set, no proxy is trusted
+func getTrustedProxies() []string {
+	return env.SplitList(os.Getenv("TRUSTED_PROXIES"))
+}
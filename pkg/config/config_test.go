@@ -0,0 +1,143 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/roman"
+)
+
+func TestGetPort(t *testing.T) {
+	// Save the original PORT value and defer restoration
+	originalPort := os.Getenv("PORT")
+	defer os.Setenv("PORT", originalPort)
+
+	tests := []struct {
+		envPort      string
+		expectedPort int
+	}{
+		{"8080", 8080},    // Valid port number
+		{"invalid", 8001}, // Invalid port number
+		{"", 8001},        // No port set, should use default
+	}
+
+	for _, test := range tests {
+		// Set the PORT environment variable
+		os.Setenv("PORT", test.envPort)
+
+		// Get the port using the function
+		port := getPort()
+
+		// Check if the returned port matches the expected port
+		if port != test.expectedPort {
+			t.Errorf("Expected port %d, but got %d", test.expectedPort, port)
+		}
+	}
+}
+
+func TestGetMode(t *testing.T) {
+	tests := []struct {
+		envMode      string
+		expectedMode string
+	}{
+		{"debug", gin.DebugMode},     // Valid mode
+		{"test", gin.TestMode},       // Valid mode
+		{"invalid", gin.ReleaseMode}, // Invalid mode
+		{"", gin.ReleaseMode},        // No mode set, should use release
+	}
+
+	for _, test := range tests {
+		t.Setenv(gin.EnvGinMode, test.envMode)
+		assert.Equal(t, test.expectedMode, getMode())
+	}
+}
+
+func TestGetTrustedProxies(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, ,192.168.1.1")
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, getTrustedProxies())
+
+	t.Setenv("TRUSTED_PROXIES", "")
+	assert.Empty(t, getTrustedProxies())
+}
+
+func TestGetMaxBodyBytes(t *testing.T) {
+	tests := []struct {
+		envLimit      string
+		expectedLimit int64
+	}{
+		{"2048", 2048}, // Valid limit
+		{"-1", 0},      // Negative limit, should use default
+		{"invalid", 0}, // Invalid limit, should use default
+		{"", 0},        // No limit set, should use default
+	}
+
+	for _, test := range tests {
+		t.Setenv("MAX_BODY_BYTES", test.envLimit)
+		assert.Equal(t, test.expectedLimit, getMaxBodyBytes())
+	}
+}
+
+func TestGetRequestTimeout(t *testing.T) {
+	tests := []struct {
+		envTimeout      string
+		expectedTimeout time.Duration
+	}{
+		{"5s", 5 * time.Second}, // Valid timeout
+		{"-1s", 0},              // Negative timeout, should use default
+		{"invalid", 0},          // Invalid timeout, should use default
+		{"", 0},                 // No timeout set, should use default
+	}
+
+	for _, test := range tests {
+		t.Setenv("REQUEST_TIMEOUT", test.envTimeout)
+		assert.Equal(t, test.expectedTimeout, getRequestTimeout())
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("PORT", "9000")
+	t.Setenv(gin.EnvGinMode, gin.DebugMode)
+	t.Setenv("MAX_RANGES", "5")
+
+	cfg := FromEnv()
+	assert.Equal(t, 9000, cfg.Port)
+	assert.Equal(t, gin.DebugMode, cfg.Mode)
+	assert.Equal(t, 5, cfg.Limits.MaxRanges)
+	assert.Equal(t, roman.DefaultLimits().MaxRangeSpan, cfg.Limits.MaxRangeSpan)
+}
+
+func TestRegisterFlags(t *testing.T) {
+	t.Setenv("PORT", "9000")
+	t.Setenv("MAX_RANGES", "5")
+	t.Setenv("REQUEST_TIMEOUT", "3s")
+
+	cfg := FromEnv()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.RegisterFlags(fs)
+	require.NoError(t, fs.Parse([]string{"-port", "9001", "-trusted-proxies", "10.0.0.0/8, 192.168.1.1", "-max-range-span", "10", "-max-batch-operations", "20"}))
+
+	assert.Equal(t, 9001, cfg.Port, "flags should override the environment")
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, cfg.TrustedProxies)
+	assert.Equal(t, 10, cfg.Limits.MaxRangeSpan)
+	assert.Equal(t, 5, cfg.Limits.MaxRanges, "unset flags should keep the environment")
+	assert.Equal(t, 20, cfg.Limits.MaxOperations)
+	assert.Equal(t, 3*time.Second, cfg.RequestTimeout)
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Config{Port: DefaultPort, Mode: gin.ReleaseMode}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Mode = "production"
+	assert.EqualError(t, cfg.Validate(), "invalid mode 'production': expected debug, release or test")
+
+	cfg.Mode = gin.DebugMode
+	cfg.Port = 70000
+	assert.EqualError(t, cfg.Validate(), "invalid port 70000")
+}
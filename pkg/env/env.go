@@ -0,0 +1,18 @@
+// Package env parses the values of the environment variables read by the
+// configurations of the other packages, so that they all accept the same
+// formats.
+package env
+
+import "strings"
+
+// SplitList splits a comma separated list, trimming spaces and dropping
+// empty items, e.g. "a, ,b" is [a b]. It returns nil for empty lists.
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
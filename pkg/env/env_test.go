@@ -0,0 +1,26 @@
+package env_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/env"
+)
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"", nil},
+		{" , ,", nil},
+		{"a", []string{"a"}},
+		{"10.0.0.0/8, ,192.168.1.1", []string{"10.0.0.0/8", "192.168.1.1"}},
+		{" gzip ,br,", []string{"gzip", "br"}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, env.SplitList(test.value), test.value)
+	}
+}
@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"math"
+	"os"
+	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Config defines the thresholds of the built-in checks.
+type Config struct {
+	// MaxQueueDepth is the amount of in-flight requests above which
+	// the service reports not ready.
+	MaxQueueDepth int
+	// MemoryLimit is the heap size in bytes the memory check compares
+	// against. If it is zero, the Go runtime memory limit (GOMEMLIMIT) is used.
+	MemoryLimit uint64
+	// MemoryThreshold is the fraction of MemoryLimit above which the
+	// memory check fails.
+	MemoryThreshold float64
+}
+
+// DefaultConfig returns the thresholds used when nothing has been set.
+func DefaultConfig() Config {
+	return Config{
+		MaxQueueDepth:   1000,
+		MemoryLimit:     0,
+		MemoryThreshold: 0.9,
+	}
+}
+
+// ConfigFromEnv reads the thresholds from HEALTH_MAX_QUEUE_DEPTH,
+// HEALTH_MEMORY_LIMIT and HEALTH_MEMORY_THRESHOLD. Unset or invalid
+// values fall back to DefaultConfig.
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	if value, err := strconv.Atoi(os.Getenv("HEALTH_MAX_QUEUE_DEPTH")); err == nil && value > 0 {
+		cfg.MaxQueueDepth = value
+	}
+	if value, err := strconv.ParseUint(os.Getenv("HEALTH_MEMORY_LIMIT"), 10, 64); err == nil {
+		cfg.MemoryLimit = value
+	}
+	if value, err := strconv.ParseFloat(os.Getenv("HEALTH_MEMORY_THRESHOLD"), 64); err == nil && value > 0 && value <= 1 {
+		cfg.MemoryThreshold = value
+	}
+	return cfg
+}
+
+// QueueDepthCheck fails when depth reports more than max pending jobs.
+func QueueDepthCheck(depth func() int, max int) CheckFunc {
+	return func(context.Context) error {
+		if current := depth(); current > max {
+			return errors.Errorf("queue depth %d exceeds %d", current, max)
+		}
+		return nil
+	}
+}
+
+// MemoryCheck fails when the heap grows above threshold * limit.
+// If limit is zero the Go runtime memory limit is used, and the
+// check always passes when no limit has been set.
+func MemoryCheck(limit uint64, threshold float64) CheckFunc {
+	return func(context.Context) error {
+		max := limit
+		if max == 0 {
+			runtimeLimit := debug.SetMemoryLimit(-1)
+			if runtimeLimit == math.MaxInt64 {
+				return nil
+			}
+			max = uint64(runtimeLimit)
+		}
+
+		used := heapBytes()
+		if float64(used) > threshold*float64(max) {
+			return errors.Errorf("heap usage %d bytes exceeds %.0f%% of %d bytes", used, threshold*100, max)
+		}
+		return nil
+	}
+}
+
+// heapBytes returns the memory occupied by heap objects.
+func heapBytes() uint64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
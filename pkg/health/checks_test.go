@@ -0,0 +1,51 @@
+package health_test
+
+import (
+	"context"
+	"math"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/health"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("HEALTH_MAX_QUEUE_DEPTH", "50")
+	t.Setenv("HEALTH_MEMORY_LIMIT", "1048576")
+	t.Setenv("HEALTH_MEMORY_THRESHOLD", "1.5")
+
+	cfg := health.ConfigFromEnv()
+	assert.Equal(t, 50, cfg.MaxQueueDepth)
+	assert.Equal(t, uint64(1048576), cfg.MemoryLimit)
+	assert.Equal(t, health.DefaultConfig().MemoryThreshold, cfg.MemoryThreshold, "thresholds above 1 should fall back to the default")
+}
+
+func TestQueueDepthCheck(t *testing.T) {
+	depth := 0
+	check := health.QueueDepthCheck(func() int { return depth }, 2)
+
+	assert.NoError(t, check(context.Background()))
+	depth = 2
+	assert.NoError(t, check(context.Background()))
+	depth = 3
+	assert.EqualError(t, check(context.Background()), "queue depth 3 exceeds 2")
+}
+
+func TestMemoryCheck(t *testing.T) {
+	assert.NoError(t, health.MemoryCheck(math.MaxUint64, 0.9)(context.Background()))
+	assert.Error(t, health.MemoryCheck(1, 0.9)(context.Background()))
+}
+
+func TestMemoryCheckRuntimeLimit(t *testing.T) {
+	previous := debug.SetMemoryLimit(-1)
+	defer debug.SetMemoryLimit(previous)
+
+	// Without any limit the check always passes
+	debug.SetMemoryLimit(math.MaxInt64)
+	assert.NoError(t, health.MemoryCheck(0, 0.9)(context.Background()))
+
+	debug.SetMemoryLimit(1)
+	assert.Error(t, health.MemoryCheck(0, 0.9)(context.Background()))
+}
@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// Probe identifies the kind of health check endpoint.
+type Probe string
+
+const (
+	Liveness  Probe = "livez"
+	Readiness Probe = "readyz"
+	Startup   Probe = "startupz"
+
+	StatusOK     = "ok"
+	StatusFailed = "failed"
+
+	// checkTimeout bounds the time a single check may take.
+	checkTimeout = 2 * time.Second
+)
+
+// CheckFunc reports a problem by returning an error.
+type CheckFunc func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+// CheckResult is the outcome of a single named check.
+type CheckResult struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Result is the outcome of all the checks of a probe.
+type Result struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks,omitempty"`
+}
+
+// Registry holds the named checks of every probe. Readiness and startup
+// are additionally gated by flags that are flipped by the server lifecycle.
+type Registry struct {
+	mu      sync.RWMutex
+	checks  map[Probe][]namedCheck
+	ready   atomic.Bool
+	started atomic.Bool
+}
+
+// NewRegistry creates a Registry that is neither started nor ready.
+func NewRegistry() *Registry {
+	r := &Registry{checks: make(map[Probe][]namedCheck)}
+	r.Register(Readiness, "shutdown", func(context.Context) error {
+		if !r.ready.Load() {
+			return errors.New("server is not accepting traffic")
+		}
+		return nil
+	})
+	r.Register(Startup, "started", func(context.Context) error {
+		if !r.started.Load() {
+			return errors.New("server has not started")
+		}
+		return nil
+	})
+	return r
+}
+
+// Register adds a named check to probe. Checks run in registration order.
+func (r *Registry) Register(probe Probe, name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[probe] = append(r.checks[probe], namedCheck{name: name, check: check})
+}
+
+// SetReady toggles readiness at runtime, e.g. to fail readiness before
+// connections are drained on shutdown. Becoming ready also completes startup.
+func (r *Registry) SetReady(ready bool) {
+	r.ready.Store(ready)
+	if ready {
+		r.started.Store(true)
+	}
+}
+
+// Ready reports the readiness flag set by SetReady.
+func (r *Registry) Ready() bool {
+	return r.ready.Load()
+}
+
+// Run executes the checks of probe.
+func (r *Registry) Run(ctx context.Context, probe Probe) Result {
+	r.mu.RLock()
+	checks := append([]namedCheck(nil), r.checks[probe]...)
+	r.mu.RUnlock()
+
+	result := Result{Status: StatusOK}
+	for _, c := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		start := time.Now()
+		err := c.check(checkCtx)
+		cancel()
+
+		checkResult := CheckResult{Name: c.name, Status: StatusOK, Duration: time.Since(start).String()}
+		if err != nil {
+			checkResult.Status = StatusFailed
+			checkResult.Error = err.Error()
+			result.Status = StatusFailed
+		}
+		result.Checks = append(result.Checks, checkResult)
+	}
+	return result
+}
+
+// Handler serves probe. It responds with 200 when every check passes and
+// with 503 otherwise. The per-check results are included with ?verbose.
+func (r *Registry) Handler(probe Probe) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result := r.Run(c.Request.Context(), probe)
+
+		status := http.StatusOK
+		if result.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		if _, verbose := c.GetQuery("verbose"); !verbose {
+			result.Checks = nil
+		}
+		c.JSON(status, result)
+	}
+}
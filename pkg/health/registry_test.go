@@ -0,0 +1,87 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/health"
+)
+
+func TestRegistryLifecycle(t *testing.T) {
+	registry := health.NewRegistry()
+	ctx := context.Background()
+
+	assert.Equal(t, health.StatusOK, registry.Run(ctx, health.Liveness).Status, "liveness has no checks by default")
+	assert.Equal(t, health.StatusFailed, registry.Run(ctx, health.Readiness).Status)
+	assert.Equal(t, health.StatusFailed, registry.Run(ctx, health.Startup).Status)
+
+	registry.SetReady(true)
+	assert.True(t, registry.Ready())
+	assert.Equal(t, health.StatusOK, registry.Run(ctx, health.Readiness).Status)
+	assert.Equal(t, health.StatusOK, registry.Run(ctx, health.Startup).Status)
+
+	// Failing readiness on shutdown does not undo startup
+	registry.SetReady(false)
+	assert.Equal(t, health.StatusFailed, registry.Run(ctx, health.Readiness).Status)
+	assert.Equal(t, health.StatusOK, registry.Run(ctx, health.Startup).Status)
+}
+
+func TestRegistryRunReportsEveryCheck(t *testing.T) {
+	registry := health.NewRegistry()
+	registry.Register(health.Liveness, "passing", func(context.Context) error { return nil })
+	registry.Register(health.Liveness, "failing", func(context.Context) error { return errors.New("broken") })
+
+	result := registry.Run(context.Background(), health.Liveness)
+	require.Len(t, result.Checks, 2)
+	assert.Equal(t, health.StatusFailed, result.Status)
+	assert.Equal(t, "passing", result.Checks[0].Name)
+	assert.Equal(t, health.StatusOK, result.Checks[0].Status)
+	assert.Equal(t, "failing", result.Checks[1].Name)
+	assert.Equal(t, health.StatusFailed, result.Checks[1].Status)
+	assert.Equal(t, "broken", result.Checks[1].Error)
+}
+
+func TestRegistryCheckDeadline(t *testing.T) {
+	registry := health.NewRegistry()
+	registry.Register(health.Liveness, "deadline", func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok, "checks should run with a deadline")
+		return nil
+	})
+	registry.Run(context.Background(), health.Liveness)
+}
+
+func TestRegistryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := health.NewRegistry()
+	router := gin.New()
+	router.GET("/readyz", registry.Handler(health.Readiness))
+
+	serve := func(url string) (int, health.Result) {
+		req, _ := http.NewRequest("GET", url, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		var result health.Result
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		return resp.Code, result
+	}
+
+	code, result := serve("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusFailed, result.Status)
+	assert.Empty(t, result.Checks, "checks are only reported with ?verbose")
+
+	registry.SetReady(true)
+	code, result = serve("/readyz?verbose")
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, result.Checks, 1)
+	assert.Equal(t, "shutdown", result.Checks[0].Name)
+}
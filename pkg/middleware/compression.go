@@ -0,0 +1,310 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/env"
+)
+
+const (
+	EncodingZstd   = "zstd"
+	EncodingBrotli = "br"
+	EncodingGzip   = "gzip"
+
+	// uncompressedSizeKey stores the body size of a compressed response
+	// before compression, so the Monitor can account for both sizes.
+	uncompressedSizeKey = "middleware.uncompressed_body_size"
+)
+
+// CompressionConfig defines which responses are compressed.
+type CompressionConfig struct {
+	// Encodings lists the supported encodings in order of preference.
+	// Compression is disabled when it is empty.
+	Encodings []string
+	// MinSize is the body size in bytes below which responses are sent
+	// uncompressed. Flushed responses are always compressed.
+	MinSize int
+	// ExcludedPaths are never compressed.
+	ExcludedPaths []string
+}
+
+// DefaultCompressionConfig returns the configuration used when nothing
+// has been set.
+func DefaultCompressionConfig() CompressionConfig {
+	return CompressionConfig{
+		Encodings:     []string{EncodingZstd, EncodingBrotli, EncodingGzip},
+		MinSize:       1024,
+		ExcludedPaths: []string{"/metrics"},
+	}
+}
+
+// CompressionConfigFromEnv reads the configuration from
+// COMPRESSION_ENCODINGS and COMPRESSION_MIN_SIZE. Encodings are comma
+// separated; unknown encodings are ignored and an empty value disables
+// compression. Unset or invalid values fall back to DefaultCompressionConfig.
+func CompressionConfigFromEnv() CompressionConfig {
+	cfg := DefaultCompressionConfig()
+	if value, ok := os.LookupEnv("COMPRESSION_ENCODINGS"); ok {
+		cfg.Encodings = nil
+		for _, encoding := range env.SplitList(strings.ToLower(value)) {
+			if _, known := encoders[encoding]; known {
+				cfg.Encodings = append(cfg.Encodings, encoding)
+			}
+		}
+	}
+	if value, err := strconv.Atoi(os.Getenv("COMPRESSION_MIN_SIZE")); err == nil && value >= 0 {
+		cfg.MinSize = value
+	}
+	return cfg
+}
+
+// encoder is implemented by the gzip, brotli and zstd writers.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// encoders holds a pool of writers per supported encoding.
+var encoders = map[string]*sync.Pool{
+	EncodingZstd: {New: func() interface{} {
+		// A small window keeps the memory per response low
+		w, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithWindowSize(1<<20))
+		return w
+	}},
+	EncodingBrotli: {New: func() interface{} {
+		return brotli.NewWriterLevel(nil, brotli.DefaultCompression)
+	}},
+	EncodingGzip: {New: func() interface{} {
+		return gzip.NewWriter(nil)
+	}},
+}
+
+// negotiateEncoding selects the encoding of supported, in order of
+// preference, with the highest quality in the Accept-Encoding header.
+// It returns an empty string if none is acceptable.
+func negotiateEncoding(acceptEncoding string, supported []string) string {
+	qualities := map[string]float64{}
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		quality := 1.0
+		if key, value, found := strings.Cut(strings.TrimSpace(params), "="); found && strings.TrimSpace(key) == "q" {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = q
+			}
+		}
+		if name == "*" {
+			wildcard = quality
+		} else {
+			qualities[name] = quality
+		}
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range supported {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = wildcard
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// isCompressible reports whether responses of contentType benefit
+// from compression.
+func isCompressible(contentType string) bool {
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	switch {
+	case mediaType == "":
+		return false
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/x-ndjson", "application/javascript", "application/xml":
+		return true
+	}
+	return false
+}
+
+// compressWriter buffers the start of the body until it can decide
+// whether to compress it, then streams it through the encoder.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+
+	buffer  []byte
+	decided bool
+	encoder encoder
+	size    int
+}
+
+// Write buffers the body until MinSize bytes have been written.
+func (w *compressWriter) Write(data []byte) (int, error) {
+	w.size += len(data)
+	if !w.decided {
+		w.buffer = append(w.buffer, data...)
+		if len(w.buffer) < w.minSize {
+			return len(data), nil
+		}
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString writes s like Write.
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// decide starts compressing if the response allows it and writes the
+// buffered body.
+func (w *compressWriter) decide() error {
+	w.decided = true
+	header := w.Header()
+	status := w.Status()
+	if header.Get("Content-Encoding") == "" && status != http.StatusNoContent &&
+		status != http.StatusNotModified && isCompressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		// The compressed body is a different representation
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		w.encoder = encoders[w.encoding].Get().(encoder)
+		w.encoder.Reset(w.ResponseWriter)
+	}
+
+	buffer := w.buffer
+	w.buffer = nil
+	if len(buffer) == 0 {
+		return nil
+	}
+	if w.encoder != nil {
+		_, err := w.encoder.Write(buffer)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buffer)
+	return err
+}
+
+// Flush sends the body written so far. A flushed response is treated as
+// a stream and compressed regardless of its size.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide()
+	}
+	if w.encoder != nil {
+		_ = w.encoder.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Size returns the amount of uncompressed bytes written by the handler.
+func (w *compressWriter) Size() int {
+	if w.size == 0 && !w.Written() {
+		return -1
+	}
+	return w.size
+}
+
+// Written reports whether the handler has written any part of the response.
+func (w *compressWriter) Written() bool {
+	return w.size > 0 || w.ResponseWriter.Written()
+}
+
+// Hijack stops compressing and hands over the connection.
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.decided = true
+	return w.ResponseWriter.Hijack()
+}
+
+// close writes the remaining body and releases the encoder.
+func (w *compressWriter) close() {
+	if !w.decided {
+		// Small bodies are sent as is
+		w.decided = true
+		if len(w.buffer) > 0 {
+			_, _ = w.ResponseWriter.Write(w.buffer)
+			w.buffer = nil
+		}
+		return
+	}
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+		w.encoder.Reset(nil)
+		encoders[w.encoding].Put(w.encoder)
+		w.encoder = nil
+	}
+}
+
+// Compression compresses responses with DefaultCompressionConfig.
+func Compression() gin.HandlerFunc {
+	return CompressionWithConfig(DefaultCompressionConfig())
+}
+
+// CompressionWithConfig compresses responses with the encoding negotiated
+// from the Accept-Encoding header.
+func CompressionWithConfig(cfg CompressionConfig) gin.HandlerFunc {
+	excluded := make(map[string]struct{}, len(cfg.ExcludedPaths))
+	for _, path := range cfg.ExcludedPaths {
+		excluded[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := excluded[c.Request.URL.Path]; ok || len(cfg.Encodings) == 0 || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), cfg.Encodings)
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &compressWriter{ResponseWriter: original, encoding: encoding, minSize: cfg.MinSize}
+		c.Writer = writer
+		defer func() {
+			writer.close()
+			c.Writer = original
+			if writer.size > 0 {
+				c.Set(uncompressedSizeKey, writer.size)
+			}
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decompress returns a reader of body encoded with encoding.
+func decompress(t *testing.T, encoding string, body io.Reader) io.Reader {
+	switch encoding {
+	case EncodingGzip:
+		reader, err := gzip.NewReader(body)
+		require.NoError(t, err)
+		return reader
+	case EncodingBrotli:
+		return brotli.NewReader(body)
+	case EncodingZstd:
+		reader, err := zstd.NewReader(body)
+		require.NoError(t, err)
+		return reader
+	}
+	return body
+}
+
+func compressionRouter(cfg CompressionConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CompressionWithConfig(cfg))
+	r.GET("/large", func(c *gin.Context) {
+		c.Header("ETag", `"large"`)
+		c.String(http.StatusOK, strings.Repeat("MMMCMXCIX ", 500))
+	})
+	r.GET("/small", func(c *gin.Context) {
+		c.String(http.StatusOK, "XLII")
+	})
+	r.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", make([]byte, 4096))
+	})
+	r.GET("/metrics", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("metric 1\n", 500))
+	})
+	r.GET("/not-modified", func(c *gin.Context) {
+		c.Header("ETag", `"large"`)
+		c.Status(http.StatusNotModified)
+	})
+	return r
+}
+
+func serveCompressed(r http.Handler, method, url, acceptEncoding string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	supported := DefaultCompressionConfig().Encodings
+	tests := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", EncodingGzip},
+		{"gzip, deflate, br", EncodingBrotli},
+		{"gzip, deflate, br, zstd", EncodingZstd},
+		{"GZIP", EncodingGzip},
+		{"zstd;q=0.5, gzip;q=0.8", EncodingGzip},
+		{"br;q=0, gzip;q=0.1", EncodingGzip},
+		{"*", EncodingZstd},
+		{"*;q=0.5, zstd;q=0", EncodingBrotli},
+		{"gzip;q=0", ""},
+		{"deflate", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, negotiateEncoding(test.acceptEncoding, supported), "Accept-Encoding: %q", test.acceptEncoding)
+	}
+}
+
+func TestCompressionEncodings(t *testing.T) {
+	r := compressionRouter(DefaultCompressionConfig())
+
+	for _, encoding := range []string{EncodingZstd, EncodingBrotli, EncodingGzip} {
+		t.Run(encoding, func(t *testing.T) {
+			resp := serveCompressed(r, "GET", "/large", encoding)
+
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Equal(t, encoding, resp.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", resp.Header().Get("Vary"))
+			assert.Empty(t, resp.Header().Get("Content-Length"))
+			assert.Equal(t, `W/"large"`, resp.Header().Get("ETag"), "compressed responses should have a weak ETag")
+			assert.Less(t, resp.Body.Len(), 5000)
+
+			body, err := io.ReadAll(decompress(t, encoding, resp.Body))
+			require.NoError(t, err)
+			assert.Equal(t, strings.Repeat("MMMCMXCIX ", 500), string(body))
+		})
+	}
+}
+
+func TestCompressionSkipped(t *testing.T) {
+	r := compressionRouter(DefaultCompressionConfig())
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		acceptEncoding string
+		expectedVary   string
+	}{
+		{"NotAccepted", "GET", "/large", "", "Accept-Encoding"},
+		{"SmallBody", "GET", "/small", "gzip", "Accept-Encoding"},
+		{"NotCompressible", "GET", "/image", "gzip", "Accept-Encoding"},
+		{"NotModified", "GET", "/not-modified", "gzip", "Accept-Encoding"},
+		{"ExcludedPath", "GET", "/metrics", "gzip", ""},
+		{"Head", "HEAD", "/large", "gzip", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := serveCompressed(r, test.method, test.url, test.acceptEncoding)
+			assert.Empty(t, resp.Header().Get("Content-Encoding"))
+			assert.Equal(t, test.expectedVary, resp.Header().Get("Vary"))
+		})
+	}
+
+	assert.Equal(t, "XLII", serveCompressed(r, "GET", "/small", "gzip").Body.String())
+	assert.Equal(t, `"large"`, serveCompressed(r, "GET", "/not-modified", "gzip").Header().Get("ETag"))
+
+	// Compression can be disabled
+	r = compressionRouter(CompressionConfig{})
+	resp := serveCompressed(r, "GET", "/large", "gzip")
+	assert.Empty(t, resp.Header().Get("Content-Encoding"))
+	assert.Empty(t, resp.Header().Get("Vary"))
+}
+
+func TestCompressionConfigFromEnv(t *testing.T) {
+	t.Setenv("COMPRESSION_ENCODINGS", "gzip, deflate, BR")
+	t.Setenv("COMPRESSION_MIN_SIZE", "-1")
+
+	cfg := CompressionConfigFromEnv()
+	assert.Equal(t, []string{EncodingGzip, EncodingBrotli}, cfg.Encodings, "unknown encodings should be ignored")
+	assert.Equal(t, DefaultCompressionConfig().MinSize, cfg.MinSize, "negative sizes should fall back to the default")
+
+	t.Setenv("COMPRESSION_ENCODINGS", "")
+	assert.Empty(t, CompressionConfigFromEnv().Encodings)
+}
+
+func TestCompressionStreaming(t *testing.T) {
+	for _, test := range []struct {
+		contentType string
+		events      []string
+	}{
+		{"application/x-ndjson", []string{`{"decimal":1,"roman":"I"}` + "\n", `{"decimal":2,"roman":"II"}` + "\n"}},
+		{"text/event-stream", []string{"data: I\n\n", "data: II\n\n"}},
+	} {
+		t.Run(test.contentType, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			next := make(chan struct{})
+			r := gin.New()
+			r.Use(Compression())
+			r.GET("/stream", func(c *gin.Context) {
+				c.Header("Content-Type", test.contentType)
+				for _, event := range test.events {
+					_, _ = c.Writer.WriteString(event)
+					c.Writer.Flush()
+					// Wait for the client to read the event before sending the next
+					<-next
+				}
+			})
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			req, _ := http.NewRequest("GET", server.URL+"/stream", nil)
+			req.Header.Set("Accept-Encoding", EncodingGzip)
+			resp, err := http.DefaultTransport.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, EncodingGzip, resp.Header.Get("Content-Encoding"), "flushed responses should be compressed regardless of size")
+
+			reader := bufio.NewReader(decompress(t, EncodingGzip, resp.Body))
+			for _, event := range test.events {
+				line, err := reader.ReadString('\n')
+				require.NoError(t, err)
+				if strings.HasSuffix(event, "\n\n") {
+					blank, err := reader.ReadString('\n')
+					require.NoError(t, err)
+					line += blank
+				}
+				assert.Equal(t, event, line, "each event should arrive once it is flushed")
+				next <- struct{}{}
+			}
+		})
+	}
+}
+
+func TestCompressionAccounting(t *testing.T) {
+	m := NewMonitor(MonitorOptions{Registerer: prometheus.NewRegistry()})
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	m.Use(r)
+	r.Use(Compression())
+	r.GET("/large", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("MMMCMXCIX ", 500))
+	})
+	r.GET("/small", func(c *gin.Context) {
+		c.String(http.StatusOK, "XLII")
+	})
+
+	compressed := serveCompressed(r, "GET", "/large", EncodingGzip).Body.Len()
+	serveCompressed(r, "GET", "/small", EncodingGzip)
+
+	families, err := m.Gatherer().Gather()
+	require.NoError(t, err)
+	sent := findMetricFamily(families, metricResponseBody).GetMetric()[0].GetCounter().GetValue()
+	uncompressed := findMetricFamily(families, metricResponseBodyRaw).GetMetric()[0].GetCounter().GetValue()
+	assert.Equal(t, float64(compressed+4), sent, "the sent bytes should be compressed")
+	assert.Equal(t, float64(5000+4), uncompressed)
+}
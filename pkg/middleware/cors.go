@@ -1,26 +1,207 @@
 package middleware
 
 import (
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/env"
 )
 
-func Cors() gin.HandlerFunc {
-	return cors.New(cors.Config{
+// CorsConfig defines the cross-origin requests the API accepts.
+type CorsConfig struct {
+	// AllowOrigins lists the allowed origins, e.g. "https://example.com".
+	// "https://*.example.com" allows every subdomain of example.com and
+	// "*" allows any origin.
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache the preflight response.
+	MaxAge time.Duration
+}
+
+// DefaultCorsConfig returns the policy used when nothing has been set,
+// allowing the local Swagger UI only.
+func DefaultCorsConfig() CorsConfig {
+	return CorsConfig{
 		AllowOrigins: []string{
 			"http://127.0.0.1",
 			"http://127.0.0.1:8001",
 			"http://localhost",
 			"http://localhost:8001"},
-		AllowMethods: []string{"*"},
-		AllowHeaders: []string{"*"},
-		//ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodOptions},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Accept-Version", "Authorization", "X-Request-ID"},
+		ExposeHeaders:    []string{"ETag", "X-Request-ID", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "API-Version", "Deprecation", "Sunset", "Link"},
 		AllowCredentials: true,
-		//AllowOriginFunc: func(origin string) bool {
-		//	return origin == "https://github.com"
-		//},
-		MaxAge: 12 * time.Hour,
-	})
+		MaxAge:           12 * time.Hour,
+	}
+}
+
+// CorsConfigFromEnv reads the policy from CORS_ALLOW_ORIGINS,
+// CORS_ALLOW_METHODS, CORS_ALLOW_HEADERS, CORS_EXPOSE_HEADERS,
+// CORS_ALLOW_CREDENTIALS and CORS_MAX_AGE. Lists are comma separated and
+// the max age uses the time.ParseDuration format. Unset or unparsable
+// values fall back to DefaultCorsConfig; use Validate to reject insecure
+// policies.
+func CorsConfigFromEnv() CorsConfig {
+	cfg := DefaultCorsConfig()
+	if value := env.SplitList(os.Getenv("CORS_ALLOW_ORIGINS")); len(value) > 0 {
+		cfg.AllowOrigins = value
+	}
+	if value := env.SplitList(os.Getenv("CORS_ALLOW_METHODS")); len(value) > 0 {
+		cfg.AllowMethods = value
+	}
+	if value := env.SplitList(os.Getenv("CORS_ALLOW_HEADERS")); len(value) > 0 {
+		cfg.AllowHeaders = value
+	}
+	if value, ok := os.LookupEnv("CORS_EXPOSE_HEADERS"); ok {
+		cfg.ExposeHeaders = env.SplitList(value)
+	}
+	if value, err := strconv.ParseBool(os.Getenv("CORS_ALLOW_CREDENTIALS")); err == nil {
+		cfg.AllowCredentials = value
+	}
+	if value, err := time.ParseDuration(os.Getenv("CORS_MAX_AGE")); err == nil && value >= 0 {
+		cfg.MaxAge = value
+	}
+	return cfg
+}
+
+// Validate rejects malformed origins and insecure combinations, such as
+// wildcards together with credentials.
+func (cfg CorsConfig) Validate() error {
+	if len(cfg.AllowOrigins) == 0 {
+		return errors.New("CORS policy must allow at least one origin")
+	}
+	for _, origin := range cfg.AllowOrigins {
+		if origin == "*" {
+			if len(cfg.AllowOrigins) > 1 {
+				return errors.New("CORS origin '*' cannot be combined with other origins")
+			}
+			if cfg.AllowCredentials {
+				return errors.New("CORS origin '*' cannot be combined with credentials")
+			}
+			continue
+		}
+		if _, err := parseOrigin(origin); err != nil {
+			return err
+		}
+	}
+	if len(cfg.AllowMethods) == 0 {
+		return errors.New("CORS policy must allow at least one method")
+	}
+	if cfg.AllowCredentials {
+		for _, list := range []struct {
+			name   string
+			values []string
+		}{
+			{"methods", cfg.AllowMethods},
+			{"headers", cfg.AllowHeaders},
+			{"expose headers", cfg.ExposeHeaders},
+		} {
+			for _, value := range list.values {
+				if value == "*" {
+					return errors.Errorf("CORS %s '*' cannot be combined with credentials", list.name)
+				}
+			}
+		}
+	}
+	if cfg.MaxAge < 0 {
+		return errors.Errorf("CORS max age %s must not be negative", cfg.MaxAge)
+	}
+	return nil
+}
+
+// originPattern is a parsed allowed origin. If wildcard is set, host is
+// the domain whose subdomains are allowed.
+type originPattern struct {
+	scheme   string
+	host     string
+	port     string
+	wildcard bool
+}
+
+// parseOrigin parses an allowed origin such as "https://example.com:8443"
+// or "https://*.example.com".
+func parseOrigin(origin string) (originPattern, error) {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return originPattern{}, errors.Errorf("invalid CORS origin '%s': expected scheme://host[:port]", origin)
+	}
+
+	pattern := originPattern{scheme: u.Scheme, host: strings.ToLower(u.Hostname()), port: u.Port()}
+	if strings.HasPrefix(pattern.host, "*.") {
+		pattern.wildcard = true
+		pattern.host = strings.TrimPrefix(pattern.host, "*.")
+		// Require a registrable domain, e.g. reject "https://*.com"
+		if !strings.Contains(pattern.host, ".") {
+			return originPattern{}, errors.Errorf("invalid CORS origin '%s': wildcard must be followed by a domain", origin)
+		}
+	}
+	if strings.Contains(pattern.host, "*") {
+		return originPattern{}, errors.Errorf("invalid CORS origin '%s': only a leading '*.' wildcard is supported", origin)
+	}
+	return pattern, nil
+}
+
+// matches reports whether origin is allowed by the pattern.
+func (p originPattern) matches(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme != p.scheme || u.Port() != p.port {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if p.wildcard {
+		return strings.HasSuffix(host, "."+p.host)
+	}
+	return host == p.host
+}
+
+// Cors applies the policy of DefaultCorsConfig.
+func Cors() gin.HandlerFunc {
+	return CorsWithConfig(DefaultCorsConfig())
+}
+
+// CorsWithConfig applies the policy of cfg. It panics if cfg is invalid,
+// so callers should Validate configuration at startup.
+func CorsWithConfig(cfg CorsConfig) gin.HandlerFunc {
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+
+	config := cors.Config{
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    cfg.ExposeHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}
+	if cfg.AllowOrigins[0] == "*" {
+		config.AllowAllOrigins = true
+		return cors.New(config)
+	}
+
+	patterns := make([]originPattern, 0, len(cfg.AllowOrigins))
+	for _, origin := range cfg.AllowOrigins {
+		pattern, _ := parseOrigin(origin)
+		patterns = append(patterns, pattern)
+	}
+	config.AllowOriginFunc = func(origin string) bool {
+		for _, pattern := range patterns {
+			if pattern.matches(origin) {
+				return true
+			}
+		}
+		return false
+	}
+	return cors.New(config)
 }
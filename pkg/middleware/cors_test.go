@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
@@ -52,3 +53,164 @@ func TestCorsMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestCorsConfigFromEnv(t *testing.T) {
+	t.Setenv("CORS_ALLOW_ORIGINS", "https://example.com, https://*.example.org")
+	t.Setenv("CORS_ALLOW_METHODS", "GET")
+	t.Setenv("CORS_EXPOSE_HEADERS", "")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "false")
+	t.Setenv("CORS_MAX_AGE", "invalid")
+
+	cfg := middleware.CorsConfigFromEnv()
+	defaults := middleware.DefaultCorsConfig()
+	assert.Equal(t, []string{"https://example.com", "https://*.example.org"}, cfg.AllowOrigins)
+	assert.Equal(t, []string{"GET"}, cfg.AllowMethods)
+	assert.Equal(t, defaults.AllowHeaders, cfg.AllowHeaders)
+	assert.Empty(t, cfg.ExposeHeaders)
+	assert.False(t, cfg.AllowCredentials)
+	assert.Equal(t, defaults.MaxAge, cfg.MaxAge, "invalid durations should fall back to the default")
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestCorsConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *middleware.CorsConfig)
+		err    string
+	}{
+		{"default", func(cfg *middleware.CorsConfig) {}, ""},
+		{"any origin without credentials", func(cfg *middleware.CorsConfig) {
+			cfg.AllowOrigins = []string{"*"}
+			cfg.AllowCredentials = false
+		}, ""},
+		{"wildcard subdomain", func(cfg *middleware.CorsConfig) {
+			cfg.AllowOrigins = []string{"https://*.example.com"}
+		}, ""},
+		{"no origins", func(cfg *middleware.CorsConfig) {
+			cfg.AllowOrigins = nil
+		}, "CORS policy must allow at least one origin"},
+		{"any origin with credentials", func(cfg *middleware.CorsConfig) {
+			cfg.AllowOrigins = []string{"*"}
+		}, "CORS origin '*' cannot be combined with credentials"},
+		{"any origin with other origins", func(cfg *middleware.CorsConfig) {
+			cfg.AllowOrigins = []string{"*", "https://example.com"}
+			cfg.AllowCredentials = false
+		}, "CORS origin '*' cannot be combined with other origins"},
+		{"any header with credentials", func(cfg *middleware.CorsConfig) {
+			cfg.AllowHeaders = []string{"*"}
+		}, "CORS headers '*' cannot be combined with credentials"},
+		{"any method with credentials", func(cfg *middleware.CorsConfig) {
+			cfg.AllowMethods = []string{"*"}
+		}, "CORS methods '*' cannot be combined with credentials"},
+		{"any exposed header with credentials", func(cfg *middleware.CorsConfig) {
+			cfg.ExposeHeaders = []string{"*"}
+		}, "CORS expose headers '*' cannot be combined with credentials"},
+		{"no methods", func(cfg *middleware.CorsConfig) {
+			cfg.AllowMethods = nil
+		}, "CORS policy must allow at least one method"},
+		{"missing scheme", func(cfg *middleware.CorsConfig) {
+			cfg.AllowOrigins = []string{"example.com"}
+		}, "invalid CORS origin 'example.com': expected scheme://host[:port]"},
+		{"origin with path", func(cfg *middleware.CorsConfig) {
+			cfg.AllowOrigins = []string{"https://example.com/app"}
+		}, "invalid CORS origin 'https://example.com/app': expected scheme://host[:port]"},
+		{"wildcard top level domain", func(cfg *middleware.CorsConfig) {
+			cfg.AllowOrigins = []string{"https://*.com"}
+		}, "invalid CORS origin 'https://*.com': wildcard must be followed by a domain"},
+		{"wildcard inside host", func(cfg *middleware.CorsConfig) {
+			cfg.AllowOrigins = []string{"https://api-*.example.com"}
+		}, "invalid CORS origin 'https://api-*.example.com': only a leading '*.' wildcard is supported"},
+		{"negative max age", func(cfg *middleware.CorsConfig) {
+			cfg.MaxAge = -time.Second
+		}, "CORS max age -1s must not be negative"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := middleware.DefaultCorsConfig()
+			tc.modify(&cfg)
+			err := cfg.Validate()
+			if tc.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.err)
+			assert.Panics(t, func() { middleware.CorsWithConfig(cfg) })
+		})
+	}
+}
+
+func TestCorsPreflight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	anyOrigin := middleware.DefaultCorsConfig()
+	anyOrigin.AllowOrigins = []string{"*"}
+	anyOrigin.AllowCredentials = false
+	subdomains := middleware.DefaultCorsConfig()
+	subdomains.AllowOrigins = []string{"https://*.example.com"}
+
+	tests := []struct {
+		name            string
+		cfg             middleware.CorsConfig
+		origin          string
+		expectedStatus  int
+		expectedOrigin  string
+		withCredentials bool
+	}{
+		{"exact origin allowed", middleware.DefaultCorsConfig(), "http://localhost:8001", http.StatusNoContent, "http://localhost:8001", true},
+		{"exact origin other port", middleware.DefaultCorsConfig(), "http://localhost:9000", http.StatusForbidden, "", false},
+		{"any origin", anyOrigin, "https://example.net", http.StatusNoContent, "*", false},
+		{"subdomain allowed", subdomains, "https://api.example.com", http.StatusNoContent, "https://api.example.com", true},
+		{"nested subdomain allowed", subdomains, "https://v1.api.example.com", http.StatusNoContent, "https://v1.api.example.com", true},
+		{"bare domain rejected", subdomains, "https://example.com", http.StatusForbidden, "", false},
+		{"suffix lookalike rejected", subdomains, "https://evilexample.com", http.StatusForbidden, "", false},
+		{"scheme mismatch rejected", subdomains, "http://api.example.com", http.StatusForbidden, "", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.CorsWithConfig(tc.cfg))
+			router.POST("/test", func(c *gin.Context) {
+				c.String(http.StatusOK, "Test route")
+			})
+
+			req, err := http.NewRequest("OPTIONS", "/test", nil)
+			assert.NoError(t, err, "Failed to create request")
+			req.Header.Set("Origin", tc.origin)
+			req.Header.Set("Access-Control-Request-Method", "POST")
+			req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.expectedStatus, resp.Code)
+			assert.Equal(t, tc.expectedOrigin, resp.Header().Get("Access-Control-Allow-Origin"))
+			if tc.expectedStatus != http.StatusNoContent {
+				return
+			}
+			assert.Equal(t, "GET,POST,OPTIONS", resp.Header().Get("Access-Control-Allow-Methods"))
+			assert.Contains(t, resp.Header().Get("Access-Control-Allow-Headers"), "Content-Type")
+			assert.Equal(t, "43200", resp.Header().Get("Access-Control-Max-Age"))
+			if tc.withCredentials {
+				assert.Equal(t, "true", resp.Header().Get("Access-Control-Allow-Credentials"))
+			} else {
+				assert.Empty(t, resp.Header().Get("Access-Control-Allow-Credentials"))
+			}
+		})
+	}
+}
+
+func TestCorsExposeHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Cors())
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "Test route")
+	})
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Origin", "http://localhost")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, "Etag,X-Request-Id,Ratelimit-Limit,Ratelimit-Remaining,Ratelimit-Reset,Api-Version,Deprecation,Sunset,Link", resp.Header().Get("Access-Control-Expose-Headers"))
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationConfig describes the deprecation of the routes the
+// Deprecation middleware is used on.
+type DeprecationConfig struct {
+	// Deprecation is when the routes were deprecated, sent in the
+	// Deprecation header of RFC 9745. The header is omitted when it is
+	// zero.
+	Deprecation time.Time
+	// Sunset is when the routes stop being served, sent in the Sunset
+	// header of RFC 8594. The header is omitted when it is zero.
+	Sunset time.Time
+	// Link is a page documenting the deprecation, sent in a Link header
+	// with the "deprecation" relation when it is set.
+	Link string
+	// Successor returns the path replacing the path of a request, sent in
+	// a Link header with the "successor-version" relation when it is set
+	// and returns a path.
+	Successor func(path string) string
+}
+
+// Deprecation marks the responses of the routes it is used on as
+// deprecated.
+func Deprecation(cfg DeprecationConfig) gin.HandlerFunc {
+	deprecation := ""
+	if !cfg.Deprecation.IsZero() {
+		deprecation = "@" + strconv.FormatInt(cfg.Deprecation.Unix(), 10)
+	}
+	sunset := ""
+	if !cfg.Sunset.IsZero() {
+		sunset = cfg.Sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		if deprecation != "" {
+			header.Set("Deprecation", deprecation)
+		}
+		if sunset != "" {
+			header.Set("Sunset", sunset)
+		}
+		if cfg.Link != "" {
+			header.Add("Link", "<"+cfg.Link+`>; rel="deprecation"`)
+		}
+		if cfg.Successor != nil {
+			if successor := cfg.Successor(c.Request.URL.Path); successor != "" {
+				header.Add("Link", "<"+successor+`>; rel="successor-version"`)
+			}
+		}
+		c.Next()
+	}
+}
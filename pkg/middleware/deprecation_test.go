@@ -0,0 +1,72 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
+)
+
+func TestDeprecation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Deprecation(middleware.DeprecationConfig{
+		Deprecation: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+		Sunset:      time.Date(2027, time.October, 19, 0, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+		Link:        "https://example.com/deprecation",
+		Successor: func(path string) string {
+			return strings.Replace(path, "/v1/", "/v2/", 1)
+		},
+	}))
+	router.GET("/api/v1/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "Test route")
+	})
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/test", nil))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "@1792368000", resp.Header().Get("Deprecation"))
+	assert.Equal(t, "Mon, 18 Oct 2027 22:00:00 GMT", resp.Header().Get("Sunset"))
+	assert.Equal(t, []string{
+		`<https://example.com/deprecation>; rel="deprecation"`,
+		`</api/v2/test>; rel="successor-version"`,
+	}, resp.Header().Values("Link"))
+}
+
+func TestDeprecationWithoutSunset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Deprecation(middleware.DeprecationConfig{Deprecation: time.Unix(1700000000, 0)}))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, "@1700000000", resp.Header().Get("Deprecation"))
+	assert.Empty(t, resp.Header().Get("Sunset"))
+	assert.Empty(t, resp.Header().Values("Link"))
+}
+
+func TestDeprecationWithoutDate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Deprecation(middleware.DeprecationConfig{Link: "https://example.com/deprecation"}))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Empty(t, resp.Header().Values("Deprecation"), "a zero date should not be sent as @-62135596800")
+	assert.Equal(t, []string{`<https://example.com/deprecation>; rel="deprecation"`}, resp.Header().Values("Link"))
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var (
@@ -14,17 +13,28 @@ var (
 	metricURIRequestTotal = "gin_uri_request_total"
 	metricRequestBody     = "gin_request_body_total"
 	metricResponseBody    = "gin_response_body_total"
+	metricResponseBodyRaw = "gin_response_body_uncompressed_total"
 	metricRequestDuration = "gin_request_duration"
 	metricSlowRequest     = "gin_slow_request_total"
+	metricTimeoutRequest  = "gin_timeout_request_total"
 )
 
+// timedOutKey marks requests that ran out of time, see MarkTimedOut.
+const timedOutKey = "middleware.timed_out"
+
+// MarkTimedOut marks the request of c as timed out, so the Monitor
+// counts it in the "gin_timeout_request_total" metric.
+func MarkTimedOut(c *gin.Context) {
+	c.Set(timedOutKey, true)
+}
+
 // Use set gin metrics middleware
 func (m *Monitor) Use(r gin.IRoutes) {
 	m.initGinMetrics()
 
 	r.Use(m.monitorInterceptor)
-	r.GET(m.metricPath, func(ctx *gin.Context) {
-		promhttp.Handler().ServeHTTP(ctx.Writer, ctx.Request)
+	r.GET(m.getMetricPath(), func(ctx *gin.Context) {
+		m.metricHandler().ServeHTTP(ctx.Writer, ctx.Request)
 	})
 }
 
@@ -39,57 +49,70 @@ func (m *Monitor) UseWithoutExposingEndpoint(r gin.IRoutes) {
 // Expose adds metric path to a given router.
 // The router can be different with the one passed to UseWithoutExposingEndpoint.
 // This allows to expose metrics on different port.
+// Only the registry of the Monitor is served on the path.
 func (m *Monitor) Expose(r gin.IRoutes) {
-	r.GET(m.metricPath, func(ctx *gin.Context) {
-		promhttp.Handler().ServeHTTP(ctx.Writer, ctx.Request)
+	r.GET(m.getMetricPath(), func(ctx *gin.Context) {
+		m.metricHandler().ServeHTTP(ctx.Writer, ctx.Request)
 	})
 }
 
 // initGinMetrics used to init gin metrics
 func (m *Monitor) initGinMetrics() {
 
-	_ = monitor.AddMetric(&Metric{
+	_ = m.AddMetric(&Metric{
 		Type:        Counter,
 		Name:        metricRequestTotal,
 		Description: "all the server received request num.",
 		Labels:      nil,
 	})
-	_ = monitor.AddMetric(&Metric{
+	_ = m.AddMetric(&Metric{
 		Type:        Counter,
 		Name:        metricURIRequestTotal,
 		Description: "all the server received request num with every uri.",
 		Labels:      []string{"uri", "method", "code"},
 	})
-	_ = monitor.AddMetric(&Metric{
+	_ = m.AddMetric(&Metric{
 		Type:        Counter,
 		Name:        metricRequestBody,
 		Description: "the server received request body size, unit byte",
 		Labels:      nil,
 	})
-	_ = monitor.AddMetric(&Metric{
+	_ = m.AddMetric(&Metric{
 		Type:        Counter,
 		Name:        metricResponseBody,
 		Description: "the server send response body size, unit byte",
 		Labels:      nil,
 	})
-	_ = monitor.AddMetric(&Metric{
+	_ = m.AddMetric(&Metric{
+		Type:        Counter,
+		Name:        metricResponseBodyRaw,
+		Description: "the server send response body size before compression, unit byte",
+		Labels:      nil,
+	})
+	_ = m.AddMetric(&Metric{
 		Type:        Histogram,
 		Name:        metricRequestDuration,
 		Description: "the time server took to handle the request.",
 		Labels:      []string{"uri"},
-		Buckets:     m.reqDuration,
+		Buckets:     m.getDuration(),
 	})
-	_ = monitor.AddMetric(&Metric{
+	_ = m.AddMetric(&Metric{
 		Type:        Counter,
 		Name:        metricSlowRequest,
-		Description: fmt.Sprintf("the server handled slow requests counter, t=%d.", m.slowTime),
+		Description: fmt.Sprintf("the server handled slow requests counter, t=%d.", m.getSlowTime()),
+		Labels:      []string{"uri", "method", "code"},
+	})
+	_ = m.AddMetric(&Metric{
+		Type:        Counter,
+		Name:        metricTimeoutRequest,
+		Description: "the server handled requests that ran out of time counter.",
 		Labels:      []string{"uri", "method", "code"},
 	})
 }
 
 // monitorInterceptor as gin monitor middleware.
 func (m *Monitor) monitorInterceptor(ctx *gin.Context) {
-	if ctx.Request.URL.Path == m.metricPath {
+	if ctx.Request.URL.Path == m.getMetricPath() {
 		ctx.Next()
 		return
 	}
@@ -120,15 +143,25 @@ func (m *Monitor) ginMetricHandle(ctx *gin.Context, start time.Time) {
 
 	// set slow request
 	latency := time.Since(start)
-	if int32(latency.Seconds()) > m.slowTime {
+	if int32(latency.Seconds()) > m.getSlowTime() {
 		_ = m.GetMetric(metricSlowRequest).Inc([]string{ctx.FullPath(), r.Method, strconv.Itoa(w.Status())})
 	}
 
+	// set timed out request
+	if ctx.GetBool(timedOutKey) {
+		_ = m.GetMetric(metricTimeoutRequest).Inc([]string{ctx.FullPath(), r.Method, strconv.Itoa(w.Status())})
+	}
+
 	// set request duration
 	_ = m.GetMetric(metricRequestDuration).Observe([]string{ctx.FullPath()}, latency.Seconds())
 
-	// set response size
+	// set response size, as sent and before compression
 	if w.Size() > 0 {
 		_ = m.GetMetric(metricResponseBody).Add(nil, float64(w.Size()))
 	}
+	if size := ctx.GetInt(uncompressedSizeKey); size > 0 {
+		_ = m.GetMetric(metricResponseBodyRaw).Add(nil, float64(size))
+	} else if w.Size() > 0 {
+		_ = m.GetMetric(metricResponseBodyRaw).Add(nil, float64(w.Size()))
+	}
 }
@@ -1,14 +1,18 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func setupRouter(m *Monitor) *gin.Engine {
@@ -106,3 +110,101 @@ func TestGinMetricHandle(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, counter)
 }
+
+func TestTimedOutRequest(t *testing.T) {
+	monitor := NewMonitor(MonitorOptions{Registerer: prometheus.NewRegistry()})
+	r := setupRouter(monitor)
+	r.GET("/timeout", func(c *gin.Context) {
+		MarkTimedOut(c)
+		c.Status(http.StatusGatewayTimeout)
+	})
+	r.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "test")
+	})
+
+	for _, path := range []string{"/timeout", "/test"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	families, err := monitor.Gatherer().Gather()
+	require.NoError(t, err)
+	metrics := findMetricFamily(families, metricTimeoutRequest).GetMetric()
+	require.Len(t, metrics, 1, "only timed out requests should be counted")
+	assert.Equal(t, float64(1), metrics[0].GetCounter().GetValue())
+	labels := map[string]string{}
+	for _, label := range metrics[0].GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+	assert.Equal(t, map[string]string{"uri": "/timeout", "method": "GET", "code": "504"}, labels)
+}
+
+func TestExposeServesMonitorRegistry(t *testing.T) {
+	monitor := NewMonitor(MonitorOptions{Prefix: "exposed"})
+	r := setupRouter(monitor)
+	r.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "test")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	r.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/debug/metrics", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "exposed_gin_request_total 1")
+	assert.Contains(t, w.Body.String(), "go_goroutines")
+}
+
+func TestMonitorInterceptorConcurrentAddMetric(t *testing.T) {
+	monitor := NewMonitor(MonitorOptions{})
+	r := setupRouter(monitor)
+	r.GET("/test", func(c *gin.Context) {
+		_ = monitor.GetMetric("custom_metric_0").Inc(nil)
+		c.String(http.StatusOK, "test")
+	})
+
+	const workers = 8
+	const requests = 50
+	var wg sync.WaitGroup
+
+	// Serve traffic through the interceptor
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < requests; j++ {
+				w := httptest.NewRecorder()
+				req, _ := http.NewRequest("GET", "/test", nil)
+				r.ServeHTTP(w, req)
+				assert.Equal(t, http.StatusOK, w.Code)
+			}
+		}()
+	}
+
+	// Register custom metrics at the same time
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := monitor.AddMetric(&Metric{
+				Type:        Counter,
+				Name:        fmt.Sprintf("custom_metric_%d", i),
+				Description: "A metric registered while serving traffic",
+			})
+			assert.NoError(t, err)
+			monitor.SetSlowTime(int32(10 + i))
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i := 0; i < workers; i++ {
+		assert.Equal(t, Counter, monitor.GetMetric(fmt.Sprintf("custom_metric_%d", i)).Type)
+	}
+	metric := monitor.GetMetric(metricRequestTotal)
+	assert.Equal(t, float64(workers*requests), testutil.ToFloat64(metric.vec.(*prometheus.CounterVec).WithLabelValues()))
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InFlight counts the requests that are currently being handled.
+// Its Depth is used as the job queue depth of the readiness probe.
+type InFlight struct {
+	count atomic.Int64
+}
+
+// Handler counts the request for as long as the handler chain runs.
+func (f *InFlight) Handler(c *gin.Context) {
+	f.count.Add(1)
+	defer f.count.Add(-1)
+	c.Next()
+}
+
+// Depth returns the amount of requests currently being handled.
+func (f *InFlight) Depth() int {
+	return int(f.count.Load())
+}
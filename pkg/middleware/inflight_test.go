@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInFlight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	inFlight := &InFlight{}
+	router := gin.New()
+	router.Use(inFlight.Handler)
+
+	var depth int
+	router.GET("/", func(c *gin.Context) {
+		depth = inFlight.Depth()
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, 1, depth, "the request should be counted while it is handled")
+	assert.Equal(t, 0, inFlight.Depth(), "the request should no longer be counted once handled")
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -15,19 +17,91 @@ type Metric struct {
 	Buckets     []float64
 	Objectives  map[float64]float64
 
-	vec prometheus.Collector
+	// NativeHistogramBucketFactor enables native histograms for Histogram
+	// type metrics when it is greater than one. Buckets may then be empty.
+	NativeHistogramBucketFactor     float64
+	NativeHistogramMaxBucketNumber  uint32
+	NativeHistogramMinResetDuration time.Duration
+
+	vec         prometheus.Collector
+	namespace   string
+	constLabels prometheus.Labels
+}
+
+// labelSeries is implemented by every Prometheus vector used by Metric.
+type labelSeries interface {
+	DeleteLabelValues(lvs ...string) bool
+	Reset()
 }
 
 // SetGaugeValue set data for Gauge type Metric.
 func (m *Metric) SetGaugeValue(labelValues []string, value float64) error {
+	return m.Set(labelValues, value)
+}
+
+// Set sets the Gauge type metric to an arbitrary value.
+func (m *Metric) Set(labelValues []string, value float64) error {
+	gauge, err := m.gauge(labelValues)
+	if err != nil {
+		return err
+	}
+	gauge.Set(value)
+	return nil
+}
+
+// Dec decreases value for Gauge type metric, decrements
+// the gauge by 1
+func (m *Metric) Dec(labelValues []string) error {
+	gauge, err := m.gauge(labelValues)
+	if err != nil {
+		return err
+	}
+	gauge.Dec()
+	return nil
+}
+
+// SetToCurrentTime sets the Gauge type metric to the current
+// Unix time in seconds.
+func (m *Metric) SetToCurrentTime(labelValues []string) error {
+	gauge, err := m.gauge(labelValues)
+	if err != nil {
+		return err
+	}
+	gauge.SetToCurrentTime()
+	return nil
+}
+
+// gauge returns the Gauge of the metric for the given label values.
+func (m *Metric) gauge(labelValues []string) (prometheus.Gauge, error) {
 	if m.Type == None {
-		return errors.Errorf("metric '%s' not existed.", m.Name)
+		return nil, errors.Errorf("metric '%s' not existed.", m.Name)
 	}
 
 	if m.Type != Gauge {
-		return errors.Errorf("metric '%s' not Gauge type", m.Name)
+		return nil, errors.Errorf("metric '%s' not Gauge type", m.Name)
+	}
+	return m.vec.(*prometheus.GaugeVec).WithLabelValues(labelValues...), nil
+}
+
+// Delete removes the series with the given label values from
+// the metric. It works for every metric type.
+func (m *Metric) Delete(labelValues []string) error {
+	if m.Type == None {
+		return errors.Errorf("metric '%s' not existed.", m.Name)
+	}
+	if !m.vec.(labelSeries).DeleteLabelValues(labelValues...) {
+		return errors.Errorf("metric '%s' has no series for labels %v", m.Name, labelValues)
+	}
+	return nil
+}
+
+// Reset removes all the series of the metric. It works for
+// every metric type.
+func (m *Metric) Reset() error {
+	if m.Type == None {
+		return errors.Errorf("metric '%s' not existed.", m.Name)
 	}
-	m.vec.(*prometheus.GaugeVec).WithLabelValues(labelValues...).Set(value)
+	m.vec.(labelSeries).Reset()
 	return nil
 }
 
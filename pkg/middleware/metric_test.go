@@ -2,8 +2,10 @@ package middleware
 
 import (
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -176,3 +178,69 @@ func TestObserveErrors(t *testing.T) {
 	err = metric.Observe([]string{"value1"}, 0.7)
 	assert.Error(t, err, "Observing Counter type should produce an error")
 }
+
+func newTestGauge() *Metric {
+	return &Metric{
+		Type:        Gauge,
+		Name:        "test_gauge",
+		Description: "A test gauge metric",
+		Labels:      []string{"label1"},
+		vec: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "test_gauge", Help: "A test gauge metric"},
+			[]string{"label1"},
+		),
+	}
+}
+
+func TestGaugeOperations(t *testing.T) {
+	metric := newTestGauge()
+	gauge := metric.vec.(*prometheus.GaugeVec).WithLabelValues("value1")
+
+	assert.NoError(t, metric.Set([]string{"value1"}, 10))
+	assert.Equal(t, float64(10), testutil.ToFloat64(gauge))
+
+	assert.NoError(t, metric.Dec([]string{"value1"}))
+	assert.Equal(t, float64(9), testutil.ToFloat64(gauge))
+
+	before := float64(time.Now().Unix())
+	assert.NoError(t, metric.SetToCurrentTime([]string{"value1"}))
+	assert.GreaterOrEqual(t, testutil.ToFloat64(gauge), before)
+}
+
+func TestGaugeOperationsErrors(t *testing.T) {
+	metric := &Metric{
+		Type:        None,
+		Name:        "test_none",
+		Description: "A test none metric",
+		Labels:      []string{"label1"},
+	}
+
+	assert.Error(t, metric.Set([]string{"value1"}, 1), "Setting None type should produce an error")
+	assert.Error(t, metric.Dec([]string{"value1"}), "Decrementing None type should produce an error")
+	assert.Error(t, metric.SetToCurrentTime([]string{"value1"}), "Setting time on None type should produce an error")
+
+	metric.Type = Counter
+	assert.Error(t, metric.Set([]string{"value1"}, 1), "Setting Counter type should produce an error")
+	assert.Error(t, metric.Dec([]string{"value1"}), "Decrementing Counter type should produce an error")
+	assert.Error(t, metric.SetToCurrentTime([]string{"value1"}), "Setting time on Counter type should produce an error")
+}
+
+func TestDeleteAndReset(t *testing.T) {
+	metric := newTestGauge()
+	vec := metric.vec.(*prometheus.GaugeVec)
+
+	assert.NoError(t, metric.Set([]string{"value1"}, 1))
+	assert.NoError(t, metric.Set([]string{"value2"}, 2))
+	assert.Equal(t, 2, testutil.CollectAndCount(vec))
+
+	assert.NoError(t, metric.Delete([]string{"value1"}))
+	assert.Equal(t, 1, testutil.CollectAndCount(vec))
+	assert.Error(t, metric.Delete([]string{"value1"}), "Deleting a missing series should produce an error")
+
+	assert.NoError(t, metric.Reset())
+	assert.Equal(t, 0, testutil.CollectAndCount(vec))
+
+	none := &Metric{Type: None, Name: "test_none"}
+	assert.Error(t, none.Delete([]string{"value1"}), "Deleting from None type should produce an error")
+	assert.Error(t, none.Reset(), "Resetting None type should produce an error")
+}
@@ -1,23 +1,142 @@
 package middleware
 
 import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/gin-contrib/secure"
 	"github.com/gin-gonic/gin"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/env"
 )
 
-func Security() gin.HandlerFunc {
-	return secure.New(secure.Config{
-		//AllowedHosts:          []string{"example.com", "ssl.example.com"},
-		//SSLRedirect:           true,
-		//SSLHost:               "ssl.example.com",
+// SecurityConfig defines the security headers and host checks applied
+// by the Security middleware.
+type SecurityConfig struct {
+	// ContentSecurityPolicy is sent as the Content-Security-Policy header.
+	ContentSecurityPolicy string
+	// STSSeconds is the max-age of the Strict-Transport-Security header.
+	// HSTS is disabled when it is zero.
+	STSSeconds           int64
+	STSIncludeSubdomains bool
+	// AllowedHosts lists the hosts requests may be addressed to.
+	// Every host is allowed when it is empty.
+	AllowedHosts []string
+	// SSLRedirect redirects plain HTTP requests to HTTPS, using SSLHost
+	// as the host when it is set.
+	SSLRedirect bool
+	SSLHost     string
+	// SSLProxyHeaders are the proxy headers that mark a request as HTTPS,
+	// e.g. X-Forwarded-Proto: https. Only set them behind a trusted proxy.
+	SSLProxyHeaders map[string]string
+}
+
+// DefaultSecurityConfig returns the hardened configuration used when
+// nothing has been set.
+func DefaultSecurityConfig() SecurityConfig {
+	return SecurityConfig{
+		ContentSecurityPolicy: "default-src 'self'",
 		STSSeconds:            315360000,
 		STSIncludeSubdomains:  true,
+		SSLProxyHeaders:       map[string]string{"X-Forwarded-Proto": "https"},
+	}
+}
+
+// SecurityConfigFromEnv reads the configuration from SECURITY_CSP,
+// SECURITY_HSTS_SECONDS, SECURITY_HSTS_INCLUDE_SUBDOMAINS,
+// SECURITY_ALLOWED_HOSTS, SECURITY_SSL_REDIRECT, SECURITY_SSL_HOST and
+// SECURITY_SSL_PROXY_HEADERS. Lists are comma separated and proxy headers
+// use the "Header:value" format. Setting SECURITY_SSL_PROXY_HEADERS to an
+// empty value stops trusting proxy headers. Unset or invalid values fall
+// back to DefaultSecurityConfig.
+func SecurityConfigFromEnv() SecurityConfig {
+	cfg := DefaultSecurityConfig()
+	if value, ok := os.LookupEnv("SECURITY_CSP"); ok {
+		cfg.ContentSecurityPolicy = value
+	}
+	if value, err := strconv.ParseInt(os.Getenv("SECURITY_HSTS_SECONDS"), 10, 64); err == nil && value >= 0 {
+		cfg.STSSeconds = value
+	}
+	if value, err := strconv.ParseBool(os.Getenv("SECURITY_HSTS_INCLUDE_SUBDOMAINS")); err == nil {
+		cfg.STSIncludeSubdomains = value
+	}
+	cfg.AllowedHosts = env.SplitList(os.Getenv("SECURITY_ALLOWED_HOSTS"))
+	if value, err := strconv.ParseBool(os.Getenv("SECURITY_SSL_REDIRECT")); err == nil {
+		cfg.SSLRedirect = value
+	}
+	cfg.SSLHost = os.Getenv("SECURITY_SSL_HOST")
+	if value, ok := os.LookupEnv("SECURITY_SSL_PROXY_HEADERS"); ok {
+		cfg.SSLProxyHeaders = map[string]string{}
+		for _, header := range env.SplitList(value) {
+			if name, headerValue, found := strings.Cut(header, ":"); found {
+				cfg.SSLProxyHeaders[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+			}
+		}
+	}
+	return cfg
+}
+
+// Report describes the effective configuration, one setting per line.
+func (cfg SecurityConfig) Report() []string {
+	hsts := "disabled"
+	if cfg.STSSeconds > 0 {
+		hsts = fmt.Sprintf("max-age=%d, include subdomains: %t", cfg.STSSeconds, cfg.STSIncludeSubdomains)
+	}
+	hosts := "any"
+	if len(cfg.AllowedHosts) > 0 {
+		hosts = strings.Join(cfg.AllowedHosts, ", ")
+	}
+	redirect := "disabled"
+	if cfg.SSLRedirect {
+		redirect = "enabled"
+		if cfg.SSLHost != "" {
+			redirect += " to " + cfg.SSLHost
+		}
+	}
+	proxyHeaders := "none"
+	if len(cfg.SSLProxyHeaders) > 0 {
+		var headers []string
+		for name, value := range cfg.SSLProxyHeaders {
+			headers = append(headers, name+": "+value)
+		}
+		sort.Strings(headers)
+		proxyHeaders = strings.Join(headers, ", ")
+	}
+	csp := cfg.ContentSecurityPolicy
+	if csp == "" {
+		csp = "none"
+	}
+	return []string{
+		"content security policy: " + csp,
+		"strict transport security: " + hsts,
+		"allowed hosts: " + hosts,
+		"ssl redirect: " + redirect,
+		"trusted ssl proxy headers: " + proxyHeaders,
+	}
+}
+
+// Security applies the security headers of DefaultSecurityConfig.
+func Security() gin.HandlerFunc {
+	return SecurityWithConfig(DefaultSecurityConfig())
+}
+
+// SecurityWithConfig applies the security headers and host checks of cfg.
+func SecurityWithConfig(cfg SecurityConfig) gin.HandlerFunc {
+	return secure.New(secure.Config{
+		AllowedHosts:          cfg.AllowedHosts,
+		SSLRedirect:           cfg.SSLRedirect,
+		SSLHost:               cfg.SSLHost,
+		STSSeconds:            cfg.STSSeconds,
+		STSIncludeSubdomains:  cfg.STSIncludeSubdomains,
 		FrameDeny:             true,
 		ContentTypeNosniff:    true,
 		BrowserXssFilter:      true,
-		ContentSecurityPolicy: "default-src 'self'",
+		ContentSecurityPolicy: cfg.ContentSecurityPolicy,
 		IENoOpen:              true,
 		ReferrerPolicy:        "strict-origin-when-cross-origin",
-		SSLProxyHeaders:       map[string]string{"X-Forwarded-Proto": "https"},
+		SSLProxyHeaders:       cfg.SSLProxyHeaders,
 	})
 }
@@ -45,3 +45,82 @@ func TestSecurityMiddleware(t *testing.T) {
 	// Check if the response status code is OK
 	assert.Equal(t, http.StatusOK, resp.Code, "Unexpected response status code")
 }
+
+func TestSecurityConfigFromEnv(t *testing.T) {
+	t.Setenv("SECURITY_CSP", "default-src 'none'")
+	t.Setenv("SECURITY_HSTS_SECONDS", "invalid")
+	t.Setenv("SECURITY_HSTS_INCLUDE_SUBDOMAINS", "false")
+	t.Setenv("SECURITY_ALLOWED_HOSTS", "example.com, api.example.com")
+	t.Setenv("SECURITY_SSL_REDIRECT", "true")
+	t.Setenv("SECURITY_SSL_HOST", "ssl.example.com")
+	t.Setenv("SECURITY_SSL_PROXY_HEADERS", "X-Forwarded-Proto:https,X-Forwarded-Ssl: on")
+
+	cfg := middleware.SecurityConfigFromEnv()
+	assert.Equal(t, "default-src 'none'", cfg.ContentSecurityPolicy)
+	assert.Equal(t, middleware.DefaultSecurityConfig().STSSeconds, cfg.STSSeconds, "invalid values should fall back to the default")
+	assert.False(t, cfg.STSIncludeSubdomains)
+	assert.Equal(t, []string{"example.com", "api.example.com"}, cfg.AllowedHosts)
+	assert.True(t, cfg.SSLRedirect)
+	assert.Equal(t, "ssl.example.com", cfg.SSLHost)
+	assert.Equal(t, map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Ssl": "on"}, cfg.SSLProxyHeaders)
+
+	// An empty value stops trusting proxy headers
+	t.Setenv("SECURITY_SSL_PROXY_HEADERS", "")
+	assert.Empty(t, middleware.SecurityConfigFromEnv().SSLProxyHeaders)
+}
+
+func TestSecurityWithConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := middleware.DefaultSecurityConfig()
+	cfg.STSSeconds = 0
+	cfg.AllowedHosts = []string{"example.com"}
+	cfg.SSLRedirect = true
+
+	router := gin.New()
+	router.Use(middleware.SecurityWithConfig(cfg))
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "Test route")
+	})
+
+	serve := func(host string, headers map[string]string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "http://"+host+"/test", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Requests to other hosts are rejected
+	assert.Equal(t, http.StatusForbidden, serve("evil.com", nil).Code)
+
+	// Plain HTTP is redirected to HTTPS
+	resp := serve("example.com", nil)
+	assert.Equal(t, http.StatusMovedPermanently, resp.Code)
+	assert.Equal(t, "https://example.com/test", resp.Header().Get("Location"))
+
+	// Requests marked as HTTPS by a proxy are served without HSTS
+	resp = serve("example.com", map[string]string{"X-Forwarded-Proto": "https"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, resp.Header().Get("Strict-Transport-Security"))
+}
+
+func TestSecurityConfigReport(t *testing.T) {
+	assert.Equal(t, []string{
+		"content security policy: default-src 'self'",
+		"strict transport security: max-age=315360000, include subdomains: true",
+		"allowed hosts: any",
+		"ssl redirect: disabled",
+		"trusted ssl proxy headers: X-Forwarded-Proto: https",
+	}, middleware.DefaultSecurityConfig().Report())
+
+	cfg := middleware.SecurityConfig{AllowedHosts: []string{"example.com"}, SSLRedirect: true, SSLHost: "ssl.example.com"}
+	assert.Equal(t, []string{
+		"content security policy: none",
+		"strict transport security: disabled",
+		"allowed hosts: example.com",
+		"ssl redirect: enabled to ssl.example.com",
+		"trusted ssl proxy headers: none",
+	}, cfg.Report())
+}
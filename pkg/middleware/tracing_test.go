@@ -0,0 +1,94 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/middleware"
+)
+
+func setupTracingRouter(t *testing.T) (*gin.Engine, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Tracing())
+	router.GET("/test/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "Test route")
+	})
+	router.GET("/fail", func(c *gin.Context) {
+		c.String(http.StatusInternalServerError, "Failure")
+	})
+	return router, exporter
+}
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	router, exporter := setupTracingRouter(t)
+
+	req, err := http.NewRequest("GET", "/test/1", nil)
+	assert.NoError(t, err, "Failed to create request")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	span := spans[0]
+
+	// The server span continues the trace of the caller
+	assert.Equal(t, "GET /test/:id", span.Name)
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent.SpanID().String())
+	assert.True(t, span.Parent.IsRemote())
+
+	assert.Equal(t, "/test/:id", spanAttribute(span, "http.route").AsString())
+	assert.Equal(t, "/test/1", spanAttribute(span, "url.path").AsString())
+	assert.Equal(t, int64(http.StatusOK), spanAttribute(span, "http.response.status_code").AsInt64())
+	assert.Equal(t, codes.Unset, span.Status.Code)
+}
+
+func TestTracingMiddlewareNewTrace(t *testing.T) {
+	router, exporter := setupTracingRouter(t)
+
+	req, err := http.NewRequest("GET", "/fail", nil)
+	assert.NoError(t, err, "Failed to create request")
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	span := spans[0]
+
+	// Without a traceparent header a new root span is started
+	assert.False(t, span.Parent.IsValid())
+	assert.True(t, span.SpanContext.IsValid())
+	assert.Equal(t, codes.Error, span.Status.Code)
+}
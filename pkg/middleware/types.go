@@ -1,8 +1,13 @@
 package middleware
 
 import (
+	"net/http"
+	"sync"
+
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type MetricType int
@@ -21,6 +26,7 @@ const (
 var (
 	defaultDuration = []float64{0.1, 0.3, 1.2, 5, 10}
 	monitor         *Monitor
+	monitorOnce     sync.Once
 
 	promTypeHandler = map[MetricType]func(metric *Metric) error{
 		Counter:   counterHandler,
@@ -31,29 +37,109 @@ var (
 )
 
 // Monitor is an object that uses to set gin server monitor.
+// It is safe for concurrent use, so metrics can be added while
+// requests are being served.
 type Monitor struct {
+	mu          sync.RWMutex
 	slowTime    int32
 	metricPath  string
 	reqDuration []float64
 	metrics     map[string]*Metric
+
+	registerer  prometheus.Registerer
+	gatherer    prometheus.Gatherer
+	prefix      string
+	constLabels prometheus.Labels
+	handler     http.Handler
+	handlerOnce sync.Once
 }
 
-// GetMonitor used to get global Monitor object,
-// this function returns a singleton object.
-func GetMonitor() *Monitor {
-	if monitor == nil {
-		monitor = &Monitor{
-			metricPath:  defaultMetricPath,
-			slowTime:    defaultSlowTime,
-			reqDuration: defaultDuration,
-			metrics:     make(map[string]*Metric),
+// MonitorOptions configures a Monitor created by NewMonitor.
+type MonitorOptions struct {
+	// Registerer is used to register every metric of the Monitor. If it is
+	// nil, a new registry with the Go runtime and process collectors is used.
+	Registerer prometheus.Registerer
+	// Gatherer is served on the metric path. If it is nil, the Registerer is
+	// used when it is also a Gatherer, otherwise the default gatherer is used.
+	Gatherer prometheus.Gatherer
+	// Prefix is prepended to every metric name, e.g. "roman" turns
+	// "gin_request_total" into "roman_gin_request_total".
+	Prefix string
+	// ConstLabels are attached to every metric of the Monitor.
+	ConstLabels prometheus.Labels
+}
+
+// NewMonitor creates a Monitor that registers its metrics with the
+// registry given in opts instead of the global Prometheus registry.
+func NewMonitor(opts MonitorOptions) *Monitor {
+	registerer, gatherer := opts.Registerer, opts.Gatherer
+	if registerer == nil {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+		registerer = registry
+	}
+	if gatherer == nil {
+		if g, ok := registerer.(prometheus.Gatherer); ok {
+			gatherer = g
+		} else {
+			gatherer = prometheus.DefaultGatherer
 		}
 	}
+
+	return &Monitor{
+		metricPath:  defaultMetricPath,
+		slowTime:    defaultSlowTime,
+		reqDuration: defaultDuration,
+		metrics:     make(map[string]*Metric),
+		registerer:  registerer,
+		gatherer:    gatherer,
+		prefix:      opts.Prefix,
+		constLabels: opts.ConstLabels,
+	}
+}
+
+// GetMonitor used to get global Monitor object,
+// this function returns a singleton object registered
+// with the default Prometheus registry.
+func GetMonitor() *Monitor {
+	monitorOnce.Do(func() {
+		monitor = NewMonitor(MonitorOptions{
+			Registerer: prometheus.DefaultRegisterer,
+			Gatherer:   prometheus.DefaultGatherer,
+		})
+	})
 	return monitor
 }
 
+// Registerer returns the registerer the Monitor metrics are registered with.
+func (m *Monitor) Registerer() prometheus.Registerer {
+	return m.registerer
+}
+
+// Gatherer returns the gatherer served on the metric path.
+func (m *Monitor) Gatherer() prometheus.Gatherer {
+	return m.gatherer
+}
+
+// metricHandler returns the http.Handler exposing the Monitor registry.
+func (m *Monitor) metricHandler() http.Handler {
+	m.handlerOnce.Do(func() {
+		m.handler = promhttp.InstrumentMetricHandler(
+			m.registerer,
+			promhttp.HandlerFor(m.gatherer, promhttp.HandlerOpts{}),
+		)
+	})
+	return m.handler
+}
+
 // GetMetric used to get metric object by metric_name.
 func (m *Monitor) GetMetric(name string) *Metric {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	if metric, ok := m.metrics[name]; ok {
 		return metric
 	}
@@ -63,23 +149,54 @@ func (m *Monitor) GetMetric(name string) *Metric {
 // SetMetricPath set metricPath property. metricPath is used for Prometheus
 // to get gin server monitoring data.
 func (m *Monitor) SetMetricPath(path string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.metricPath = path
 }
 
+// getMetricPath returns the metricPath property.
+func (m *Monitor) getMetricPath() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.metricPath
+}
+
 // SetSlowTime set slowTime property. slowTime is used to determine whether
 // the request is slow. For "gin_slow_request_total" metric.
 func (m *Monitor) SetSlowTime(slowTime int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.slowTime = slowTime
 }
 
+// getSlowTime returns the slowTime property.
+func (m *Monitor) getSlowTime() int32 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.slowTime
+}
+
 // SetDuration set reqDuration property. reqDuration is used to ginRequestDuration
 // metric buckets.
 func (m *Monitor) SetDuration(duration []float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.reqDuration = duration
 }
 
-// AddMetric add custom monitor metric.
+// getDuration returns the reqDuration property.
+func (m *Monitor) getDuration() []float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.reqDuration
+}
+
+// AddMetric add custom monitor metric. It can be called
+// concurrently with requests being served.
 func (m *Monitor) AddMetric(metric *Metric) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if _, ok := m.metrics[metric.Name]; ok {
 		return errors.Errorf("metric '%s' is existed", metric.Name)
 	}
@@ -87,9 +204,13 @@ func (m *Monitor) AddMetric(metric *Metric) error {
 	if metric.Name == "" {
 		return errors.Errorf("metric name cannot be empty.")
 	}
+	metric.namespace = m.prefix
+	metric.constLabels = m.constLabels
 	if f, ok := promTypeHandler[metric.Type]; ok {
 		if err := f(metric); err == nil {
-			prometheus.MustRegister(metric.vec)
+			if err := m.registerer.Register(metric.vec); err != nil {
+				return errors.Wrapf(err, "metric '%s' cannot be registered", metric.Name)
+			}
 			m.metrics[metric.Name] = metric
 			return nil
 		}
@@ -99,7 +220,7 @@ func (m *Monitor) AddMetric(metric *Metric) error {
 
 func counterHandler(metric *Metric) error {
 	metric.vec = prometheus.NewCounterVec(
-		prometheus.CounterOpts{Name: metric.Name, Help: metric.Description},
+		prometheus.CounterOpts{Namespace: metric.namespace, Name: metric.Name, Help: metric.Description, ConstLabels: metric.constLabels},
 		metric.Labels,
 	)
 	return nil
@@ -107,18 +228,30 @@ func counterHandler(metric *Metric) error {
 
 func gaugeHandler(metric *Metric) error {
 	metric.vec = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{Name: metric.Name, Help: metric.Description},
+		prometheus.GaugeOpts{Namespace: metric.namespace, Name: metric.Name, Help: metric.Description, ConstLabels: metric.constLabels},
 		metric.Labels,
 	)
 	return nil
 }
 
+// histogramHandler creates a classic histogram from Buckets, a native
+// histogram from NativeHistogramBucketFactor, or a histogram with both.
 func histogramHandler(metric *Metric) error {
-	if len(metric.Buckets) == 0 {
+	native := metric.NativeHistogramBucketFactor > 1
+	if len(metric.Buckets) == 0 && !native {
 		return errors.Errorf("metric '%s' is histogram type, cannot lose bucket param.", metric.Name)
 	}
 	metric.vec = prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{Name: metric.Name, Help: metric.Description, Buckets: metric.Buckets},
+		prometheus.HistogramOpts{
+			Namespace:                       metric.namespace,
+			Name:                            metric.Name,
+			Help:                            metric.Description,
+			ConstLabels:                     metric.constLabels,
+			Buckets:                         metric.Buckets,
+			NativeHistogramBucketFactor:     metric.NativeHistogramBucketFactor,
+			NativeHistogramMaxBucketNumber:  metric.NativeHistogramMaxBucketNumber,
+			NativeHistogramMinResetDuration: metric.NativeHistogramMinResetDuration,
+		},
 		metric.Labels,
 	)
 	return nil
@@ -128,8 +261,8 @@ func summaryHandler(metric *Metric) error {
 	if len(metric.Objectives) == 0 {
 		return errors.Errorf("metric '%s' is summary type, cannot lose objectives param.", metric.Name)
 	}
-	prometheus.NewSummaryVec(
-		prometheus.SummaryOpts{Name: metric.Name, Help: metric.Description, Objectives: metric.Objectives},
+	metric.vec = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{Namespace: metric.namespace, Name: metric.Name, Help: metric.Description, ConstLabels: metric.constLabels, Objectives: metric.Objectives},
 		metric.Labels,
 	)
 	return nil
@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"sync"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -120,6 +123,7 @@ func TestSummaryHandler(t *testing.T) {
 
 	err = summaryHandler(metricWithValidObjectives)
 	assert.NoError(t, err, "Expected no error when Objectives are valid")
+	assert.IsType(t, &prometheus.SummaryVec{}, metricWithValidObjectives.vec, "Expected the SummaryVec to be assigned to the metric")
 }
 
 func TestGetMetric(t *testing.T) {
@@ -145,3 +149,252 @@ func TestGetMetric(t *testing.T) {
 	assert.NotNil(t, nonExistentMetric, "Expected to retrieve a non-nil metric for a non-existent metric")
 	assert.Equal(t, &Metric{}, nonExistentMetric, "Expected to retrieve an empty metric for a non-existent metric")
 }
+
+func TestNewMonitor(t *testing.T) {
+	monitor := NewMonitor(MonitorOptions{})
+
+	assert.Equal(t, defaultMetricPath, monitor.metricPath)
+	assert.Equal(t, defaultSlowTime, monitor.slowTime)
+	assert.Equal(t, defaultDuration, monitor.reqDuration)
+	assert.NotEqual(t, prometheus.DefaultRegisterer, monitor.Registerer(), "NewMonitor should not use the global registry")
+	assert.Equal(t, monitor.Registerer(), monitor.Gatherer(), "The registry should be used as gatherer")
+
+	// The private registry still exports the Go runtime metrics
+	families, err := monitor.Gatherer().Gather()
+	assert.NoError(t, err)
+	assert.NotNil(t, findMetricFamily(families, "go_goroutines"), "Go collector should be registered")
+}
+
+func TestNewMonitorIsolation(t *testing.T) {
+	first := NewMonitor(MonitorOptions{})
+	second := NewMonitor(MonitorOptions{})
+
+	metric := func() *Metric {
+		return &Metric{Type: Counter, Name: "isolated_counter", Description: "A counter registered twice"}
+	}
+
+	assert.NoError(t, first.AddMetric(metric()))
+	assert.NoError(t, second.AddMetric(metric()), "Monitors with their own registry should not collide")
+
+	_ = first.GetMetric("isolated_counter").Inc(nil)
+
+	families, err := second.Gatherer().Gather()
+	assert.NoError(t, err)
+	assert.Nil(t, findMetricFamily(families, "isolated_counter"), "Metrics of one Monitor should not leak into another")
+
+	families, err = first.Gatherer().Gather()
+	assert.NoError(t, err)
+	family := findMetricFamily(families, "isolated_counter")
+	assert.NotNil(t, family)
+	assert.Equal(t, float64(1), family.GetMetric()[0].GetCounter().GetValue())
+}
+
+func TestNewMonitorPrefixAndConstLabels(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	monitor := NewMonitor(MonitorOptions{
+		Registerer:  registry,
+		Prefix:      "roman",
+		ConstLabels: prometheus.Labels{"env": "test"},
+	})
+	assert.Equal(t, registry, monitor.Gatherer())
+
+	err := monitor.AddMetric(&Metric{
+		Type:        Counter,
+		Name:        "prefixed_counter",
+		Description: "A prefixed counter",
+		Labels:      []string{"label1"},
+	})
+	assert.NoError(t, err)
+
+	// Metrics are still looked up by their unprefixed name
+	assert.NoError(t, monitor.GetMetric("prefixed_counter").Inc([]string{"value1"}))
+
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+	assert.Nil(t, findMetricFamily(families, "prefixed_counter"))
+
+	family := findMetricFamily(families, "roman_prefixed_counter")
+	assert.NotNil(t, family, "Metric name should carry the prefix")
+	labels := map[string]string{}
+	for _, label := range family.GetMetric()[0].GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+	assert.Equal(t, map[string]string{"env": "test", "label1": "value1"}, labels)
+}
+
+func TestAddMetricRegisterError(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{Name: "taken_counter", Help: "Registered outside the Monitor"}))
+
+	monitor := NewMonitor(MonitorOptions{Registerer: registry})
+	err := monitor.AddMetric(&Metric{Type: Counter, Name: "taken_counter", Description: "A clashing counter"})
+	assert.Error(t, err, "Registration conflicts should be returned instead of panicking")
+	assert.Equal(t, "", monitor.GetMetric("taken_counter").Name, "A failed metric should not be stored")
+}
+
+func TestGetMonitorUsesDefaultRegistry(t *testing.T) {
+	monitor := GetMonitor()
+
+	assert.Equal(t, prometheus.DefaultRegisterer, monitor.Registerer())
+	assert.Equal(t, prometheus.DefaultGatherer, monitor.Gatherer())
+}
+
+func findMetricFamily(families []*dto.MetricFamily, name string) *dto.MetricFamily {
+	for _, family := range families {
+		if family.GetName() == name {
+			return family
+		}
+	}
+	return nil
+}
+
+func TestMetricTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		metric     *Metric
+		addErr     bool
+		incErr     bool
+		observeErr bool
+	}{
+		{
+			name:   "None",
+			metric: &Metric{Type: None, Name: "type_none", Description: "A none metric"},
+			addErr: true, incErr: true, observeErr: true,
+		},
+		{
+			name:       "Counter",
+			metric:     &Metric{Type: Counter, Name: "type_counter", Description: "A counter metric", Labels: []string{"label1"}},
+			observeErr: true,
+		},
+		{
+			name:       "Gauge",
+			metric:     &Metric{Type: Gauge, Name: "type_gauge", Description: "A gauge metric", Labels: []string{"label1"}},
+			observeErr: true,
+		},
+		{
+			name:   "Histogram",
+			metric: &Metric{Type: Histogram, Name: "type_histogram", Description: "A histogram metric", Labels: []string{"label1"}, Buckets: []float64{1, 5}},
+			incErr: true,
+		},
+		{
+			name:   "NativeHistogram",
+			metric: &Metric{Type: Histogram, Name: "type_native_histogram", Description: "A native histogram metric", Labels: []string{"label1"}, NativeHistogramBucketFactor: 1.1},
+			incErr: true,
+		},
+		{
+			name:   "Summary",
+			metric: &Metric{Type: Summary, Name: "type_summary", Description: "A summary metric", Labels: []string{"label1"}, Objectives: map[float64]float64{0.5: 0.05}},
+			incErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			monitor := NewMonitor(MonitorOptions{Registerer: registry})
+
+			err := monitor.AddMetric(tt.metric)
+			if tt.addErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			metric := monitor.GetMetric(tt.metric.Name)
+			assert.Equal(t, tt.incErr, metric.Inc([]string{"value1"}) != nil, "unexpected Inc result")
+			assert.Equal(t, tt.observeErr, metric.Observe([]string{"value1"}, 2) != nil, "unexpected Observe result")
+
+			families, err := registry.Gather()
+			assert.NoError(t, err)
+			family := findMetricFamily(families, tt.metric.Name)
+			if tt.addErr {
+				assert.Nil(t, family)
+				return
+			}
+
+			// Every registered metric reports the recorded value
+			assert.NotNil(t, family, "metric should be gathered")
+			sample := family.GetMetric()[0]
+			switch tt.metric.Type {
+			case Counter:
+				assert.Equal(t, float64(1), sample.GetCounter().GetValue())
+			case Gauge:
+				assert.Equal(t, float64(1), sample.GetGauge().GetValue())
+			case Histogram:
+				assert.Equal(t, uint64(1), sample.GetHistogram().GetSampleCount())
+				assert.Equal(t, float64(2), sample.GetHistogram().GetSampleSum())
+			case Summary:
+				assert.Equal(t, uint64(1), sample.GetSummary().GetSampleCount())
+				assert.Equal(t, float64(2), sample.GetSummary().GetSampleSum())
+			}
+		})
+	}
+}
+
+func TestNativeHistogram(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	monitor := NewMonitor(MonitorOptions{Registerer: registry})
+
+	err := monitor.AddMetric(&Metric{
+		Type:                           Histogram,
+		Name:                           "native_histogram",
+		Description:                    "A native histogram metric",
+		NativeHistogramBucketFactor:    1.1,
+		NativeHistogramMaxBucketNumber: 100,
+	})
+	assert.NoError(t, err, "A native histogram does not need classic buckets")
+	assert.NoError(t, monitor.GetMetric("native_histogram").Observe(nil, 3))
+
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+	histogram := findMetricFamily(families, "native_histogram").GetMetric()[0].GetHistogram()
+	assert.NotEmpty(t, histogram.GetPositiveSpan(), "Observation should be recorded in a native bucket")
+	assert.Empty(t, histogram.GetBucket(), "No classic buckets should be exposed")
+}
+
+func TestGetMonitorConcurrent(t *testing.T) {
+	const workers = 16
+	monitors := make([]*Monitor, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			monitors[i] = GetMonitor()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, m := range monitors {
+		assert.Same(t, monitors[0], m, "GetMonitor should return the same instance from every goroutine")
+	}
+}
+
+func TestAddMetricConcurrentDuplicates(t *testing.T) {
+	monitor := NewMonitor(MonitorOptions{})
+
+	const workers = 16
+	errs := make(chan error, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- monitor.AddMetric(&Metric{Type: Gauge, Name: "concurrent_gauge", Description: "A gauge added concurrently"})
+			_ = monitor.GetMetric("concurrent_gauge")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Exactly one registration wins, the others report the duplicate
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+}
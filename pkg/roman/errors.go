@@ -0,0 +1,50 @@
+package roman
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors matched by the errors returned by this package.
+var (
+	// ErrOutOfRange is matched by a *RangeError.
+	ErrOutOfRange = errors.New("roman: number out of range")
+	// ErrSyntax is matched by a *SyntaxError.
+	ErrSyntax = errors.New("roman: invalid numeral")
+)
+
+// RangeError is returned for numbers that cannot be written as Roman
+// numerals, and for numerals whose value is out of range.
+type RangeError struct {
+	Number int
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("roman: %d is out of range, must be between %d and %d", e.Number, MinValue, MaxValue)
+}
+
+// Is makes errors.Is(err, ErrOutOfRange) report true.
+func (e *RangeError) Is(target error) bool {
+	return target == ErrOutOfRange
+}
+
+// SyntaxError is returned for invalid Roman numerals.
+type SyntaxError struct {
+	Numeral string
+	// Offset is the index of the invalid symbol in the trimmed numeral,
+	// or -1 if the numeral as a whole is invalid.
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Offset >= 0 {
+		return fmt.Sprintf("roman: invalid numeral %q: %s at offset %d", e.Numeral, e.Msg, e.Offset)
+	}
+	return fmt.Sprintf("roman: invalid numeral %q: %s", e.Numeral, e.Msg)
+}
+
+// Is makes errors.Is(err, ErrSyntax) report true.
+func (e *SyntaxError) Is(target error) bool {
+	return target == ErrSyntax
+}
@@ -0,0 +1,36 @@
+package roman_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/roman"
+)
+
+func ExampleConvert() {
+	numeral, _ := roman.Convert(1994)
+	fmt.Println(numeral)
+
+	additive, _ := roman.Convert(1994, roman.WithNotation(roman.Additive))
+	fmt.Println(additive)
+	// Output:
+	// MCMXCIV
+	// MDCCCCLXXXXIIII
+}
+
+func ExampleParse() {
+	number, _ := roman.Parse("mcmxciv")
+	fmt.Println(number)
+
+	_, err := roman.Parse("IIII")
+	fmt.Println(errors.Is(err, roman.ErrSyntax), err)
+	// Output:
+	// 1994
+	// true roman: invalid numeral "IIII": not in standard notation, expected "IV"
+}
+
+func ExampleNumeral() {
+	year := roman.Numeral(2024)
+	fmt.Printf("%s (%d)\n", year, year)
+	// Output: MMXXIV (2024)
+}
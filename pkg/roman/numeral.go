@@ -0,0 +1,53 @@
+package roman
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Numeral is a number that formats and marshals as a Roman numeral in
+// the standard notation.
+type Numeral int
+
+// String returns the numeral, or the decimal number if it is out of range.
+func (n Numeral) String() string {
+	numeral, err := Convert(int(n))
+	if err != nil {
+		return strconv.Itoa(int(n))
+	}
+	return numeral
+}
+
+// Format implements fmt.Formatter. The %s, %v and %q verbs write the
+// numeral, and the integer verbs, e.g. %d or %x, write the number.
+func (n Numeral) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 's', 'v', 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), n.String())
+	case 'd', 'b', 'o', 'x', 'X':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), int(n))
+	default:
+		fmt.Fprintf(f, "%%!%c(roman.Numeral=%d)", verb, int(n))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns a *RangeError
+// if the number is out of range.
+func (n Numeral) MarshalText() ([]byte, error) {
+	numeral, err := Convert(int(n))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(numeral), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the numeral
+// like Parse.
+func (n *Numeral) UnmarshalText(text []byte) error {
+	number, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*n = Numeral(number)
+	return nil
+}
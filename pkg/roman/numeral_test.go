@@ -0,0 +1,59 @@
+package roman_test
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/roman"
+)
+
+var (
+	_ fmt.Formatter            = roman.Numeral(0)
+	_ fmt.Stringer             = roman.Numeral(0)
+	_ encoding.TextMarshaler   = roman.Numeral(0)
+	_ encoding.TextUnmarshaler = (*roman.Numeral)(nil)
+)
+
+func TestNumeralFormat(t *testing.T) {
+	n := roman.Numeral(1994)
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"%s", "MCMXCIV"},
+		{"%v", "MCMXCIV"},
+		{"%q", `"MCMXCIV"`},
+		{"%10s|", "   MCMXCIV|"},
+		{"%-10s|", "MCMXCIV   |"},
+		{"%d", "1994"},
+		{"%05d", "01994"},
+		{"%x", "7ca"},
+		{"%f", "%!f(roman.Numeral=1994)"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, fmt.Sprintf(test.format, n), test.format)
+	}
+	assert.Equal(t, "0", roman.Numeral(0).String(), "out of range numbers should be written as decimals")
+}
+
+func TestNumeralText(t *testing.T) {
+	data, err := json.Marshal(map[string]roman.Numeral{"year": 1994})
+	require.NoError(t, err)
+	assert.Equal(t, `{"year":"MCMXCIV"}`, string(data))
+
+	var decoded map[string]roman.Numeral
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, roman.Numeral(1994), decoded["year"])
+
+	_, err = roman.Numeral(4000).MarshalText()
+	assert.ErrorIs(t, err, roman.ErrOutOfRange)
+
+	var n roman.Numeral
+	assert.ErrorIs(t, n.UnmarshalText([]byte("IIII")), roman.ErrSyntax)
+}
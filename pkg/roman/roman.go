@@ -0,0 +1,197 @@
+// Package roman converts numbers to Roman numerals and back.
+//
+// It only depends on the standard library, so it can be used without
+// the HTTP API built on top of it:
+//
+//	numeral, err := roman.Convert(1994)      // "MCMXCIV"
+//	number, err := roman.Parse("MCMXCIV")    // 1994
+//	err := roman.Validate("IIII")            // *roman.SyntaxError
+//
+// Errors are a *RangeError for numbers out of range and a *SyntaxError
+// for invalid numerals, matching ErrOutOfRange and ErrSyntax with errors.Is.
+package roman
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The range of numbers that can be written as Roman numerals.
+const (
+	MinValue = 1
+	MaxValue = 3999
+)
+
+// Notation selects how numbers are written.
+type Notation int
+
+const (
+	// Standard is the subtractive notation, e.g. 4 is IV and 1994 is MCMXCIV.
+	Standard Notation = iota
+	// Additive writes numbers without subtractive pairs, e.g. 4 is IIII
+	// and 1994 is MDCCCCLXXXXIIII.
+	Additive
+)
+
+// String returns the name of the notation.
+func (n Notation) String() string {
+	switch n {
+	case Standard:
+		return "standard"
+	case Additive:
+		return "additive"
+	}
+	return fmt.Sprintf("Notation(%d)", int(n))
+}
+
+// ParseNotation returns the notation with the given name, ignoring case.
+func ParseNotation(name string) (Notation, error) {
+	for _, notation := range []Notation{Standard, Additive} {
+		if strings.EqualFold(name, notation.String()) {
+			return notation, nil
+		}
+	}
+	return Standard, fmt.Errorf("roman: unknown notation %q", name)
+}
+
+// symbol is a numeral symbol, or subtractive pair, and its value.
+type symbol struct {
+	value   int
+	numeral string
+}
+
+// symbols lists the symbols of every notation by decreasing value.
+var symbols = map[Notation][]symbol{
+	Standard: {
+		{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+		{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+		{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+	},
+	Additive: {
+		{1000, "M"}, {500, "D"}, {100, "C"}, {50, "L"},
+		{10, "X"}, {5, "V"}, {1, "I"},
+	},
+}
+
+// values maps the single symbols to their values.
+var values = map[rune]int{
+	'I': 1,
+	'V': 5,
+	'X': 10,
+	'L': 50,
+	'C': 100,
+	'D': 500,
+	'M': 1000,
+}
+
+// Option configures Convert, Parse and Validate.
+type Option func(*options)
+
+type options struct {
+	notation  Notation
+	lowercase bool
+	lenient   bool
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithNotation selects the notation numbers are written in and numerals
+// are expected in. The default is Standard.
+func WithNotation(notation Notation) Option {
+	return func(o *options) {
+		o.notation = notation
+	}
+}
+
+// Lowercase makes Convert write lowercase numerals, e.g. "xlii".
+func Lowercase() Option {
+	return func(o *options) {
+		o.lowercase = true
+	}
+}
+
+// Lenient makes Parse accept numerals that are not written canonically
+// in the notation, e.g. "IIII" or "IC", as long as every symbol is valid.
+func Lenient() Option {
+	return func(o *options) {
+		o.lenient = true
+	}
+}
+
+// Convert writes number as a Roman numeral. It returns a *RangeError if
+// number is not between MinValue and MaxValue.
+func Convert(number int, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	if number < MinValue || number > MaxValue {
+		return "", &RangeError{Number: number}
+	}
+	table, ok := symbols[o.notation]
+	if !ok {
+		return "", fmt.Errorf("roman: unknown notation %v", o.notation)
+	}
+
+	var b strings.Builder
+	for _, s := range table {
+		for number >= s.value {
+			b.WriteString(s.numeral)
+			number -= s.value
+		}
+	}
+	if o.lowercase {
+		return strings.ToLower(b.String()), nil
+	}
+	return b.String(), nil
+}
+
+// Parse reads a Roman numeral, ignoring case and surrounding spaces.
+// It returns a *SyntaxError if the numeral contains invalid symbols or,
+// unless Lenient is given, is not written canonically in the notation,
+// and a *RangeError if its value is not between MinValue and MaxValue.
+func Parse(numeral string, opts ...Option) (int, error) {
+	o := newOptions(opts)
+	trimmed := strings.ToUpper(strings.TrimSpace(numeral))
+	if trimmed == "" {
+		return 0, &SyntaxError{Numeral: numeral, Offset: 0, Msg: "empty numeral"}
+	}
+
+	number := 0
+	runes := []rune(trimmed)
+	for i, r := range runes {
+		value, ok := values[r]
+		if !ok {
+			return 0, &SyntaxError{Numeral: numeral, Offset: i, Msg: fmt.Sprintf("invalid symbol %q", r)}
+		}
+		// A symbol followed by a larger one is subtracted, e.g. the I of IV
+		if i+1 < len(runes) && value < values[runes[i+1]] {
+			number -= value
+		} else {
+			number += value
+		}
+	}
+	if number < MinValue || number > MaxValue {
+		return 0, &RangeError{Number: number}
+	}
+
+	if !o.lenient {
+		canonical, err := Convert(number, WithNotation(o.notation))
+		if err != nil {
+			return 0, err
+		}
+		if canonical != trimmed {
+			return 0, &SyntaxError{Numeral: numeral, Offset: -1, Msg: fmt.Sprintf("not in %s notation, expected %q", o.notation, canonical)}
+		}
+	}
+	return number, nil
+}
+
+// Validate checks a Roman numeral like Parse.
+func Validate(numeral string, opts ...Option) error {
+	_, err := Parse(numeral, opts...)
+	return err
+}
@@ -0,0 +1,114 @@
+package roman_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/roman"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		number   int
+		opts     []roman.Option
+		expected string
+	}{
+		{1, nil, "I"},
+		{4, nil, "IV"},
+		{1994, nil, "MCMXCIV"},
+		{3999, nil, "MMMCMXCIX"},
+		{4, []roman.Option{roman.WithNotation(roman.Additive)}, "IIII"},
+		{1994, []roman.Option{roman.WithNotation(roman.Additive)}, "MDCCCCLXXXXIIII"},
+		{42, []roman.Option{roman.Lowercase()}, "xlii"},
+	}
+
+	for _, test := range tests {
+		numeral, err := roman.Convert(test.number, test.opts...)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, numeral)
+	}
+}
+
+func TestConvertOutOfRange(t *testing.T) {
+	for _, number := range []int{-1, 0, 4000} {
+		_, err := roman.Convert(number)
+		assert.True(t, errors.Is(err, roman.ErrOutOfRange))
+		var rangeErr *roman.RangeError
+		require.ErrorAs(t, err, &rangeErr)
+		assert.Equal(t, number, rangeErr.Number)
+	}
+	_, err := roman.Convert(1, roman.WithNotation(roman.Notation(42)))
+	assert.EqualError(t, err, "roman: unknown notation Notation(42)")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		numeral  string
+		opts     []roman.Option
+		expected int
+	}{
+		{"XLII", nil, 42},
+		{" mcmxciv ", nil, 1994},
+		{"IIII", []roman.Option{roman.WithNotation(roman.Additive)}, 4},
+		{"IIII", []roman.Option{roman.Lenient()}, 4},
+		{"IC", []roman.Option{roman.Lenient()}, 99},
+		{"MMMCMXCIX", nil, 3999},
+	}
+
+	for _, test := range tests {
+		number, err := roman.Parse(test.numeral, test.opts...)
+		require.NoError(t, err, test.numeral)
+		assert.Equal(t, test.expected, number, test.numeral)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		numeral  string
+		opts     []roman.Option
+		sentinel error
+		message  string
+	}{
+		{"", nil, roman.ErrSyntax, `roman: invalid numeral "": empty numeral at offset 0`},
+		{"XLIIA", nil, roman.ErrSyntax, `roman: invalid numeral "XLIIA": invalid symbol 'A' at offset 4`},
+		{"IIII", nil, roman.ErrSyntax, `roman: invalid numeral "IIII": not in standard notation, expected "IV"`},
+		{"IV", []roman.Option{roman.WithNotation(roman.Additive)}, roman.ErrSyntax, `roman: invalid numeral "IV": not in additive notation, expected "IIII"`},
+		{"MMMM", nil, roman.ErrOutOfRange, "roman: 4000 is out of range, must be between 1 and 3999"},
+		{"MMMM", []roman.Option{roman.Lenient()}, roman.ErrOutOfRange, "roman: 4000 is out of range, must be between 1 and 3999"},
+	}
+
+	for _, test := range tests {
+		_, err := roman.Parse(test.numeral, test.opts...)
+		assert.True(t, errors.Is(err, test.sentinel), "%q: unexpected error %v", test.numeral, err)
+		assert.EqualError(t, err, test.message)
+		assert.Equal(t, err, roman.Validate(test.numeral, test.opts...))
+	}
+
+	var syntaxErr *roman.SyntaxError
+	require.ErrorAs(t, roman.Validate("XLIIA"), &syntaxErr)
+	assert.Equal(t, 4, syntaxErr.Offset)
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, notation := range []roman.Notation{roman.Standard, roman.Additive} {
+		for number := roman.MinValue; number <= roman.MaxValue; number++ {
+			numeral, err := roman.Convert(number, roman.WithNotation(notation))
+			require.NoError(t, err)
+			parsed, err := roman.Parse(numeral, roman.WithNotation(notation))
+			require.NoError(t, err, numeral)
+			assert.Equal(t, number, parsed)
+		}
+	}
+}
+
+func TestParseNotation(t *testing.T) {
+	notation, err := roman.ParseNotation("Additive")
+	require.NoError(t, err)
+	assert.Equal(t, roman.Additive, notation)
+
+	_, err = roman.ParseNotation("vinculum")
+	assert.EqualError(t, err, `roman: unknown notation "vinculum"`)
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Config defines the http.Server timeouts and the shutdown behaviour.
+type Config struct {
+	// Addr is the TCP address to listen on, e.g. ":8001".
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// DrainDelay is the time between failing readiness and closing the
+	// listener, so load balancers can stop routing new requests first.
+	DrainDelay time.Duration
+	// GracePeriod is how long in-flight requests may take to finish
+	// before the remaining connections are closed.
+	GracePeriod time.Duration
+}
+
+// DefaultConfig returns the configuration used when nothing has been set.
+func DefaultConfig() Config {
+	return Config{
+		Addr:              ":8001",
+		ReadTimeout:       10 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		DrainDelay:        0,
+		GracePeriod:       30 * time.Second,
+	}
+}
+
+// ConfigFromEnv reads the configuration from the environment. Durations
+// use the time.ParseDuration format, e.g. "15s". Unset or invalid values
+// fall back to DefaultConfig.
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	cfg.ReadTimeout = durationFromEnv("SERVER_READ_TIMEOUT", cfg.ReadTimeout)
+	cfg.ReadHeaderTimeout = durationFromEnv("SERVER_READ_HEADER_TIMEOUT", cfg.ReadHeaderTimeout)
+	cfg.WriteTimeout = durationFromEnv("SERVER_WRITE_TIMEOUT", cfg.WriteTimeout)
+	cfg.IdleTimeout = durationFromEnv("SERVER_IDLE_TIMEOUT", cfg.IdleTimeout)
+	cfg.DrainDelay = durationFromEnv("SHUTDOWN_DRAIN_DELAY", cfg.DrainDelay)
+	cfg.GracePeriod = durationFromEnv("SHUTDOWN_GRACE_PERIOD", cfg.GracePeriod)
+	return cfg
+}
+
+func durationFromEnv(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// Readiness is notified when the server starts and stops accepting traffic.
+type Readiness interface {
+	SetReady(ready bool)
+}
+
+// Server wraps an http.Server with graceful shutdown.
+type Server struct {
+	cfg       Config
+	srv       *http.Server
+	readiness Readiness
+}
+
+// New creates a Server serving handler with the timeouts of cfg.
+// readiness may be nil.
+func New(cfg Config, handler http.Handler, readiness Readiness) *Server {
+	return &Server{
+		cfg: cfg,
+		srv: &http.Server{
+			Addr:              cfg.Addr,
+			Handler:           handler,
+			ReadTimeout:       cfg.ReadTimeout,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+		},
+		readiness: readiness,
+	}
+}
+
+// Run listens on the configured address and serves until ctx is done.
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", s.cfg.Addr)
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve accepts connections on ln until ctx is done. It then fails
+// readiness, waits for the drain delay and shuts down gracefully,
+// giving in-flight requests the grace period to complete.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.srv.Serve(ln)
+	}()
+	s.setReady(true)
+
+	select {
+	case err := <-serveErr:
+		s.setReady(false)
+		return err
+	case <-ctx.Done():
+	}
+
+	// Stop receiving new traffic before draining the connections
+	s.setReady(false)
+	log.Printf("shutting down: draining for %s, grace period %s", s.cfg.DrainDelay, s.cfg.GracePeriod)
+	time.Sleep(s.cfg.DrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.GracePeriod)
+	defer cancel()
+	if err := s.srv.Shutdown(shutdownCtx); err != nil {
+		_ = s.srv.Close()
+		return errors.Wrap(err, "graceful shutdown did not complete")
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) setReady(ready bool) {
+	if s.readiness != nil {
+		s.readiness.SetReady(ready)
+	}
+}
+
+// SignalContext returns a context that is cancelled on SIGINT or SIGTERM.
+func SignalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// ListenAndServe serves handler with cfg until the process receives
+// SIGINT or SIGTERM, then shuts down gracefully.
+func ListenAndServe(cfg Config, handler http.Handler, readiness Readiness) error {
+	ctx, stop := SignalContext()
+	defer stop()
+	return New(cfg, handler, readiness).Run(ctx)
+}
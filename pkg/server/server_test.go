@@ -0,0 +1,256 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/health"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/server"
+)
+
+const subprocessEnv = "SERVER_TEST_SUBPROCESS"
+
+// slowRouter serves a readiness endpoint and a slow endpoint that
+// simulates a long range conversion.
+func slowRouter(readiness *health.Registry, delay time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/readyz", readiness.Handler(health.Readiness))
+	r.GET("/slow", func(c *gin.Context) {
+		time.Sleep(delay)
+		c.String(http.StatusOK, "done")
+	})
+	return r
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("SERVER_READ_TIMEOUT", "3s")
+	t.Setenv("SERVER_WRITE_TIMEOUT", "invalid")
+	t.Setenv("SERVER_IDLE_TIMEOUT", "-1s")
+	t.Setenv("SHUTDOWN_DRAIN_DELAY", "250ms")
+	t.Setenv("SHUTDOWN_GRACE_PERIOD", "1m")
+
+	cfg := server.ConfigFromEnv()
+	defaults := server.DefaultConfig()
+
+	assert.Equal(t, 3*time.Second, cfg.ReadTimeout)
+	assert.Equal(t, defaults.ReadHeaderTimeout, cfg.ReadHeaderTimeout)
+	assert.Equal(t, defaults.WriteTimeout, cfg.WriteTimeout, "invalid durations should fall back to the default")
+	assert.Equal(t, defaults.IdleTimeout, cfg.IdleTimeout, "negative durations should fall back to the default")
+	assert.Equal(t, 250*time.Millisecond, cfg.DrainDelay)
+	assert.Equal(t, time.Minute, cfg.GracePeriod)
+}
+
+func TestServeShutdownOnCancel(t *testing.T) {
+	readiness := health.NewRegistry()
+	cfg := server.DefaultConfig()
+	cfg.GracePeriod = 2 * time.Second
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	url := "http://" + ln.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.New(cfg, slowRouter(readiness, 300*time.Millisecond), readiness).Serve(ctx, ln)
+	}()
+
+	require.Eventually(t, readiness.Ready, time.Second, 10*time.Millisecond, "server should become ready")
+
+	// Start a request that is still in flight when shutdown begins
+	slow := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(url + "/slow")
+		if err != nil {
+			slow <- 0
+			return
+		}
+		resp.Body.Close()
+		slow <- resp.StatusCode
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	cancel()
+	assert.Equal(t, http.StatusOK, <-slow, "in-flight request should complete")
+	assert.NoError(t, <-done)
+	assert.False(t, readiness.Ready(), "readiness should fail after shutdown")
+}
+
+func TestServeGracePeriodExceeded(t *testing.T) {
+	cfg := server.DefaultConfig()
+	cfg.GracePeriod = 50 * time.Millisecond
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	url := "http://" + ln.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.New(cfg, slowRouter(health.NewRegistry(), time.Second), nil).Serve(ctx, ln)
+	}()
+
+	go func() {
+		resp, err := http.Get(url + "/slow")
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	cancel()
+	assert.Error(t, <-done, "shutdown should report requests exceeding the grace period")
+}
+
+func TestRunInvalidAddress(t *testing.T) {
+	cfg := server.DefaultConfig()
+	cfg.Addr = "invalid:address:1"
+
+	err := server.New(cfg, http.NotFoundHandler(), nil).Run(context.Background())
+	assert.Error(t, err)
+}
+
+func TestNewAppliesTimeouts(t *testing.T) {
+	cfg := server.DefaultConfig()
+	cfg.Addr = "127.0.0.1:0"
+	cfg.ReadHeaderTimeout = 100 * time.Millisecond
+
+	readiness := health.NewRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ln, err := net.Listen("tcp", cfg.Addr)
+	require.NoError(t, err)
+	go func() {
+		_ = server.New(cfg, slowRouter(readiness, 0), readiness).Serve(ctx, ln)
+	}()
+	require.Eventually(t, readiness.Ready, time.Second, 10*time.Millisecond)
+
+	// A client that never finishes its headers is disconnected
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("GET /readyz HTTP/1.1\r\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, err = io.ReadAll(conn)
+	assert.NoError(t, err, "the server should close the connection before the client deadline")
+}
+
+// TestServerProcess is run as a subprocess by TestSignalShutdown.
+// It serves slowRouter until it receives a signal.
+func TestServerProcess(t *testing.T) {
+	if os.Getenv(subprocessEnv) != "1" {
+		t.Skip("only runs as a subprocess")
+	}
+
+	readiness := health.NewRegistry()
+	cfg := server.DefaultConfig()
+	cfg.DrainDelay = 500 * time.Millisecond
+	cfg.GracePeriod = 5 * time.Second
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(2)
+	}
+	fmt.Println("listening", ln.Addr().String())
+
+	ctx, stop := server.SignalContext()
+	defer stop()
+	if err := server.New(cfg, slowRouter(readiness, time.Second), readiness).Serve(ctx, ln); err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+	fmt.Println("stopped")
+	os.Exit(0)
+}
+
+func TestSignalShutdown(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to processes on windows")
+	}
+
+	for _, sig := range []os.Signal{syscall.SIGTERM, os.Interrupt} {
+		t.Run(sig.String(), func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestServerProcess$")
+			cmd.Env = append(os.Environ(), subprocessEnv+"=1")
+			stdout, err := cmd.StdoutPipe()
+			require.NoError(t, err)
+			require.NoError(t, cmd.Start())
+			defer func() { _ = cmd.Process.Kill() }()
+
+			// Wait for the subprocess to report its address
+			lines := bufio.NewScanner(stdout)
+			var addr string
+			for lines.Scan() {
+				if strings.HasPrefix(lines.Text(), "listening ") {
+					addr = strings.TrimPrefix(lines.Text(), "listening ")
+					break
+				}
+			}
+			require.NotEmpty(t, addr, "subprocess did not start listening")
+			url := "http://" + addr
+
+			require.Eventually(t, func() bool {
+				resp, err := http.Get(url + "/readyz")
+				if err != nil {
+					return false
+				}
+				resp.Body.Close()
+				return resp.StatusCode == http.StatusOK
+			}, 2*time.Second, 20*time.Millisecond, "subprocess should become ready")
+
+			// Start a conversion that is in flight when the signal arrives
+			slow := make(chan int, 1)
+			go func() {
+				resp, err := http.Get(url + "/slow")
+				if err != nil {
+					slow <- 0
+					return
+				}
+				resp.Body.Close()
+				slow <- resp.StatusCode
+			}()
+			time.Sleep(100 * time.Millisecond)
+
+			require.NoError(t, cmd.Process.Signal(sig))
+
+			// Readiness fails while the server is still draining
+			require.Eventually(t, func() bool {
+				resp, err := http.Get(url + "/readyz")
+				if err != nil {
+					return false
+				}
+				resp.Body.Close()
+				return resp.StatusCode == http.StatusServiceUnavailable
+			}, 400*time.Millisecond, 20*time.Millisecond, "readiness should fail before draining")
+
+			assert.Equal(t, http.StatusOK, <-slow, "in-flight request should complete")
+
+			var rest strings.Builder
+			for lines.Scan() {
+				rest.WriteString(lines.Text() + "\n")
+			}
+			assert.NoError(t, cmd.Wait(), "subprocess should exit cleanly")
+			assert.Contains(t, rest.String(), "stopped")
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// InstrumentationName identifies the tracers created by this service.
+	InstrumentationName = "github.com/mrtyormaa/decimal-to-roman-numerals"
+
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+
+	defaultServiceName = "decimal-to-roman-numerals"
+)
+
+// Config defines how spans are exported.
+type Config struct {
+	// Exporter is one of ExporterOTLP, ExporterStdout or ExporterNone.
+	Exporter string
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string
+}
+
+// ConfigFromEnv reads the tracing configuration from the standard
+// OTEL_TRACES_EXPORTER and OTEL_SERVICE_NAME environment variables.
+// The OTLP exporter itself honours the OTEL_EXPORTER_OTLP_* variables.
+// If nothing has been set, tracing is disabled.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Exporter:    strings.ToLower(strings.TrimSpace(os.Getenv("OTEL_TRACES_EXPORTER"))),
+		ServiceName: os.Getenv("OTEL_SERVICE_NAME"),
+	}
+	if cfg.Exporter == "" {
+		cfg.Exporter = ExporterNone
+	}
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = defaultServiceName
+	}
+	return cfg
+}
+
+// Init installs a global tracer provider and the W3C trace context
+// propagator according to cfg. The returned function flushes and
+// stops the provider and must be called before the process exits.
+func Init(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout, "console":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, errors.Errorf("trace exporter '%s' not supported.", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create trace exporter")
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create trace resource")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer used for the service spans. It resolves
+// the global provider lazily, so spans follow whatever provider was
+// installed by Init or by tests.
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
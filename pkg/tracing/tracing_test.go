@@ -0,0 +1,56 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/tracing"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "")
+	t.Setenv("OTEL_SERVICE_NAME", "")
+
+	cfg := tracing.ConfigFromEnv()
+	assert.Equal(t, tracing.ExporterNone, cfg.Exporter)
+	assert.Equal(t, "decimal-to-roman-numerals", cfg.ServiceName)
+
+	t.Setenv("OTEL_TRACES_EXPORTER", " OTLP ")
+	t.Setenv("OTEL_SERVICE_NAME", "roman")
+
+	cfg = tracing.ConfigFromEnv()
+	assert.Equal(t, tracing.ExporterOTLP, cfg.Exporter)
+	assert.Equal(t, "roman", cfg.ServiceName)
+}
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		name      string
+		exporter  string
+		expectErr bool
+	}{
+		{name: "None", exporter: tracing.ExporterNone},
+		{name: "Empty", exporter: ""},
+		{name: "Stdout", exporter: tracing.ExporterStdout},
+		{name: "OTLP", exporter: tracing.ExporterOTLP},
+		{name: "Unsupported", exporter: "zipkin", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown, err := tracing.Init(context.Background(), tracing.Config{Exporter: tt.exporter})
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+
+	// The W3C propagator is always installed
+	assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+}
@@ -0,0 +1,52 @@
+package types
+
+// BatchPayload is the body of a batch request.
+type BatchPayload struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperation is an operation of a batch request: "convert" converts
+// Numbers, "range" converts the numbers from Min to Max, "parse" converts
+// Numeral to a number and "validate" checks Numeral.
+type BatchOperation struct {
+	Op      string `json:"op" example:"convert"`
+	Numbers []int  `json:"numbers,omitempty" example:"1,4,9"`
+	Min     int    `json:"min,omitempty" example:"10"`
+	Max     int    `json:"max,omitempty" example:"20"`
+	Numeral string `json:"numeral,omitempty" example:"MCMXCIV"`
+}
+
+// BatchResult is the result of a batch operation. Failed operations
+// have an Error and, for invalid numbers, InvalidNumbers.
+type BatchResult struct {
+	Index          int            `json:"index" example:"0"` // The position of the operation in the request.
+	Op             string         `json:"op" example:"convert"`
+	Results        []RomanNumeral `json:"results,omitempty"`
+	Valid          *bool          `json:"valid,omitempty"` // Whether the numeral is valid, for "validate".
+	Error          string         `json:"error,omitempty" example:"[ERR1019] invalid Roman numeral: expected a numeral between I and MMMCMXCIX in standard notation"`
+	InvalidNumbers []string       `json:"invalid_numbers,omitempty"`
+}
+
+// BatchResponse is the response of a batch request, with a result for
+// every operation in the order of the request.
+type BatchResponse struct {
+	Results []BatchResult `json:"results"`
+}
+
+// BatchItem is the v2 result of a batch operation. Failed operations
+// have Errors.
+type BatchItem struct {
+	Index   int            `json:"index" example:"0"`
+	Op      string         `json:"op" example:"convert"`
+	Results []RomanNumeral `json:"results,omitempty"`
+	Valid   *bool          `json:"valid,omitempty"`
+	Errors  []ErrorDetail  `json:"errors"`
+}
+
+// BatchEnvelope is the v2 response of a batch request. Data is null and
+// Errors is not empty when the whole batch failed.
+type BatchEnvelope struct {
+	Data   []BatchItem   `json:"data"`
+	Meta   Meta          `json:"meta"`
+	Errors []ErrorDetail `json:"errors"`
+}
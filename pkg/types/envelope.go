@@ -0,0 +1,45 @@
+package types
+
+// Meta describes a v2 response.
+type Meta struct {
+	Version   string `json:"version" example:"v2"`
+	Count     int    `json:"count" example:"3"`                 // The amount of results, or the amount a dry run would produce.
+	Requested int    `json:"requested,omitempty" example:"4"`   // The amount of numbers in the request, counting duplicates and overlaps.
+	Ranges    int    `json:"ranges,omitempty" example:"2"`      // The amount of ranges in the request.
+	DryRun    bool   `json:"dry_run,omitempty" example:"false"` // Set when the results were only counted.
+	// The time spent serving the request, in milliseconds.
+	DurationMs float64 `json:"duration_ms" example:"0.042"`
+}
+
+// ErrorDetail describes an error of a v2 response.
+type ErrorDetail struct {
+	Code    string `json:"code" example:"ERR1002"`
+	Message string `json:"message" example:"invalid input: please provide valid integers within the supported range (1-3999)"`
+	// Value is the invalid input, for ERR1002 and the invalid numbers of
+	// lenient requests. It may be empty, e.g. for "1,,2".
+	Value *string `json:"value,omitempty" example:"4000"`
+	// Path and Detail locate and describe a problem of the JSON body,
+	// for ERR1006, ERR1010 and ERR1013. Detail also lists the allowed
+	// query parameters, for ERR1000 and ERR1007.
+	Path   string `json:"path,omitempty" example:"$.ranges[0].min"`
+	Detail string `json:"detail,omitempty"`
+	// Limit and Requested describe an exceeded limit, for ERR1014,
+	// ERR1015 and ERR1016.
+	Limit     int `json:"limit,omitempty"`
+	Requested int `json:"requested,omitempty"`
+}
+
+// ConversionEnvelope is the v2 response of the conversion endpoints.
+// Data is null and Errors is not empty when the request failed.
+type ConversionEnvelope struct {
+	Data   []RomanNumeral `json:"data"`
+	Meta   Meta           `json:"meta"`
+	Errors []ErrorDetail  `json:"errors"`
+}
+
+// HealthEnvelope is the v2 response of the health endpoint.
+type HealthEnvelope struct {
+	Data   *HealthResponse `json:"data"`
+	Meta   Meta            `json:"meta"`
+	Errors []ErrorDetail   `json:"errors"`
+}
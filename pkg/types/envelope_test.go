@@ -0,0 +1,34 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversionEnvelope(t *testing.T) {
+	empty := ""
+	data, err := json.Marshal(ConversionEnvelope{
+		Meta:   Meta{Version: "v2", DurationMs: 0.5},
+		Errors: []ErrorDetail{{Code: "ERR1002", Message: "invalid input", Value: &empty}},
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"data": null,
+		"meta": {"version": "v2", "count": 0, "duration_ms": 0.5},
+		"errors": [{"code": "ERR1002", "message": "invalid input", "value": ""}]
+	}`, string(data))
+
+	expected := ConversionEnvelope{
+		Data:   []RomanNumeral{{Decimal: 4, Roman: "IV"}},
+		Meta:   Meta{Version: "v2", Count: 1, Requested: 2, Ranges: 1, DurationMs: 0.042},
+		Errors: []ErrorDetail{},
+	}
+	data, err = json.Marshal(expected)
+	assert.NoError(t, err)
+
+	var actual ConversionEnvelope
+	assert.NoError(t, json.Unmarshal(data, &actual))
+	assert.Equal(t, expected, actual)
+}
@@ -2,10 +2,36 @@ package types
 
 // NumberRange struct defines the model for a range of numbers
 type NumberRange struct {
-	Min int `json:"min" binding:"required" example:"10"` // The minimum value of the range (inclusive).
-	Max int `json:"max" binding:"required" example:"20"` // The maximum value of the range (inclusive).
+	Min     int   `json:"min" binding:"required" example:"10"` // The minimum value of the range (inclusive).
+	Max     int   `json:"max" binding:"required" example:"20"` // The maximum value of the range (inclusive).
+	Step    int   `json:"step,omitempty" example:"5"`          // The gap between the numbers of the range, from Min. Zero means 1.
+	Exclude []int `json:"exclude,omitempty" example:"15"`      // Numbers left out of the range.
 }
 
+// RangesPayload is the body of a request for ranges. Order is "asc" (the
+// default), "desc" or "input", the order of the ranges and of the numbers
+// within them. Numbers covered by several ranges are returned once unless
+// Dedupe is false.
 type RangesPayload struct {
 	Ranges []NumberRange `json:"ranges" binding:"required"`
+	Order  string        `json:"order,omitempty" example:"asc"`
+	Dedupe *bool         `json:"dedupe,omitempty" example:"true"`
+}
+
+// RangesCount describes the results a request for ranges would produce.
+type RangesCount struct {
+	DryRun   bool `json:"dry_run" example:"true"`
+	Ranges   int  `json:"ranges" example:"2"`   // The amount of ranges in the request.
+	Expanded int  `json:"expanded" example:"7"` // The amount of numbers covered by the ranges, counting overlaps once per range.
+	Results  int  `json:"count" example:"6"`    // The amount of results, unique unless duplicates are kept.
+	// Exceeded is the limit converting the ranges would exceed, if any.
+	Exceeded *LimitExceeded `json:"exceeded,omitempty"`
+}
+
+// LimitExceeded describes a limit a request exceeds, with the error
+// converting it fails with.
+type LimitExceeded struct {
+	Error     string `json:"error" example:"[ERR1016] invalid ranges: the ranges cover too many numbers in total"`
+	Limit     int    `json:"limit" example:"20000"`
+	Requested int    `json:"requested" example:"23994"`
 }
@@ -0,0 +1,222 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/roman"
+)
+
+// Numeral is a number between 1 and 3999 whose text form is its Roman
+// numeral. It can be used in JSON payloads, in SQL columns and as a
+// command-line flag:
+//
+//   - It marshals to JSON and text as a numeral, e.g. "XLII".
+//   - It unmarshals from a JSON integer, e.g. 42, or from a string holding
+//     a Roman numeral or a decimal number, e.g. "XLII" or "42".
+//   - It is stored in databases as an integer and scanned from integers,
+//     numerals and decimal strings.
+//
+// Numerals are parsed strictly: only canonical numerals in the standard
+// notation are accepted, in any case. Use LenientNumeral to also accept
+// non-canonical numerals such as IIII or IC.
+type Numeral int
+
+// LenientNumeral is a Numeral that also accepts non-canonical numerals,
+// e.g. IIII for 4, when it is unmarshalled, scanned or set as a flag.
+type LenientNumeral Numeral
+
+// ParseNumeral parses a Roman numeral, or a decimal number, within the
+// supported range. The options are those of roman.Parse, e.g.
+// roman.Lenient(). Numbers out of range return a *roman.RangeError and
+// invalid numerals a *roman.SyntaxError.
+func ParseNumeral(s string, opts ...roman.Option) (Numeral, error) {
+	s = strings.TrimSpace(s)
+	if isDecimal(s) {
+		number, err := strconv.Atoi(s)
+		if errors.Is(err, strconv.ErrRange) {
+			// Too many digits for an int
+			if s[0] == '-' {
+				return 0, &roman.RangeError{Number: roman.MinValue - 1}
+			}
+			return 0, &roman.RangeError{Number: roman.MaxValue + 1}
+		}
+		if err != nil {
+			return 0, &roman.SyntaxError{Numeral: s, Offset: -1, Msg: "invalid decimal number"}
+		}
+		if number < roman.MinValue || number > roman.MaxValue {
+			return 0, &roman.RangeError{Number: number}
+		}
+		return Numeral(number), nil
+	}
+	number, err := roman.Parse(s, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return Numeral(number), nil
+}
+
+// isDecimal reports whether s is a decimal number with at most one
+// leading sign.
+func isDecimal(s string) bool {
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// Int returns the number.
+func (n Numeral) Int() int {
+	return int(n)
+}
+
+// String implements fmt.Stringer, returning the numeral, or the decimal
+// number if it is out of range.
+func (n Numeral) String() string {
+	return roman.Numeral(n).String()
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns a
+// *roman.RangeError if the number is out of range.
+func (n Numeral) MarshalText() ([]byte, error) {
+	return roman.Numeral(n).MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (n *Numeral) UnmarshalText(text []byte) error {
+	return n.set(string(text))
+}
+
+// MarshalJSON implements json.Marshaler, writing the numeral as a string.
+func (n Numeral) MarshalJSON() ([]byte, error) {
+	text, err := n.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Like other types, null
+// leaves the numeral unchanged.
+func (n *Numeral) UnmarshalJSON(data []byte) error {
+	return n.unmarshalJSON(data)
+}
+
+// Value implements driver.Valuer, storing the number as an integer so
+// that columns sort numerically.
+func (n Numeral) Value() (driver.Value, error) {
+	if n < roman.MinValue || n > roman.MaxValue {
+		return nil, &roman.RangeError{Number: int(n)}
+	}
+	return int64(n), nil
+}
+
+// Scan implements sql.Scanner. Use a *Numeral with sql.Null* or a
+// pointer column to read NULL values.
+func (n *Numeral) Scan(src interface{}) error {
+	return n.scan(src)
+}
+
+// Set implements flag.Value.
+func (n *Numeral) Set(s string) error {
+	return n.set(s)
+}
+
+// String implements fmt.Stringer.
+func (n LenientNumeral) String() string {
+	return Numeral(n).String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (n LenientNumeral) MarshalText() ([]byte, error) {
+	return Numeral(n).MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (n *LenientNumeral) UnmarshalText(text []byte) error {
+	return (*Numeral)(n).set(string(text), roman.Lenient())
+}
+
+// MarshalJSON implements json.Marshaler.
+func (n LenientNumeral) MarshalJSON() ([]byte, error) {
+	return Numeral(n).MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *LenientNumeral) UnmarshalJSON(data []byte) error {
+	return (*Numeral)(n).unmarshalJSON(data, roman.Lenient())
+}
+
+// Value implements driver.Valuer.
+func (n LenientNumeral) Value() (driver.Value, error) {
+	return Numeral(n).Value()
+}
+
+// Scan implements sql.Scanner.
+func (n *LenientNumeral) Scan(src interface{}) error {
+	return (*Numeral)(n).scan(src, roman.Lenient())
+}
+
+// Set implements flag.Value.
+func (n *LenientNumeral) Set(s string) error {
+	return (*Numeral)(n).set(s, roman.Lenient())
+}
+
+// set parses s into n.
+func (n *Numeral) set(s string, opts ...roman.Option) error {
+	number, err := ParseNumeral(s, opts...)
+	if err != nil {
+		return err
+	}
+	*n = number
+	return nil
+}
+
+// unmarshalJSON parses a JSON integer or string into n.
+func (n *Numeral) unmarshalJSON(data []byte, opts ...roman.Option) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return n.set(s, opts...)
+	}
+
+	var number int
+	if err := json.Unmarshal(data, &number); err != nil {
+		return errors.Errorf("types: cannot unmarshal %s into a numeral: expected an integer or a string", data)
+	}
+	if number < roman.MinValue || number > roman.MaxValue {
+		return &roman.RangeError{Number: number}
+	}
+	*n = Numeral(number)
+	return nil
+}
+
+// scan reads an integer, a numeral or a decimal string into n.
+func (n *Numeral) scan(src interface{}, opts ...roman.Option) error {
+	switch value := src.(type) {
+	case int64:
+		if value < roman.MinValue || value > roman.MaxValue {
+			return &roman.RangeError{Number: int(value)}
+		}
+		*n = Numeral(value)
+		return nil
+	case string:
+		return n.set(value, opts...)
+	case []byte:
+		return n.set(string(value), opts...)
+	case nil:
+		return errors.New("types: cannot scan NULL into a numeral")
+	}
+	return errors.Errorf("types: cannot scan %T into a numeral", src)
+}
@@ -0,0 +1,206 @@
+package types_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/roman"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+var (
+	_ json.Marshaler           = types.Numeral(0)
+	_ json.Unmarshaler         = (*types.Numeral)(nil)
+	_ encoding.TextMarshaler   = types.Numeral(0)
+	_ encoding.TextUnmarshaler = (*types.Numeral)(nil)
+	_ sql.Scanner              = (*types.Numeral)(nil)
+	_ driver.Valuer            = types.Numeral(0)
+	_ flag.Value               = (*types.Numeral)(nil)
+	_ fmt.Stringer             = types.Numeral(0)
+
+	_ json.Unmarshaler = (*types.LenientNumeral)(nil)
+	_ sql.Scanner      = (*types.LenientNumeral)(nil)
+	_ flag.Value       = (*types.LenientNumeral)(nil)
+)
+
+func TestParseNumeral(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []roman.Option
+		expected types.Numeral
+		err      error
+	}{
+		{"XLII", nil, 42, nil},
+		{" mcmxciv ", nil, 1994, nil},
+		{"42", nil, 42, nil},
+		{"+3999", nil, 3999, nil},
+		{"IIII", nil, 0, roman.ErrSyntax},
+		{"IIII", []roman.Option{roman.Lenient()}, 4, nil},
+		{"ABC", []roman.Option{roman.Lenient()}, 0, roman.ErrSyntax},
+		{"", nil, 0, roman.ErrSyntax},
+		{"0", nil, 0, roman.ErrOutOfRange},
+		{"-1", nil, 0, roman.ErrOutOfRange},
+		{"4000", nil, 0, roman.ErrOutOfRange},
+		{"99999999999999999999", nil, 0, roman.ErrOutOfRange},
+		{"-99999999999999999999", nil, 0, roman.ErrOutOfRange},
+		{"+-5", nil, 0, roman.ErrSyntax},
+		{"--5", nil, 0, roman.ErrSyntax},
+		{"++5", nil, 0, roman.ErrSyntax},
+		{"-", nil, 0, roman.ErrSyntax},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			numeral, err := types.ParseNumeral(test.input, test.opts...)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, numeral)
+		})
+	}
+}
+
+func TestNumeralString(t *testing.T) {
+	assert.Equal(t, "XLII", types.Numeral(42).String())
+	assert.Equal(t, "XLII", fmt.Sprint(types.Numeral(42)))
+	assert.Equal(t, "0", types.Numeral(0).String(), "out of range numbers should be written as decimals")
+	assert.Equal(t, 42, types.Numeral(42).Int())
+}
+
+func TestNumeralJSON(t *testing.T) {
+	type payload struct {
+		Year  types.Numeral  `json:"year"`
+		Chest *types.Numeral `json:"chest,omitempty"`
+	}
+
+	data, err := json.Marshal(payload{Year: 1994})
+	require.NoError(t, err)
+	assert.Equal(t, `{"year":"MCMXCIV"}`, string(data))
+
+	_, err = json.Marshal(payload{Year: 4000})
+	assert.ErrorIs(t, err, roman.ErrOutOfRange)
+
+	tests := []struct {
+		data     string
+		expected types.Numeral
+	}{
+		{`{"year": 1994}`, 1994},
+		{`{"year": "MCMXCIV"}`, 1994},
+		{`{"year": "mcmxciv"}`, 1994},
+		{`{"year": "1994"}`, 1994},
+		{`{"year": null}`, 0},
+	}
+	for _, test := range tests {
+		var decoded payload
+		require.NoError(t, json.Unmarshal([]byte(test.data), &decoded), test.data)
+		assert.Equal(t, test.expected, decoded.Year, test.data)
+	}
+
+	invalid := []struct {
+		data string
+		err  error
+	}{
+		{`{"year": 0}`, roman.ErrOutOfRange},
+		{`{"year": 4000}`, roman.ErrOutOfRange},
+		{`{"year": "IIII"}`, roman.ErrSyntax},
+		{`{"year": "XLIIX"}`, roman.ErrSyntax},
+	}
+	for _, test := range invalid {
+		var decoded payload
+		assert.ErrorIs(t, json.Unmarshal([]byte(test.data), &decoded), test.err, test.data)
+	}
+	for _, data := range []string{`{"year": 4.5}`, `{"year": true}`, `{"year": ["X"]}`} {
+		var decoded payload
+		assert.Error(t, json.Unmarshal([]byte(data), &decoded), data)
+	}
+}
+
+func TestLenientNumeralJSON(t *testing.T) {
+	var decoded struct {
+		Numbers []types.LenientNumeral `json:"numbers"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"numbers": ["IIII", "IC", 7, "XLII"]}`), &decoded))
+	assert.Equal(t, []types.LenientNumeral{4, 99, 7, 42}, decoded.Numbers)
+
+	data, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, `{"numbers":["IV","XCIX","VII","XLII"]}`, string(data), "numerals should be marshalled canonically")
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"numbers": ["ABC"]}`), &decoded), roman.ErrSyntax)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"numbers": [5000]}`), &decoded), roman.ErrOutOfRange)
+}
+
+func TestNumeralText(t *testing.T) {
+	var decoded map[types.Numeral]string
+	require.NoError(t, json.Unmarshal([]byte(`{"XLII": "answer"}`), &decoded))
+	assert.Equal(t, map[types.Numeral]string{42: "answer"}, decoded)
+
+	text, err := types.Numeral(42).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "XLII", string(text))
+
+	var lenient types.LenientNumeral
+	require.NoError(t, lenient.UnmarshalText([]byte("VIIII")))
+	assert.Equal(t, types.LenientNumeral(9), lenient)
+}
+
+func TestNumeralSQL(t *testing.T) {
+	value, err := types.Numeral(42).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), value, "numerals should be stored as integers")
+
+	_, err = types.Numeral(0).Value()
+	assert.ErrorIs(t, err, roman.ErrOutOfRange)
+
+	tests := []struct {
+		src      interface{}
+		expected types.Numeral
+	}{
+		{int64(42), 42},
+		{"XLII", 42},
+		{[]byte("xlii"), 42},
+		{"42", 42},
+	}
+	for _, test := range tests {
+		var n types.Numeral
+		require.NoError(t, n.Scan(test.src), "%v", test.src)
+		assert.Equal(t, test.expected, n, "%v", test.src)
+	}
+
+	var n types.Numeral
+	assert.ErrorIs(t, n.Scan(int64(4000)), roman.ErrOutOfRange)
+	assert.ErrorIs(t, n.Scan("IIII"), roman.ErrSyntax)
+	assert.EqualError(t, n.Scan(nil), "types: cannot scan NULL into a numeral")
+	assert.EqualError(t, n.Scan(4.2), "types: cannot scan float64 into a numeral")
+
+	var lenient types.LenientNumeral
+	require.NoError(t, lenient.Scan("IIII"))
+	assert.Equal(t, types.LenientNumeral(4), lenient)
+}
+
+func TestNumeralFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	year := types.Numeral(2024)
+	var chapter types.LenientNumeral
+	fs.Var(&year, "year", "year")
+	fs.Var(&chapter, "chapter", "chapter")
+
+	require.NoError(t, fs.Parse([]string{"-year", "MCMXCIV", "-chapter", "IIII"}))
+	assert.Equal(t, types.Numeral(1994), year)
+	assert.Equal(t, types.LenientNumeral(4), chapter)
+	assert.Equal(t, "MCMXCIV", fs.Lookup("year").Value.String())
+
+	assert.Error(t, fs.Parse([]string{"-year", "IIII"}))
+}
@@ -11,6 +11,21 @@ type RomanNumeralResponse struct {
 	Results []RomanNumeral `json:"results"`
 }
 
+// InvalidNumber is an invalid input of a lenient request, with the
+// AppError code of the reason.
+type InvalidNumber struct {
+	Value   string `json:"value" example:"abc"`
+	Code    string `json:"code" example:"ERR1024"`
+	Message string `json:"message" example:"invalid number: expected an integer"`
+}
+
+// LenientResponse represents the response of a lenient request: the Roman
+// numerals of the valid numbers, and the invalid numbers if any.
+type LenientResponse struct {
+	Results        []RomanNumeral  `json:"results"`
+	InvalidNumbers []InvalidNumber `json:"invalid_numbers,omitempty"`
+}
+
 // ErrorResponse represents an error response with an error message and optional invalid numbers.
 type ErrorResponse struct {
 	Error          string   `json:"error" example:"[ERR1002] invalid input: please provide valid integers within the supported range (1-3999)"`
@@ -24,6 +24,27 @@ func TestRomanNumeralResponse(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestLenientResponse(t *testing.T) {
+	expected := LenientResponse{
+		Results:        []RomanNumeral{{Decimal: 4, Roman: "IV"}},
+		InvalidNumbers: []InvalidNumber{{Value: "abc", Code: "ERR1024", Message: "invalid number: expected an integer"}},
+	}
+
+	data, err := json.Marshal(expected)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"results":[{"number":4,"roman":"IV"}],"invalid_numbers":[{"value":"abc","code":"ERR1024","message":"invalid number: expected an integer"}]}`, string(data))
+
+	var actual LenientResponse
+	err = json.Unmarshal(data, &actual)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	// Without invalid numbers, the response is the one of strict requests
+	data, err = json.Marshal(LenientResponse{Results: expected.Results})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"results":[{"number":4,"roman":"IV"}]}`, string(data))
+}
+
 func TestErrorResponse(t *testing.T) {
 	expected := ErrorResponse{
 		Error:          "[ERR1002] invalid input: please provide valid integers within the supported range (1-3999)",
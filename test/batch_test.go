@@ -0,0 +1,100 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+// mixedBatch mixes every operation, with failing ones in between.
+const mixedBatch = `{"operations":[
+	{"op":"convert","numbers":[3,1,1]},
+	{"op":"convert","numbers":[1,4000,0]},
+	{"op":"range","min":1,"max":3},
+	{"op":"range","min":5,"max":4},
+	{"op":"parse","numeral":"MCMXCIV"},
+	{"op":"parse","numeral":"MMMM"},
+	{"op":"validate","numeral":"XLII"},
+	{"op":"validate","numeral":"IL"}
+]}`
+
+func TestBatch(t *testing.T) {
+	router := SetupRouter()
+
+	w := performVersionedRequest(router, http.MethodPost, "/api/v1/batch", mixedBatch, "")
+	checkStatus(t, w, http.StatusOK)
+	assert.NotEmpty(t, w.Header().Get("Deprecation"))
+
+	var response types.BatchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Results, 8)
+	for i, result := range response.Results {
+		assert.Equal(t, i, result.Index)
+	}
+
+	// Every operation has its results or its error
+	assert.Equal(t, []types.RomanNumeral{{Decimal: 1, Roman: "I"}, {Decimal: 3, Roman: "III"}}, response.Results[0].Results)
+	assert.Equal(t, "[ERR1002] invalid input: please provide valid integers within the supported range (1-3999)", response.Results[1].Error)
+	assert.Equal(t, []string{"4000", "0"}, response.Results[1].InvalidNumbers)
+	assert.Len(t, response.Results[2].Results, 3)
+	assert.Contains(t, response.Results[3].Error, "ERR1008")
+	assert.Equal(t, []types.RomanNumeral{{Decimal: 1994, Roman: "MCMXCIV"}}, response.Results[4].Results)
+	assert.Contains(t, response.Results[5].Error, "ERR1019")
+	require.NotNil(t, response.Results[6].Valid)
+	assert.True(t, *response.Results[6].Valid)
+	assert.Equal(t, []types.RomanNumeral{{Decimal: 42, Roman: "XLII"}}, response.Results[6].Results)
+	require.NotNil(t, response.Results[7].Valid)
+	assert.False(t, *response.Results[7].Valid)
+	assert.Empty(t, response.Results[7].Error, "invalid numerals are a result of validate")
+}
+
+func TestBatchV2(t *testing.T) {
+	router := SetupRouter()
+
+	for _, target := range []string{"/api/v2/batch", "/api/batch"} {
+		w := performVersionedRequest(router, http.MethodPost, target, mixedBatch, "v2")
+		checkStatus(t, w, http.StatusOK)
+
+		var envelope types.BatchEnvelope
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope), target)
+		assert.Len(t, envelope.Data, 8)
+		assert.Empty(t, envelope.Errors)
+		assert.Equal(t, 8, envelope.Meta.Count)
+		assert.Equal(t, 6, envelope.Meta.Requested, "the numbers of the successful operations are requested")
+		assert.Equal(t, 1, envelope.Meta.Ranges)
+		require.Len(t, envelope.Data[1].Errors, 2)
+		assert.Equal(t, "4000", *envelope.Data[1].Errors[0].Value)
+		assert.Empty(t, envelope.Data[4].Errors)
+	}
+}
+
+func TestBatchErrors(t *testing.T) {
+	router := SetupRouter()
+
+	w := performVersionedRequest(router, http.MethodPost, "/api/v1/batch", `{"operations":[{"op":"convert","numbers":[1]}],"ranges":[]}`, "")
+	checkStatus(t, w, http.StatusBadRequest)
+	assert.Contains(t, w.Body.String(), "ERR1021")
+
+	w = performVersionedRequest(router, http.MethodPost, "/api/v1/batch", `{"operations":[{"op":"parse","numeral":"I","numeral":"II"}]}`, "")
+	checkStatus(t, w, http.StatusBadRequest)
+	assert.Contains(t, w.Body.String(), `"$.operations[0].numeral"`)
+
+	// The limits are shared by the operations
+	operations := strings.Repeat(`{"op":"range","min":1,"max":3999},`, 5) + `{"op":"convert","numbers":[1,2,3,4,5,6]}`
+	w = performVersionedRequest(router, http.MethodPost, "/api/v1/batch", `{"operations":[`+operations+`]}`, "")
+	checkStatus(t, w, http.StatusBadRequest)
+	assert.JSONEq(t, `{"error":"[ERR1016] invalid ranges: the ranges cover too many numbers in total","limit":20000,"requested":20001}`, w.Body.String())
+
+	operations = strings.Repeat(`{"op":"parse","numeral":"I"},`, 100) + `{"op":"parse","numeral":"I"}`
+	w = performVersionedRequest(router, http.MethodPost, "/api/v2/batch", `{"operations":[`+operations+`]}`, "")
+	checkStatus(t, w, http.StatusBadRequest)
+	envelope := decodeEnvelope(t, w)
+	require.Len(t, envelope.Errors, 1)
+	assert.Equal(t, types.ErrorDetail{Code: "ERR1022", Message: "invalid batch: too many operations in the request", Limit: 100, Requested: 101}, envelope.Errors[0])
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the golden files with the current responses. v1 is
+// frozen: only use it to add new cases, never to accept changed ones.
+var update = flag.Bool("update", false, "rewrite the golden files of the v1 responses")
+
+// v1GoldenCases cover every kind of v1 response body.
+var v1GoldenCases = []struct {
+	name   string
+	method string
+	target string
+	body   string
+}{
+	{"health", http.MethodGet, "/api/v1/health", ""},
+	{"convert_numbers", http.MethodGet, "/api/v1/convert?numbers=1994,4,+04&numbers=3999", ""},
+	{"convert_invalid_numbers", http.MethodGet, "/api/v1/convert?numbers=1,4000,abc,,-1", ""},
+	{"convert_missing_numbers", http.MethodGet, "/api/v1/convert", ""},
+	{"convert_unknown_param", http.MethodGet, "/api/v1/convert?numbers=1&format=xml", ""},
+	{"convert_lenient", http.MethodGet, "/api/v1/convert?numbers=1,abc,,4000&mode=lenient", ""},
+	{"ranges", http.MethodPost, "/api/v1/convert", `{"ranges":[{"min":3,"max":4},{"min":2,"max":5}]}`},
+	{"ranges_dry_run", http.MethodPost, "/api/v1/convert?dry_run=true", `{"ranges":[{"min":3,"max":4},{"min":2,"max":5}]}`},
+	{"ranges_duplicate_keys", http.MethodPost, "/api/v1/convert", `{"ranges":[{"min":1,"min":2,"max":3}]}`},
+	{"ranges_malformed", http.MethodPost, "/api/v1/convert", `{"ranges":[{"min":1,"max":2}`},
+	{"ranges_missing_max", http.MethodPost, "/api/v1/convert", `{"ranges":[{"min":1}]}`},
+	{"ranges_reversed", http.MethodPost, "/api/v1/convert", `{"ranges":[{"min":10,"max":1}]}`},
+	{"ranges_out_of_bounds", http.MethodPost, "/api/v1/convert", `{"ranges":[{"min":0,"max":10}]}`},
+	{"ranges_too_many_numbers", http.MethodPost, "/api/v1/convert", `{"ranges":[` + strings.Repeat(`{"min":1,"max":3999},`, 5) + `{"min":1,"max":3999}]}`},
+	{"ranges_unknown_param", http.MethodPost, "/api/v1/convert?format=xml", `{"ranges":[{"min":1,"max":2}]}`},
+	{"batch", http.MethodPost, "/api/v1/batch", `{"operations":[{"op":"convert","numbers":[4,1,4000]},{"op":"range","min":1,"max":2},{"op":"parse","numeral":"IC"},{"op":"validate","numeral":"IX"}]}`},
+	{"batch_missing_operations", http.MethodPost, "/api/v1/batch", `{"ranges":[{"min":1,"max":2}]}`},
+}
+
+// TestV1GoldenResponses guarantees that the v1 responses never change:
+// the status, content type and body bytes must match the golden files.
+func TestV1GoldenResponses(t *testing.T) {
+	router := SetupRouter()
+
+	for _, tc := range v1GoldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			router.ServeHTTP(w, req)
+
+			actual := fmt.Sprintf("%d %s\n%s", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+			path := filepath.Join("testdata", "v1", tc.name+".golden")
+			if *update {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, []byte(actual), 0o644))
+			}
+
+			golden, err := os.ReadFile(path)
+			require.NoError(t, err, "run the tests with -update to create the golden file")
+			assert.Equal(t, string(golden), actual, "the v1 response changed")
+			assert.NotEmpty(t, w.Header().Get("Deprecation"), "v1 responses should be deprecated")
+		})
+	}
+}
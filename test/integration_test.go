@@ -4,9 +4,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
 	"testing"
 	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
 )
 
 // Helper function to check the response status code
@@ -164,6 +170,28 @@ func TestConvertHandlerInvalid(t *testing.T) {
 	}
 }
 
+// Test cases for invalid inputs for GET /api/v1/convert in lenient mode
+func TestConvertHandlerLenient(t *testing.T) {
+	router := SetupRouter()
+	testCases := map[string]string{
+		"abc": "ERR1024", "-1": "ERR1003", "4000": "ERR1003", "+0": "ERR1003", "%1": "ERR1024", "1+2": "ERR1024", "": "ERR1025",
+	}
+
+	for tc, code := range testCases {
+		t.Run("Lenient_"+tc, func(t *testing.T) {
+			w := performRequest(router, BasePath+"?mode=lenient&numbers=9,"+url.QueryEscape(tc))
+			checkStatus(t, w, http.StatusOK)
+
+			var response types.LenientResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, []types.RomanNumeral{{Decimal: 9, Roman: "IX"}}, response.Results)
+			require.Len(t, response.InvalidNumbers, 1)
+			assert.Equal(t, tc, response.InvalidNumbers[0].Value)
+			assert.Equal(t, code, response.InvalidNumbers[0].Code)
+		})
+	}
+}
+
 // Test cases for edge cases for GET /api/v1/convert
 func TestConvertHandlerEdgeCases(t *testing.T) {
 	router := SetupRouter()
@@ -225,6 +253,39 @@ func TestConvertRangesHandlerInvalid(t *testing.T) {
 	}
 }
 
+// Test cases for the options of POST /api/v1/convert
+func TestConvertRangesHandlerOptions(t *testing.T) {
+	router := SetupRouter()
+	keep := false
+	testCases := []struct {
+		name     string
+		payload  types.RangesPayload
+		expected []uint
+	}{
+		{"Step", types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 20, Step: 5}}}, []uint{1, 6, 11, 16}},
+		{"Exclude", types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 5, Exclude: []int{1, 3}}}}, []uint{2, 4, 5}},
+		{"Desc", types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 3}}, Order: "desc"}, []uint{3, 2, 1}},
+		{"Input", types.RangesPayload{Ranges: []types.NumberRange{{Min: 7, Max: 8}, {Min: 1, Max: 2}}, Order: "input"}, []uint{7, 8, 1, 2}},
+		{"KeepDuplicates", types.RangesPayload{Ranges: []types.NumberRange{{Min: 1, Max: 2}, {Min: 2, Max: 3}}, Dedupe: &keep}, []uint{1, 2, 2, 3}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := performPostRequest(router, BasePath, tc.payload)
+			checkStatus(t, w, http.StatusOK)
+
+			var response types.RomanNumeralResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			numbers := make([]uint, len(response.Results))
+			for i, result := range response.Results {
+				numbers[i] = result.Decimal
+				assert.Equal(t, intToRoman(int(result.Decimal)), result.Roman)
+			}
+			assert.Equal(t, tc.expected, numbers)
+		})
+	}
+}
+
 // Test cases for edge cases for POST /convert endpoint
 func TestConvertRangesHandlerEdgeCases(t *testing.T) {
 	router := SetupRouter()
@@ -3,11 +3,15 @@ package test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api"
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/api/openapi"
 )
 
 // Constants for API version and base path
@@ -16,9 +20,31 @@ const (
 	BasePath   = APIVersion + "/convert"
 )
 
-// SetupLoadRouter sets up the Gin router for testing
+// validator checks every request and response of the tests against
+// the OpenAPI document
+var validator = openapi.NewValidator(openapi.Spec(), "/metrics", "/swagger/*any")
+
+// TestMain fails the tests if the API drifted from the OpenAPI document
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if violations := validator.Violations(); len(violations) > 0 {
+		fmt.Fprintf(os.Stderr, "%d requests or responses do not match the OpenAPI document:\n", len(violations))
+		for i, violation := range violations {
+			if i == 20 {
+				fmt.Fprintf(os.Stderr, "  ... and %d more\n", len(violations)-i)
+				break
+			}
+			fmt.Fprintf(os.Stderr, "  %v\n", violation)
+		}
+		code = 1
+	}
+	os.Exit(code)
+}
+
+// SetupLoadRouter sets up the Gin router for testing, validating the
+// traffic against the OpenAPI document
 func SetupRouter() *gin.Engine {
-	return api.InitRouter()
+	return api.NewRouter(api.Options{Middleware: []gin.HandlerFunc{validator.Handler}})
 }
 
 // Helper function to perform a POST request and return the response recorder
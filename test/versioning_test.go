@@ -0,0 +1,218 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrtyormaa/decimal-to-roman-numerals/pkg/types"
+)
+
+// performVersionedRequest sends a request with the Accept-Version header,
+// unless version is empty
+func performVersionedRequest(router *gin.Engine, method, url, body, version string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	if version != "" {
+		req.Header.Set("Accept-Version", version)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func decodeEnvelope(t *testing.T, w *httptest.ResponseRecorder) types.ConversionEnvelope {
+	var envelope types.ConversionEnvelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Equal(t, "v2", envelope.Meta.Version)
+	assert.GreaterOrEqual(t, envelope.Meta.DurationMs, 0.0)
+	envelope.Meta.DurationMs = 0
+	return envelope
+}
+
+func TestV2ConvertNumbers(t *testing.T) {
+	router := SetupRouter()
+
+	w := performRequest(router, "/api/v2/convert?numbers=3,1,1")
+	checkStatus(t, w, http.StatusOK)
+	assert.Empty(t, w.Header().Get("Deprecation"), "v2 is not deprecated")
+	assert.Equal(t, types.ConversionEnvelope{
+		Data:   []types.RomanNumeral{{Decimal: 1, Roman: "I"}, {Decimal: 3, Roman: "III"}},
+		Meta:   types.Meta{Version: "v2", Count: 2, Requested: 3},
+		Errors: []types.ErrorDetail{},
+	}, decodeEnvelope(t, w))
+
+	// v2 has its own weak ETag
+	etag := w.Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `W/"`), etag)
+	assert.NotEqual(t, performRequest(router, "/api/v1/convert?numbers=3,1,1").Header().Get("ETag"), etag)
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/convert?numbers=1,3", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	checkStatus(t, w, http.StatusNotModified)
+
+	// Every invalid number is reported
+	w = performRequest(router, "/api/v2/convert?numbers=1,4000,,abc")
+	checkStatus(t, w, http.StatusBadRequest)
+	envelope := decodeEnvelope(t, w)
+	assert.Nil(t, envelope.Data)
+	require.Len(t, envelope.Errors, 3)
+	for i, value := range []string{"4000", "", "abc"} {
+		assert.Equal(t, "ERR1002", envelope.Errors[i].Code)
+		require.NotNil(t, envelope.Errors[i].Value)
+		assert.Equal(t, value, *envelope.Errors[i].Value)
+	}
+
+	w = performRequest(router, "/api/v2/convert")
+	checkStatus(t, w, http.StatusBadRequest)
+	assert.Equal(t, []types.ErrorDetail{{Code: "ERR1001", Message: "'numbers' query parameter is required"}}, decodeEnvelope(t, w).Errors)
+
+	// The message of v1 is kept, and the detail lists the parameters
+	w = performRequest(router, "/api/v2/convert?numbers=1&format=xml")
+	checkStatus(t, w, http.StatusBadRequest)
+	assert.Equal(t, []types.ErrorDetail{{
+		Code:    "ERR1000",
+		Message: "only 'numbers' query parameter is allowed",
+		Detail:  "the allowed query parameters are 'numbers' and 'mode'",
+	}}, decodeEnvelope(t, w).Errors)
+}
+
+func TestV2ConvertRanges(t *testing.T) {
+	router := SetupRouter()
+	ranges := `{"ranges":[{"min":3,"max":4},{"min":2,"max":5}]}`
+
+	w := performVersionedRequest(router, http.MethodPost, "/api/v2/convert", ranges, "")
+	checkStatus(t, w, http.StatusOK)
+	envelope := decodeEnvelope(t, w)
+	assert.Len(t, envelope.Data, 4)
+	assert.Equal(t, types.Meta{Version: "v2", Count: 4, Requested: 6, Ranges: 2}, envelope.Meta)
+
+	w = performVersionedRequest(router, http.MethodPost, "/api/v2/convert?dry_run=true", ranges, "")
+	checkStatus(t, w, http.StatusOK)
+	envelope = decodeEnvelope(t, w)
+	assert.Equal(t, []types.RomanNumeral{}, envelope.Data)
+	assert.Equal(t, types.Meta{Version: "v2", Count: 4, Requested: 6, Ranges: 2, DryRun: true}, envelope.Meta)
+
+	// JSON problems are reported one by one
+	w = performVersionedRequest(router, http.MethodPost, "/api/v2/convert", `{"ranges":[{"min":1,"min":2,"max":3,"max":4}]}`, "")
+	checkStatus(t, w, http.StatusBadRequest)
+	envelope = decodeEnvelope(t, w)
+	require.Len(t, envelope.Errors, 2)
+	assert.Equal(t, "ERR1006", envelope.Errors[0].Code)
+	assert.Equal(t, "$.ranges[0].min", envelope.Errors[0].Path)
+	assert.Equal(t, "$.ranges[0].max", envelope.Errors[1].Path)
+
+	// Exceeded limits are reported with the limit
+	w = performVersionedRequest(router, http.MethodPost, "/api/v2/convert", `{"ranges":[`+strings.Repeat(`{"min":1,"max":3999},`, 5)+`{"min":1,"max":3999}]}`, "")
+	checkStatus(t, w, http.StatusBadRequest)
+	envelope = decodeEnvelope(t, w)
+	require.Len(t, envelope.Errors, 1)
+	assert.Equal(t, "ERR1016", envelope.Errors[0].Code)
+	assert.Equal(t, 3999*6, envelope.Errors[0].Requested)
+	assert.Positive(t, envelope.Errors[0].Limit)
+
+	// Dry runs report them next to the count
+	w = performVersionedRequest(router, http.MethodPost, "/api/v2/convert?count_only=true", `{"ranges":[`+strings.Repeat(`{"min":1,"max":3999},`, 5)+`{"min":1,"max":3999}]}`, "")
+	checkStatus(t, w, http.StatusOK)
+	envelope = decodeEnvelope(t, w)
+	assert.Equal(t, types.Meta{Version: "v2", Count: 3999, Requested: 3999 * 6, Ranges: 6, DryRun: true}, envelope.Meta)
+	require.Len(t, envelope.Errors, 1)
+	assert.Equal(t, "ERR1016", envelope.Errors[0].Code)
+	assert.Equal(t, 3999*6, envelope.Errors[0].Requested)
+
+	// The message of v1 is kept, and the detail lists the parameters
+	w = performVersionedRequest(router, http.MethodPost, "/api/v2/convert?format=xml", ranges, "")
+	checkStatus(t, w, http.StatusBadRequest)
+	assert.Equal(t, []types.ErrorDetail{{
+		Code:    "ERR1007",
+		Message: "invalid request: only the 'dry_run' query parameter is allowed in POST requests",
+		Detail:  "the allowed query parameters are 'dry_run' and 'count_only'",
+	}}, decodeEnvelope(t, w).Errors)
+}
+
+func TestV2Health(t *testing.T) {
+	router := SetupRouter()
+
+	w := performRequest(router, "/api/v2/health")
+	checkStatus(t, w, http.StatusOK)
+	var envelope types.HealthEnvelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	require.NotNil(t, envelope.Data)
+	assert.Equal(t, "success", envelope.Data.Status)
+	assert.Equal(t, "v2", envelope.Meta.Version)
+	assert.Empty(t, envelope.Errors)
+}
+
+func TestV1Deprecation(t *testing.T) {
+	router := SetupRouter()
+
+	w := performRequest(router, "/api/v1/convert?numbers=1")
+	checkStatus(t, w, http.StatusOK)
+	assert.Regexp(t, `^@\d+$`, w.Header().Get("Deprecation"))
+	assert.NotEmpty(t, w.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v2/convert>; rel="successor-version"`, w.Header().Get("Link"))
+
+	// Errors are deprecated too
+	w = performRequest(router, "/api/v1/convert")
+	checkStatus(t, w, http.StatusBadRequest)
+	assert.NotEmpty(t, w.Header().Get("Deprecation"))
+
+	// Health probes outside of /api are not versioned
+	assert.Empty(t, performRequest(router, "/health").Header().Get("Deprecation"))
+}
+
+func TestAcceptVersion(t *testing.T) {
+	router := SetupRouter()
+
+	for _, tc := range []struct {
+		name       string
+		version    string
+		expected   string
+		deprecated bool
+	}{
+		{"Default", "", "v1", true},
+		{"V1", "v1", "v1", true},
+		{"V2", "v2", "v2", false},
+		{"Number", "2", "v2", false},
+		{"Uppercase", "V2", "v2", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := performVersionedRequest(router, http.MethodGet, "/api/convert?numbers=4", "", tc.version)
+			checkStatus(t, w, http.StatusOK)
+			assert.Equal(t, tc.expected, w.Header().Get("API-Version"))
+			assert.Contains(t, w.Header().Values("Vary"), "Accept-Version")
+			assert.Equal(t, tc.deprecated, w.Header().Get("Deprecation") != "")
+
+			// The body is the one of the path of the version
+			expected := performRequest(router, "/api/"+tc.expected+"/convert?numbers=4")
+			if tc.expected == "v1" {
+				assert.Equal(t, expected.Body.String(), w.Body.String())
+			} else {
+				assert.Equal(t, decodeEnvelope(t, expected), decodeEnvelope(t, w))
+			}
+		})
+	}
+
+	w := performVersionedRequest(router, http.MethodPost, "/api/convert", `{"ranges":[{"min":1,"max":2}]}`, "v2")
+	checkStatus(t, w, http.StatusOK)
+	assert.Len(t, decodeEnvelope(t, w).Data, 2)
+
+	w = performVersionedRequest(router, http.MethodGet, "/api/health", "", "v2")
+	checkStatus(t, w, http.StatusOK)
+	assert.Contains(t, w.Body.String(), `"version":"v2"`)
+
+	// The path wins over the header
+	w = performVersionedRequest(router, http.MethodGet, "/api/v1/convert?numbers=4", "", "v2")
+	assert.JSONEq(t, `{"results":[{"number":4,"roman":"IV"}]}`, w.Body.String())
+
+	// Unsupported versions are rejected
+	w = performVersionedRequest(router, http.MethodGet, "/api/convert?numbers=4", "", "v3")
+	checkStatus(t, w, http.StatusNotAcceptable)
+	assert.JSONEq(t, `{"error":"[ERR1020] unsupported API version: the Accept-Version header must be v1 or v2"}`, w.Body.String())
+}